@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/plugin"
 )
 
 var listOpts struct {
@@ -55,6 +56,12 @@ func init() {
 }
 
 func listJobs(cmd *cobra.Command, args []string) error {
+	plugins, err := loadPlugins(listOpts.configPath)
+	if err != nil {
+		return err
+	}
+	defer plugin.CloseAll(plugins)
+
 	cfg, err := config.Load(listOpts.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -86,6 +93,12 @@ func listJobs(cmd *cobra.Command, args []string) error {
 }
 
 func listEnvironments(cmd *cobra.Command, args []string) error {
+	plugins, err := loadPlugins(listOpts.configPath)
+	if err != nil {
+		return err
+	}
+	defer plugin.CloseAll(plugins)
+
 	cfg, err := config.Load(listOpts.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
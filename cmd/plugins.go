@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/user/jobprobe/internal/plugin"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// pluginDirs are extra directories searched for provider plugins.
+var pluginDirs []string
+
+func init() {
+	rootCmd.PersistentFlags().StringSliceVar(&pluginDirs, "plugin-dir", nil,
+		"Directory to search for jprobe-provider-* plugins (repeatable)")
+}
+
+// loadPlugins registers the provider plugins found in --plugin-dir, in the
+// plugins directory of the config and on PATH, in that order of precedence.
+// The returned plugins must be closed with plugin.CloseAll.
+func loadPlugins(configPath string) ([]*plugin.Provider, error) {
+	plugin.HostVersion = Version
+
+	configDir := configPath
+	if info, err := os.Stat(configPath); err == nil && !info.IsDir() {
+		configDir = filepath.Dir(configPath)
+	}
+	dirs := append(append([]string{}, pluginDirs...), filepath.Join(configDir, "plugins"))
+
+	paths, err := plugin.Discover(dirs, true)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded, err := plugin.Load(providers.DefaultRegistry, paths)
+	if err != nil {
+		plugin.CloseAll(loaded)
+		return nil, fmt.Errorf("failed to load plugins: %w", err)
+	}
+	return loaded, nil
+}
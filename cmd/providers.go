@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/user/jobprobe/internal/plugin"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+var providersOpts struct {
+	configPath string
+	outputFmt  string
+}
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "List available providers and their capabilities",
+	Long: `List the built-in providers and any provider plugins found, with the
+capabilities each one supports.
+
+Examples:
+  # List providers
+  jprobe providers
+
+  # Include plugins from a config directory
+  jprobe providers --config /path/to/configs/
+
+  # List providers as JSON
+  jprobe providers --output json`,
+	RunE: listProviders,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+
+	providersCmd.Flags().StringVarP(&providersOpts.configPath, "config", "c", ".", "Config directory or file path, used to find plugins")
+	providersCmd.Flags().StringVarP(&providersOpts.outputFmt, "output", "o", "table", "Output format (table, json)")
+}
+
+// providerInfo describes a registered provider.
+type providerInfo struct {
+	Name         string                 `json:"name"`
+	Source       string                 `json:"source"`
+	Capabilities providers.Capabilities `json:"capabilities"`
+}
+
+func listProviders(cmd *cobra.Command, args []string) error {
+	plugins, err := loadPlugins(providersOpts.configPath)
+	if err != nil {
+		return err
+	}
+	defer plugin.CloseAll(plugins)
+
+	names := providers.DefaultRegistry.List()
+	sort.Strings(names)
+
+	infos := make([]providerInfo, 0, len(names))
+	for _, name := range names {
+		p, err := providers.Get(name)
+		if err != nil {
+			return err
+		}
+		source := "built-in"
+		if pp, ok := p.(*plugin.Provider); ok {
+			source = pp.Path()
+		}
+		infos = append(infos, providerInfo{
+			Name:         name,
+			Source:       source,
+			Capabilities: providers.CapabilitiesOf(p),
+		})
+	}
+
+	if providersOpts.outputFmt == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPOLLING\tABORT\tLOGS\tDRY-RUN\tSOURCE")
+	fmt.Fprintln(w, "----\t-------\t-----\t----\t-------\t------")
+
+	for _, info := range infos {
+		c := info.Capabilities
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", info.Name,
+			yesNo(c.Polling), yesNo(c.Abort), yesNo(c.Logs), yesNo(c.DryRun), info.Source)
+	}
+
+	w.Flush()
+	fmt.Printf("\nTotal: %d providers\n", len(infos))
+
+	return nil
+}
+
+// yesNo formats a capability flag for the table.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "-"
+}
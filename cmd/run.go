@@ -12,11 +12,29 @@ import (
 
 	"github.com/user/jobprobe/internal/config"
 	"github.com/user/jobprobe/internal/output"
+	"github.com/user/jobprobe/internal/plugin"
+	"github.com/user/jobprobe/internal/providers"
+	"github.com/user/jobprobe/internal/providers/fixture"
 	"github.com/user/jobprobe/internal/runner"
 
 	// Register providers
+	_ "github.com/user/jobprobe/internal/providers/airflow"
+	_ "github.com/user/jobprobe/internal/providers/amqp"
+	_ "github.com/user/jobprobe/internal/providers/dns"
+	_ "github.com/user/jobprobe/internal/providers/exec"
+	_ "github.com/user/jobprobe/internal/providers/githubactions"
+	_ "github.com/user/jobprobe/internal/providers/gitlabci"
+	_ "github.com/user/jobprobe/internal/providers/graphql"
+	_ "github.com/user/jobprobe/internal/providers/grpc"
 	_ "github.com/user/jobprobe/internal/providers/http"
+	_ "github.com/user/jobprobe/internal/providers/jenkins"
+	_ "github.com/user/jobprobe/internal/providers/kafka"
+	_ "github.com/user/jobprobe/internal/providers/kubernetes"
+	_ "github.com/user/jobprobe/internal/providers/prometheus"
+	_ "github.com/user/jobprobe/internal/providers/redis"
 	_ "github.com/user/jobprobe/internal/providers/rundeck"
+	_ "github.com/user/jobprobe/internal/providers/socket"
+	_ "github.com/user/jobprobe/internal/providers/sql"
 )
 
 var runOpts struct {
@@ -28,6 +46,8 @@ var runOpts struct {
 	pretty      bool
 	dryRun      bool
 	verbose     bool
+	record      string
+	replay      string
 }
 
 var runCmd = &cobra.Command{
@@ -49,7 +69,11 @@ Examples:
   jprobe run --tags critical,database
 
   # Run with JSON output
-  jprobe run --output json --pretty`,
+  jprobe run --output json --pretty
+
+  # Record HTTP traffic, then run again offline against the recording
+  jprobe run --name db-backup-mysql --record ./fixtures
+  jprobe run --name db-backup-mysql --replay ./fixtures`,
 	RunE: runJobs,
 }
 
@@ -62,16 +86,30 @@ func init() {
 	runCmd.Flags().StringVarP(&runOpts.environment, "env", "e", "", "Run jobs for specific environment")
 	runCmd.Flags().StringVarP(&runOpts.outputFmt, "output", "o", "console", "Output format (console, json)")
 	runCmd.Flags().BoolVar(&runOpts.pretty, "pretty", false, "Pretty print JSON output")
-	runCmd.Flags().BoolVar(&runOpts.dryRun, "dry-run", false, "Show what would run without executing")
+	runCmd.Flags().BoolVar(&runOpts.dryRun, "dry-run", false, "Check each job's target without running it")
 	runCmd.Flags().BoolVarP(&runOpts.verbose, "verbose", "v", false, "Verbose output")
+	runCmd.Flags().StringVar(&runOpts.record, "record", "", "Record HTTP requests and responses to a directory, with credentials redacted")
+	runCmd.Flags().StringVar(&runOpts.replay, "replay", "", "Answer HTTP requests from a recording instead of the network")
+	runCmd.MarkFlagsMutuallyExclusive("record", "replay")
 }
 
 func runJobs(cmd *cobra.Command, args []string) error {
+	plugins, err := loadPlugins(runOpts.configPath)
+	if err != nil {
+		return err
+	}
+	defer plugin.CloseAll(plugins)
+
 	cfg, err := config.Load(runOpts.configPath)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	wrap, checkFixtures, err := fixtures(cfg)
+	if err != nil {
+		return err
+	}
+
 	var writer output.Writer
 	switch runOpts.outputFmt {
 	case "json":
@@ -86,6 +124,10 @@ func runJobs(cmd *cobra.Command, args []string) error {
 	writer.WriteConfigSummary(len(cfg.Environments), len(cfg.Jobs))
 
 	r := runner.NewRunner(cfg, Version)
+	defer r.Close()
+	if wrap != nil {
+		r.SetTransportWrapper(wrap)
+	}
 	r.SetProgressHandler(output.NewProgressAdapter(writer))
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -113,13 +155,50 @@ func runJobs(cmd *cobra.Command, args []string) error {
 
 	writer.WriteResult(result)
 
+	if err := checkFixtures(); err != nil {
+		return err
+	}
+
 	if !result.Success() {
+		r.Close()
+		plugin.CloseAll(plugins)
 		os.Exit(1)
 	}
 
 	return nil
 }
 
+// fixtures sets up recording or replay of HTTP traffic, as selected by
+// --record and --replay. check reports requests that could not be recorded
+// or did not match the recording.
+func fixtures(cfg *config.Config) (wrap providers.TransportWrapper, check func() error, err error) {
+	switch {
+	case runOpts.record != "":
+		recorder, err := fixture.NewRecorder(runOpts.record, maxBodySize(cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		return recorder.Wrap, recorder.Err, nil
+	case runOpts.replay != "":
+		replayer, err := fixture.NewReplayer(runOpts.replay)
+		if err != nil {
+			return nil, nil, err
+		}
+		return replayer.Wrap, replayer.Err, nil
+	}
+	return nil, func() error { return nil }, nil
+}
+
+// maxBodySize returns the largest response body any job accepts, which
+// bounds how much of each response a recording keeps.
+func maxBodySize(cfg *config.Config) config.ByteSize {
+	limit := cfg.Defaults.MaxBodySize
+	for _, job := range cfg.Jobs {
+		limit = max(limit, job.MaxBodySize)
+	}
+	return limit
+}
+
 // parseTags parses comma-separated tags.
 func parseTags(tags []string) []string {
 	var result []string
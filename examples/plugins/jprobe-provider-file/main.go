@@ -0,0 +1,213 @@
+// Command jprobe-provider-file is a reference jprobe plugin that checks
+// that a file exists, is recent and is large enough. It only uses the
+// standard library so it can be copied as a starting point for plugins.
+//
+// Environment spec:
+//
+//	root: /var/backups     # optional base directory for relative paths
+//
+// Job spec:
+//
+//	path: nightly.tar.gz   # required
+//	max_age: 26h           # optional Go duration
+//	min_size: 1048576      # optional, in bytes
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const name = "file"
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type environment struct {
+	Name string         `json:"name"`
+	Spec map[string]any `json:"spec"`
+}
+
+type job struct {
+	Name string         `json:"name"`
+	Spec map[string]any `json:"spec"`
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+var writeMu sync.Mutex
+
+// send writes one message per line to stdout.
+func send(msg message) {
+	msg.JSONRPC = "2.0"
+	data, err := json.Marshal(msg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode message: %v\n", err)
+		return
+	}
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	os.Stdout.Write(append(data, '\n'))
+}
+
+func reply(id *int64, result any) {
+	send(message{ID: id, Result: result})
+}
+
+func replyError(id *int64, code int, format string, args ...any) {
+	send(message{ID: id, Error: &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}})
+}
+
+func progress(jobName, status, text string) {
+	params, _ := json.Marshal(map[string]string{"job": jobName, "status": status, "message": text})
+	send(message{Method: "progress", Params: params})
+}
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			replyError(nil, -32700, "parse error: %v", err)
+			continue
+		}
+		if msg.ID == nil {
+			// Notifications such as cancel need no action: every check
+			// finishes quickly.
+			continue
+		}
+
+		switch msg.Method {
+		case "initialize":
+			reply(msg.ID, map[string]any{
+				"name":             name,
+				"protocol_version": 1,
+				"description":      "Checks file existence, age and size",
+			})
+		case "validate_environment":
+			var params struct {
+				Environment environment `json:"environment"`
+			}
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				replyError(msg.ID, -32602, "invalid params: %v", err)
+				continue
+			}
+			reply(msg.ID, map[string]any{"errors": validateEnvironment(params.Environment)})
+		case "validate_job":
+			var params struct {
+				Job job `json:"job"`
+			}
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				replyError(msg.ID, -32602, "invalid params: %v", err)
+				continue
+			}
+			reply(msg.ID, map[string]any{"errors": validateJob(params.Job)})
+		case "execute":
+			var params struct {
+				Job         job         `json:"job"`
+				Environment environment `json:"environment"`
+			}
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				replyError(msg.ID, -32602, "invalid params: %v", err)
+				continue
+			}
+			reply(msg.ID, execute(params.Job, params.Environment))
+		case "shutdown":
+			reply(msg.ID, map[string]any{})
+			return
+		default:
+			replyError(msg.ID, -32601, "method not found: %s", msg.Method)
+		}
+	}
+}
+
+func validateEnvironment(env environment) []fieldError {
+	errs := []fieldError{}
+	if root, ok := env.Spec["root"]; ok {
+		if _, isString := root.(string); !isString {
+			errs = append(errs, fieldError{"spec.root", "must be a string"})
+		}
+	}
+	return errs
+}
+
+func validateJob(j job) []fieldError {
+	errs := []fieldError{}
+	if path, _ := j.Spec["path"].(string); path == "" {
+		errs = append(errs, fieldError{"spec.path", "is required"})
+	}
+	if v, ok := j.Spec["max_age"]; ok {
+		s, _ := v.(string)
+		if d, err := time.ParseDuration(s); err != nil || d <= 0 {
+			errs = append(errs, fieldError{"spec.max_age", "must be a positive duration such as 26h"})
+		}
+	}
+	if v, ok := j.Spec["min_size"]; ok {
+		if n, isNumber := v.(float64); !isNumber || n < 0 {
+			errs = append(errs, fieldError{"spec.min_size", "must be a non-negative number of bytes"})
+		}
+	}
+	return errs
+}
+
+func execute(j job, env environment) map[string]any {
+	path, _ := j.Spec["path"].(string)
+	if root, _ := env.Spec["root"].(string); root != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+
+	progress(j.Name, "running", "Checking "+path)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return map[string]any{"status": "failed", "error": err.Error(), "details": map[string]any{"path": path}}
+	}
+
+	age := time.Since(info.ModTime())
+	details := map[string]any{
+		"path": path,
+		"size": info.Size(),
+		"age":  age.Round(time.Second).String(),
+	}
+
+	if s, _ := j.Spec["max_age"].(string); s != "" {
+		if maxAge, err := time.ParseDuration(s); err == nil && age > maxAge {
+			return map[string]any{
+				"status":  "failed",
+				"error":   fmt.Sprintf("file is %s old, max %s", age.Round(time.Second), maxAge),
+				"details": details,
+			}
+		}
+	}
+
+	if minSize, ok := j.Spec["min_size"].(float64); ok && info.Size() < int64(minSize) {
+		return map[string]any{
+			"status":  "failed",
+			"error":   fmt.Sprintf("file is %d bytes, min %d", info.Size(), int64(minSize)),
+			"details": details,
+		}
+	}
+
+	return map[string]any{"status": "succeeded", "details": details}
+}
@@ -0,0 +1,185 @@
+package assertion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Response body formats.
+const (
+	FormatJSON = "json"
+	FormatXML  = "xml"
+	FormatHTML = "html"
+	FormatText = "text"
+)
+
+// DetectFormat returns the body format implied by a Content-Type header.
+func DetectFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return FormatText
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return FormatJSON
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return FormatHTML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return FormatXML
+	default:
+		return FormatText
+	}
+}
+
+// CheckBody evaluates the body assertions (json, xpath, css and text) against
+// a response body. format selects the parser for XPath assertions and is
+// usually the result of DetectFormat, or the job's response_type override.
+func CheckBody(body []byte, format string, a config.Assertions) []string {
+	var errors []string
+
+	if len(a.JSON) > 0 {
+		errors = append(errors, checkJSON(body, a.JSON)...)
+	}
+
+	if len(a.XPath) > 0 {
+		errors = append(errors, checkXPath(body, format, a.XPath)...)
+	}
+
+	if len(a.CSS) > 0 {
+		errors = append(errors, checkCSS(body, a.CSS)...)
+	}
+
+	for _, m := range a.Text {
+		if err := Evaluate(string(body), nil, m); err != nil {
+			errors = append(errors, fmt.Sprintf("body: %v", err))
+		}
+	}
+
+	return errors
+}
+
+// checkJSON checks JSON path assertions against a response body.
+func checkJSON(body []byte, assertions []config.JSONAssertion) []string {
+	var errors []string
+
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return []string{fmt.Sprintf("failed to parse JSON response: %v", err)}
+	}
+
+	for _, assertion := range assertions {
+		value, err := JSONPath(data, assertion.Path)
+		if err := Evaluate(value, err, assertion.Matcher); err != nil {
+			errors = append(errors, fmt.Sprintf("JSON path %s: %v", assertion.Path, err))
+		}
+	}
+
+	return errors
+}
+
+// checkXPath checks XPath assertions against an XML or HTML response body.
+func checkXPath(body []byte, format string, assertions []config.XPathAssertion) []string {
+	var errors []string
+
+	var nav xpath.NodeNavigator
+	switch format {
+	case FormatHTML:
+		doc, err := htmlquery.Parse(bytes.NewReader(body))
+		if err != nil {
+			return []string{fmt.Sprintf("failed to parse HTML response: %v", err)}
+		}
+		nav = htmlquery.CreateXPathNavigator(doc)
+	case FormatXML, FormatText:
+		doc, err := xmlquery.Parse(bytes.NewReader(body))
+		if err != nil {
+			return []string{fmt.Sprintf("failed to parse XML response: %v", err)}
+		}
+		nav = xmlquery.CreateXPathNavigator(doc)
+	default:
+		return []string{fmt.Sprintf("xpath assertions require an XML or HTML response, got %s", format)}
+	}
+
+	for _, assertion := range assertions {
+		value, err := evaluateXPath(nav, assertion.Path)
+		if err := Evaluate(value, err, assertion.Matcher); err != nil {
+			errors = append(errors, fmt.Sprintf("XPath %s: %v", assertion.Path, err))
+		}
+	}
+
+	return errors
+}
+
+// evaluateXPath evaluates an expression and returns its scalar result, or the
+// trimmed text of the first selected node.
+func evaluateXPath(nav xpath.NodeNavigator, path string) (any, error) {
+	expr, err := xpath.Compile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %v", err)
+	}
+
+	switch v := expr.Evaluate(nav.Copy()).(type) {
+	case *xpath.NodeIterator:
+		if !v.MoveNext() {
+			return nil, fmt.Errorf("no node matched")
+		}
+		return strings.TrimSpace(v.Current().Value()), nil
+	default:
+		return v, nil
+	}
+}
+
+// checkCSS checks CSS selector assertions against an HTML response body.
+func checkCSS(body []byte, assertions []config.CSSAssertion) []string {
+	var errors []string
+
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return []string{fmt.Sprintf("failed to parse HTML response: %v", err)}
+	}
+
+	for _, assertion := range assertions {
+		value, err := evaluateCSS(doc, assertion.Selector, assertion.Attribute)
+		if err := Evaluate(value, err, assertion.Matcher); err != nil {
+			errors = append(errors, fmt.Sprintf("CSS %s: %v", assertion.Selector, err))
+		}
+	}
+
+	return errors
+}
+
+// evaluateCSS returns the trimmed text, or the named attribute, of the first
+// element matching selector.
+func evaluateCSS(doc *html.Node, selector, attribute string) (any, error) {
+	sel, err := cascadia.Compile(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %v", err)
+	}
+
+	node := sel.MatchFirst(doc)
+	if node == nil {
+		return nil, fmt.Errorf("no element matched")
+	}
+
+	if attribute != "" {
+		for _, attr := range node.Attr {
+			if attr.Key == attribute {
+				return attr.Val, nil
+			}
+		}
+		return nil, fmt.Errorf("attribute '%s' not found", attribute)
+	}
+
+	return strings.TrimSpace(htmlquery.InnerText(node)), nil
+}
@@ -0,0 +1,173 @@
+package assertion
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    string
+	}{
+		{"application/json", FormatJSON},
+		{"application/problem+json; charset=utf-8", FormatJSON},
+		{"text/xml", FormatXML},
+		{"application/soap+xml; charset=utf-8", FormatXML},
+		{"text/html; charset=utf-8", FormatHTML},
+		{"application/xhtml+xml", FormatHTML},
+		{"text/plain", FormatText},
+		{"", FormatText},
+	}
+
+	for _, tt := range tests {
+		if got := DetectFormat(tt.contentType); got != tt.expected {
+			t.Errorf("DetectFormat(%q) = %q, want %q", tt.contentType, got, tt.expected)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		actual  any
+		matcher config.Matcher
+		wantErr bool
+	}{
+		{name: "equals string", actual: "healthy", matcher: config.Matcher{Equals: "healthy"}},
+		{name: "equals int vs float", actual: float64(200), matcher: config.Matcher{Equals: 200}},
+		{name: "equals int vs string", actual: "200", matcher: config.Matcher{Equals: 200}},
+		{name: "equals mismatch", actual: "down", matcher: config.Matcher{Equals: "healthy"}, wantErr: true},
+		{name: "not equals", actual: "up", matcher: config.Matcher{NotEquals: "down"}},
+		{name: "not equals mismatch", actual: "down", matcher: config.Matcher{NotEquals: "down"}, wantErr: true},
+		{name: "contains", actual: "all systems go", matcher: config.Matcher{Contains: "systems"}},
+		{name: "matches", actual: "v1.2.3", matcher: config.Matcher{Matches: `^v\d+\.\d+`}},
+		{name: "matches mismatch", actual: "dev", matcher: config.Matcher{Matches: `^v\d+`}, wantErr: true},
+		{name: "greater than", actual: float64(5), matcher: config.Matcher{GreaterThan: floatPtr(1)}},
+		{name: "less than string number", actual: "0.005", matcher: config.Matcher{LessThan: floatPtr(0.01)}},
+		{name: "less than mismatch", actual: float64(1), matcher: config.Matcher{LessThan: floatPtr(0.01)}, wantErr: true},
+		{name: "no operator means exists", actual: "x", matcher: config.Matcher{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Evaluate(tt.actual, nil, tt.matcher)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("exists false on missing value", func(t *testing.T) {
+		if err := Evaluate(nil, errNotFound, config.Matcher{Exists: boolPtr(false)}); err != nil {
+			t.Errorf("Evaluate() error = %v", err)
+		}
+	})
+
+	t.Run("missing value reports lookup error", func(t *testing.T) {
+		if err := Evaluate(nil, errNotFound, config.Matcher{Equals: "x"}); err != errNotFound {
+			t.Errorf("Evaluate() error = %v, want %v", err, errNotFound)
+		}
+	})
+}
+
+type lookupError string
+
+func (e lookupError) Error() string { return string(e) }
+
+const errNotFound = lookupError("not found")
+
+func TestCheckBodyXML(t *testing.T) {
+	body := []byte(`<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <HealthResponse>
+      <status>OK</status>
+      <services><service>db</service><service>cache</service></services>
+    </HealthResponse>
+  </soap:Body>
+</soap:Envelope>`)
+
+	errs := CheckBody(body, FormatXML, config.Assertions{
+		XPath: []config.XPathAssertion{
+			{Path: "//HealthResponse/status", Matcher: config.Matcher{Equals: "OK"}},
+			{Path: "count(//service)", Matcher: config.Matcher{Equals: 2}},
+			{Path: "//missing", Matcher: config.Matcher{Exists: boolPtr(false)}},
+		},
+	})
+	if len(errs) > 0 {
+		t.Errorf("CheckBody() errors = %v", errs)
+	}
+
+	errs = CheckBody(body, FormatXML, config.Assertions{
+		XPath: []config.XPathAssertion{
+			{Path: "//HealthResponse/status", Matcher: config.Matcher{Equals: "DOWN"}},
+		},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0], "expected DOWN, got OK") {
+		t.Errorf("CheckBody() errors = %v", errs)
+	}
+}
+
+func TestCheckBodyHTML(t *testing.T) {
+	body := []byte(`<html><body>
+  <div id="status" class="badge ok" data-version="1.4.2">
+    All systems operational
+  </div>
+  <ul><li>api</li><li>worker</li></ul>
+</body></html>`)
+
+	errs := CheckBody(body, FormatHTML, config.Assertions{
+		CSS: []config.CSSAssertion{
+			{Selector: "#status", Matcher: config.Matcher{Contains: "operational"}},
+			{Selector: "div.badge", Attribute: "data-version", Matcher: config.Matcher{Matches: `^1\.`}},
+			{Selector: ".error", Matcher: config.Matcher{Exists: boolPtr(false)}},
+		},
+		XPath: []config.XPathAssertion{
+			{Path: "count(//li)", Matcher: config.Matcher{Equals: 2}},
+		},
+		Text: []config.Matcher{
+			{Contains: "All systems"},
+		},
+	})
+	if len(errs) > 0 {
+		t.Errorf("CheckBody() errors = %v", errs)
+	}
+
+	errs = CheckBody(body, FormatHTML, config.Assertions{
+		CSS: []config.CSSAssertion{
+			{Selector: ".error"},
+		},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0], "no element matched") {
+		t.Errorf("CheckBody() errors = %v", errs)
+	}
+}
+
+func TestCheckBodyJSON(t *testing.T) {
+	body := []byte(`{"status":"healthy","checks":{"db":"up"},"latency":12}`)
+
+	errs := CheckBody(body, FormatJSON, config.Assertions{
+		JSON: []config.JSONAssertion{
+			{Path: "$.status", Matcher: config.Matcher{Equals: "healthy"}},
+			{Path: "$.checks.db", Matcher: config.Matcher{NotEquals: "down"}},
+			{Path: "$.latency", Matcher: config.Matcher{LessThan: floatPtr(100)}},
+		},
+	})
+	if len(errs) > 0 {
+		t.Errorf("CheckBody() errors = %v", errs)
+	}
+
+	errs = CheckBody(body, FormatJSON, config.Assertions{
+		XPath: []config.XPathAssertion{{Path: "//status"}},
+	})
+	if len(errs) != 1 || !strings.Contains(errs[0], "require an XML or HTML response") {
+		t.Errorf("CheckBody() errors = %v", errs)
+	}
+}
@@ -0,0 +1,39 @@
+package assertion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JSONPath extracts a value from JSON data using a simple path notation.
+// Supports paths like $.status, $.data.name
+func JSONPath(data any, path string) (any, error) {
+	if !strings.HasPrefix(path, "$.") {
+		return nil, fmt.Errorf("path must start with $.")
+	}
+
+	path = strings.TrimPrefix(path, "$.")
+	parts := strings.Split(path, ".")
+
+	current := data
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		switch v := current.(type) {
+		case map[string]any:
+			var ok bool
+			current, ok = v[part]
+			if !ok {
+				return nil, fmt.Errorf("key '%s' not found", part)
+			}
+		case []any:
+			return nil, fmt.Errorf("array indexing not yet supported for '%s'", part)
+		default:
+			return nil, fmt.Errorf("cannot traverse '%s' in non-object type", part)
+		}
+	}
+
+	return current, nil
+}
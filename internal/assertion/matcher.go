@@ -0,0 +1,121 @@
+// Package assertion evaluates response assertions shared by all providers.
+package assertion
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Evaluate checks a looked-up value against a matcher. lookupErr is the error
+// returned when resolving the value (for example, a missing JSON key); it is
+// only reported if the matcher does not expect the value to be absent.
+func Evaluate(actual any, lookupErr error, m config.Matcher) error {
+	if m.Exists != nil && !*m.Exists {
+		if lookupErr != nil {
+			return nil
+		}
+		return fmt.Errorf("expected no value, got %v", actual)
+	}
+
+	if lookupErr != nil {
+		return lookupErr
+	}
+
+	if m.Equals != nil && !Equal(actual, m.Equals) {
+		return fmt.Errorf("expected %v, got %v", m.Equals, actual)
+	}
+
+	if m.NotEquals != nil && Equal(actual, m.NotEquals) {
+		return fmt.Errorf("expected value other than %v", m.NotEquals)
+	}
+
+	if m.Contains != "" && !strings.Contains(String(actual), m.Contains) {
+		return fmt.Errorf("expected %q to contain %q", String(actual), m.Contains)
+	}
+
+	if m.Matches != "" {
+		re, err := regexp.Compile(m.Matches)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", m.Matches, err)
+		}
+		if !re.MatchString(String(actual)) {
+			return fmt.Errorf("expected %q to match %q", String(actual), m.Matches)
+		}
+	}
+
+	if m.GreaterThan != nil || m.LessThan != nil {
+		n, ok := Float(actual)
+		if !ok {
+			return fmt.Errorf("expected a number, got %v", actual)
+		}
+		if m.GreaterThan != nil && !(n > *m.GreaterThan) {
+			return fmt.Errorf("expected greater than %v, got %v", *m.GreaterThan, actual)
+		}
+		if m.LessThan != nil && !(n < *m.LessThan) {
+			return fmt.Errorf("expected less than %v, got %v", *m.LessThan, actual)
+		}
+	}
+
+	return nil
+}
+
+// Equal compares two values for equality, tolerating the numeric type
+// differences between YAML and JSON decoding.
+func Equal(actual, expected any) bool {
+	switch exp := expected.(type) {
+	case string:
+		if act, ok := actual.(string); ok {
+			return act == exp
+		}
+	case bool:
+		if act, ok := actual.(bool); ok {
+			return act == exp
+		}
+	case int:
+		if act, ok := actual.(float64); ok {
+			return act == float64(exp)
+		}
+	case float64:
+		if act, ok := actual.(float64); ok {
+			return act == exp
+		}
+	}
+
+	return String(actual) == String(expected)
+}
+
+// String formats a value for text comparison. Whole floats are formatted
+// without a decimal point so that 200.0 compares equal to "200".
+func String(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Float converts a value to a float64 if it is numeric or a numeric string.
+func Float(v any) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -15,6 +15,7 @@ type Config struct {
 type Defaults struct {
 	Timeout      time.Duration `yaml:"timeout"`
 	PollInterval time.Duration `yaml:"poll_interval"`
+	MaxBodySize  ByteSize      `yaml:"max_body_size"`
 }
 
 // OutputConfig represents output settings.
@@ -36,6 +37,49 @@ type Environment struct {
 	APIVersion int               `yaml:"api_version"`
 	Auth       Auth              `yaml:"auth"`
 	Headers    map[string]string `yaml:"headers"`
+	Transport  Transport         `yaml:"transport"`
+
+	// TLS holds certificate settings for environments whose provider
+	// connects over TLS, such as grpcs://, rediss:// and amqps:// URLs.
+	TLS TLS `yaml:"tls"`
+
+	// Spec holds the provider's settings for the environment, which the
+	// provider decodes with DecodeEnvironmentSpec and validates itself.
+	Spec map[string]any `yaml:"spec"`
+
+	// Settings collects the other keys of the environment. They are
+	// provider settings written at the top level, as in configs written
+	// before Spec, and are decoded together with it.
+	Settings map[string]any `yaml:",inline"`
+}
+
+// TLS represents TLS certificate settings. CAFile replaces the system roots;
+// CertFile and KeyFile enable mutual TLS. Enabled turns TLS on for
+// environment types whose URL scheme does not select it.
+type TLS struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// Transport represents HTTP connection settings for an environment.
+// Zero values fall back to the provider defaults.
+type Transport struct {
+	KeepAlive           time.Duration `yaml:"keep_alive"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout"`
+	MaxIdleConns        int           `yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host"`
+	DisableKeepAlives   bool          `yaml:"disable_keep_alives"`
+	HTTP2               *bool         `yaml:"http2"`
+}
+
+// HTTP2Enabled returns whether HTTP/2 should be negotiated. It defaults to true.
+func (t Transport) HTTP2Enabled() bool {
+	return t.HTTP2 == nil || *t.HTTP2
 }
 
 // Auth represents authentication configuration.
@@ -50,35 +94,83 @@ type Auth struct {
 
 // Job represents a job definition.
 type Job struct {
-	Name         string            `yaml:"name"`
-	Description  string            `yaml:"description"`
-	Environment  string            `yaml:"environment"`
-	Type         string            `yaml:"type"`
-	JobID        string            `yaml:"job_id"`
-	Project      string            `yaml:"project"`
-	Options      map[string]string `yaml:"options"`
-	Timeout      time.Duration     `yaml:"timeout"`
-	PollInterval time.Duration     `yaml:"poll_interval"`
-	Assertions   Assertions        `yaml:"assertions"`
-	Tags         []string          `yaml:"tags"`
-	Method       string            `yaml:"method"`
-	Path         string            `yaml:"path"`
-	Headers      map[string]string `yaml:"headers"`
-	Body         map[string]any    `yaml:"body"`
-}
-
-// Assertions represents job assertions.
+	Name         string        `yaml:"name"`
+	Description  string        `yaml:"description"`
+	Environment  string        `yaml:"environment"`
+	Type         string        `yaml:"type"`
+	Timeout      time.Duration `yaml:"timeout"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	Assertions   Assertions    `yaml:"assertions"`
+	Tags         []string      `yaml:"tags"`
+	MaxBodySize  ByteSize      `yaml:"max_body_size"`
+
+	// Spec holds the provider's settings for the job, which the provider
+	// decodes with DecodeJobSpec and validates itself.
+	Spec map[string]any `yaml:"spec"`
+
+	// Settings collects the other keys of the job. They are provider
+	// settings written at the top level, such as an http job's method and
+	// path, and are decoded together with Spec.
+	Settings map[string]any `yaml:",inline"`
+
+	// Dir is the directory of the file that defines the job. Providers
+	// resolve relative file paths in its settings against it.
+	Dir string `yaml:"-"`
+}
+
+// Assertions represents job assertions. The fields here are shared by
+// several providers; the others are collected in Settings and decoded by
+// the job's provider with DecodeAssertions.
 type Assertions struct {
 	Status      string           `yaml:"status"`
 	MaxDuration time.Duration    `yaml:"max_duration"`
 	StatusCode  int              `yaml:"status_code"`
 	JSON        []JSONAssertion  `yaml:"json"`
+	XPath       []XPathAssertion `yaml:"xpath"`
+	CSS         []CSSAssertion   `yaml:"css"`
+	Text        []Matcher        `yaml:"text"`
+
+	// Settings collects the provider-specific assertions, such as an exec
+	// job's exit_code.
+	Settings map[string]any `yaml:",inline"`
+}
+
+// HasBodyAssertions returns true if any assertion inspects the response body.
+func (a Assertions) HasBodyAssertions() bool {
+	return len(a.JSON) > 0 || len(a.XPath) > 0 || len(a.CSS) > 0 || len(a.Text) > 0
+}
+
+// Matcher holds the comparison operators shared by all assertion kinds.
+// When no operator is set, the matcher only checks that a value exists.
+type Matcher struct {
+	Equals      any      `yaml:"equals"`
+	NotEquals   any      `yaml:"not_equals"`
+	Contains    string   `yaml:"contains"`
+	Matches     string   `yaml:"matches"`
+	Exists      *bool    `yaml:"exists"`
+	GreaterThan *float64 `yaml:"greater_than"`
+	LessThan    *float64 `yaml:"less_than"`
 }
 
 // JSONAssertion represents a JSON path assertion.
 type JSONAssertion struct {
-	Path   string `yaml:"path"`
-	Equals any    `yaml:"equals"`
+	Path    string `yaml:"path"`
+	Matcher `yaml:",inline"`
+}
+
+// XPathAssertion represents an XPath assertion on an XML or HTML response.
+type XPathAssertion struct {
+	Path    string `yaml:"path"`
+	Matcher `yaml:",inline"`
+}
+
+// CSSAssertion represents a CSS selector assertion on an HTML response.
+// The text of the first matching element is compared, or the named
+// attribute if Attribute is set.
+type CSSAssertion struct {
+	Selector  string `yaml:"selector"`
+	Attribute string `yaml:"attribute"`
+	Matcher   `yaml:",inline"`
 }
 
 // GetTimeout returns the job timeout or the default.
@@ -97,6 +189,23 @@ func (j *Job) GetPollInterval(defaults Defaults) time.Duration {
 	return defaults.PollInterval
 }
 
+// GetMaxBodySize returns the job response body limit or the default.
+func (j *Job) GetMaxBodySize(defaults Defaults) ByteSize {
+	if j.MaxBodySize > 0 {
+		return j.MaxBodySize
+	}
+	return defaults.MaxBodySize
+}
+
+// WithDefaults returns a copy of the job with unset settings filled in
+// from defaults, so providers see the effective values.
+func (j Job) WithDefaults(defaults Defaults) Job {
+	j.Timeout = j.GetTimeout(defaults)
+	j.PollInterval = j.GetPollInterval(defaults)
+	j.MaxBodySize = j.GetMaxBodySize(defaults)
+	return j
+}
+
 // HasTag checks if the job has a specific tag.
 func (j *Job) HasTag(tag string) bool {
 	for _, t := range j.Tags {
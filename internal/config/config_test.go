@@ -3,10 +3,45 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
 
+// probeType stands in for a provider type in the tests. Its environments
+// need a URL and its jobs a path.
+type probeType struct{}
+
+func (probeType) ValidateEnvironment(env Environment) ValidationErrors {
+	if env.URL == "" {
+		return ValidationErrors{{Field: "url", Message: "is required"}}
+	}
+	return nil
+}
+
+func (probeType) ValidateJob(job Job, env Environment) ValidationErrors {
+	var spec probeSpec
+	errs := DecodeJobSpec(job, &spec)
+	if spec.Path == "" {
+		errs = append(errs, ValidationError{Field: "path", Message: "is required"})
+	}
+	return append(errs, DecodeAssertions(job, nil)...)
+}
+
+// probeSpec holds the settings of a probe job.
+type probeSpec struct {
+	Method string   `yaml:"method"`
+	Path   string   `yaml:"path"`
+	Args   []string `yaml:"args"`
+}
+
+// registerProbeType makes probe a valid type for the rest of the test.
+func registerProbeType(t *testing.T) {
+	t.Helper()
+	RegisterType("probe", probeType{})
+	t.Cleanup(func() { UnregisterType("probe") })
+}
+
 func TestExpandEnvVars(t *testing.T) {
 	os.Setenv("TEST_TOKEN", "secret123")
 	defer os.Unsetenv("TEST_TOKEN")
@@ -104,7 +139,57 @@ func TestJobGetTimeout(t *testing.T) {
 	})
 }
 
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ByteSize
+		wantErr  bool
+	}{
+		{input: "512", expected: 512},
+		{input: "512B", expected: 512},
+		{input: "64KB", expected: 64 * Kilobyte},
+		{input: "10MB", expected: 10 * Megabyte},
+		{input: "10mb", expected: 10 * Megabyte},
+		{input: "1GB", expected: Gigabyte},
+		{input: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseByteSize(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseByteSize(%q) = %d, want %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJobWithDefaults(t *testing.T) {
+	defaults := Defaults{
+		Timeout:      10 * time.Minute,
+		PollInterval: 10 * time.Second,
+		MaxBodySize:  10 * Megabyte,
+	}
+
+	job := Job{Timeout: time.Minute, MaxBodySize: Kilobyte}.WithDefaults(defaults)
+
+	if job.Timeout != time.Minute {
+		t.Errorf("Timeout = %v, want %v", job.Timeout, time.Minute)
+	}
+	if job.PollInterval != 10*time.Second {
+		t.Errorf("PollInterval = %v, want %v", job.PollInterval, 10*time.Second)
+	}
+	if job.MaxBodySize != Kilobyte {
+		t.Errorf("MaxBodySize = %v, want %v", job.MaxBodySize, Kilobyte)
+	}
+}
+
 func TestValidate(t *testing.T) {
+	registerProbeType(t)
+
 	t.Run("valid config", func(t *testing.T) {
 		cfg := &Config{
 			Defaults: Defaults{
@@ -116,7 +201,7 @@ func TestValidate(t *testing.T) {
 			},
 			Environments: map[string]Environment{
 				"test-env": {
-					Type: "http",
+					Type: "probe",
 					URL:  "http://localhost:8080",
 				},
 			},
@@ -124,9 +209,8 @@ func TestValidate(t *testing.T) {
 				{
 					Name:        "test-job",
 					Environment: "test-env",
-					Type:        "http",
-					Method:      "GET",
-					Path:        "/health",
+					Type:        "probe",
+					Settings:    map[string]any{"method": "GET", "path": "/health"},
 				},
 			},
 		}
@@ -144,16 +228,15 @@ func TestValidate(t *testing.T) {
 			},
 			Environments: map[string]Environment{
 				"test-env": {
-					Type: "http",
+					Type: "probe",
 					URL:  "http://localhost:8080",
 				},
 			},
 			Jobs: []Job{
 				{
 					Environment: "test-env",
-					Type:        "http",
-					Method:      "GET",
-					Path:        "/health",
+					Type:        "probe",
+					Settings:    map[string]any{"path": "/health"},
 				},
 			},
 		}
@@ -172,7 +255,7 @@ func TestValidate(t *testing.T) {
 			},
 			Environments: map[string]Environment{
 				"test-env": {
-					Type: "http",
+					Type: "probe",
 					URL:  "http://localhost:8080",
 				},
 			},
@@ -180,16 +263,14 @@ func TestValidate(t *testing.T) {
 				{
 					Name:        "test-job",
 					Environment: "test-env",
-					Type:        "http",
-					Method:      "GET",
-					Path:        "/health",
+					Type:        "probe",
+					Settings:    map[string]any{"path": "/health"},
 				},
 				{
 					Name:        "test-job",
 					Environment: "test-env",
-					Type:        "http",
-					Method:      "GET",
-					Path:        "/ready",
+					Type:        "probe",
+					Settings:    map[string]any{"path": "/ready"},
 				},
 			},
 		}
@@ -211,9 +292,8 @@ func TestValidate(t *testing.T) {
 				{
 					Name:        "test-job",
 					Environment: "nonexistent",
-					Type:        "http",
-					Method:      "GET",
-					Path:        "/health",
+					Type:        "probe",
+					Settings:    map[string]any{"path": "/health"},
 				},
 			},
 		}
@@ -223,15 +303,196 @@ func TestValidate(t *testing.T) {
 			t.Error("expected validation error for missing environment")
 		}
 	})
+
+	t.Run("registered type", func(t *testing.T) {
+		cfg := &Config{
+			Defaults: Defaults{
+				Timeout:      10 * time.Minute,
+				PollInterval: 10 * time.Second,
+			},
+			Environments: map[string]Environment{
+				"web":   {Type: "probe"},
+				"other": {Type: "ftp", URL: "ftp://files"},
+			},
+			Jobs: []Job{
+				{
+					Name:        "health",
+					Environment: "web",
+					Type:        "probe",
+					Settings:    map[string]any{"methd": "GET"},
+					Assertions:  Assertions{Settings: map[string]any{"exit_code": 0}},
+				},
+				{Name: "upload", Environment: "other", Type: "ftp"},
+			},
+		}
+
+		err := Validate(cfg)
+		if err == nil {
+			t.Fatal("expected validation errors")
+		}
+		for _, want := range []string{
+			"environments.web.url: is required",
+			"environments.other.type: invalid type 'ftp', must be one of: probe",
+			"jobs[0].methd: unknown field",
+			"jobs[0].path: is required",
+			"jobs[0].assertions.exit_code: unknown field",
+			"jobs[1].type: invalid type 'ftp', must be one of: probe",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected %q in %v", want, err)
+			}
+		}
+	})
+}
+
+func TestDecodeSpec(t *testing.T) {
+	type spec struct {
+		Path   string        `yaml:"path"`
+		MaxAge time.Duration `yaml:"max_age"`
+		Size   ByteSize      `yaml:"size"`
+		Count  int           `yaml:"count"`
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		var got spec
+		errs := DecodeSpec(map[string]any{"path": "a.txt", "max_age": "26h", "size": "1MB", "count": 3}, &got)
+		if len(errs) > 0 {
+			t.Fatalf("DecodeSpec() errors = %v", errs)
+		}
+		want := spec{Path: "a.txt", MaxAge: 26 * time.Hour, Size: 1 << 20, Count: 3}
+		if got != want {
+			t.Errorf("DecodeSpec() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var got spec
+		if errs := DecodeSpec(nil, &got); len(errs) > 0 {
+			t.Errorf("DecodeSpec() errors = %v", errs)
+		}
+	})
+
+	t.Run("unknown and mistyped fields", func(t *testing.T) {
+		var got spec
+		errs := DecodeSpec(map[string]any{"pth": "a.txt", "count": "many"}, &got)
+		if len(errs) != 2 {
+			t.Fatalf("DecodeSpec() errors = %v, want 2", errs)
+		}
+		if !strings.Contains(errs.Error(), "spec.pth: unknown field") {
+			t.Errorf("expected unknown field error, got %v", errs)
+		}
+		if !strings.Contains(errs.Error(), "spec.count: cannot unmarshal") {
+			t.Errorf("expected type error, got %v", errs)
+		}
+	})
+}
+
+func TestDecodeJobSpec(t *testing.T) {
+	job := Job{
+		Settings: map[string]any{"method": "GET", "path": "/old", "args": []any{"-v"}},
+		Spec:     map[string]any{"path": "/health"},
+	}
+
+	var got probeSpec
+	if errs := DecodeJobSpec(job, &got); len(errs) > 0 {
+		t.Fatalf("DecodeJobSpec() errors = %v", errs)
+	}
+	if got.Method != "GET" || got.Path != "/health" || len(got.Args) != 1 {
+		t.Errorf("DecodeJobSpec() = %+v, want method from settings and path from spec", got)
+	}
+
+	job = Job{
+		Settings: map[string]any{"args": "-v", "headers": map[string]any{"a": "b"}},
+		Spec:     map[string]any{"method": map[string]any{"get": true}},
+	}
+	errs := DecodeJobSpec(job, &got)
+	for _, want := range []string{
+		"args: cannot unmarshal",
+		"headers: unknown field",
+		"spec.method: cannot unmarshal",
+	} {
+		if !strings.Contains(errs.Error(), want) {
+			t.Errorf("expected %q in %v", want, errs)
+		}
+	}
+}
+
+func TestExpandEnvVarsInConfig(t *testing.T) {
+	os.Setenv("TEST_HOST", "db.internal")
+	defer os.Unsetenv("TEST_HOST")
+
+	cfg := &Config{
+		Environments: map[string]Environment{
+			"events": {Settings: map[string]any{"brokers": []any{"${TEST_HOST}:9092"}}},
+		},
+		Jobs: []Job{
+			{Spec: map[string]any{"args": []any{"--host", "${TEST_HOST}"}, "env": map[string]any{"HOST": "${TEST_HOST}"}}},
+		},
+	}
+	ExpandEnvVarsInConfig(cfg)
+
+	if got := cfg.Environments["events"].Settings["brokers"].([]any)[0]; got != "db.internal:9092" {
+		t.Errorf("brokers[0] = %v", got)
+	}
+	if got := cfg.Jobs[0].Spec["args"].([]any)[1]; got != "db.internal" {
+		t.Errorf("args[1] = %v", got)
+	}
+	if got := cfg.Jobs[0].Spec["env"].(map[string]any)["HOST"]; got != "db.internal" {
+		t.Errorf("env.HOST = %v", got)
+	}
+}
+
+func TestValidateAuth(t *testing.T) {
+	registerProbeType(t)
+
+	tests := []struct {
+		name    string
+		auth    Auth
+		wantErr bool
+	}{
+		{name: "no auth", auth: Auth{}},
+		{name: "none", auth: Auth{Type: "none"}},
+		{name: "bearer", auth: Auth{Type: "bearer", Token: "t"}},
+		{name: "basic", auth: Auth{Type: "basic", Username: "u", Password: "p"}},
+		{name: "api_key", auth: Auth{Type: "api_key", APIKey: "k"}},
+		{name: "digest", auth: Auth{Type: "digest", Username: "u", Password: "p"}},
+		{name: "digest without username", auth: Auth{Type: "digest", Password: "p"}, wantErr: true},
+		{name: "typo", auth: Auth{Type: "baerer", Token: "t"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Defaults: Defaults{
+					Timeout:      10 * time.Minute,
+					PollInterval: 10 * time.Second,
+				},
+				Environments: map[string]Environment{
+					"test-env": {
+						Type: "probe",
+						URL:  "http://localhost:8080",
+						Auth: tt.auth,
+					},
+				},
+			}
+
+			err := Validate(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
 }
 
 func TestLoadFromFile(t *testing.T) {
+	registerProbeType(t)
 	dir := t.TempDir()
 
 	configContent := `
 defaults:
   timeout: 5m
   poll_interval: 5s
+  max_body_size: 1MB
 
 output:
   format: json
@@ -241,17 +502,23 @@ output:
 
 environments:
   test-env:
-    type: http
+    type: probe
     url: http://localhost:8080
 
 jobs:
   - name: health-check
     environment: test-env
-    type: http
+    type: probe
     method: GET
     path: /health
     assertions:
       status_code: 200
+      json:
+        - path: $.status
+          equals: healthy
+      css:
+        - selector: "#status"
+          contains: operational
     tags:
       - critical
 `
@@ -270,6 +537,10 @@ jobs:
 		t.Errorf("Timeout = %v, want %v", cfg.Defaults.Timeout, 5*time.Minute)
 	}
 
+	if cfg.Defaults.MaxBodySize != Megabyte {
+		t.Errorf("MaxBodySize = %v, want %v", cfg.Defaults.MaxBodySize, Megabyte)
+	}
+
 	if cfg.Output.Format != "json" {
 		t.Errorf("Output.Format = %v, want %v", cfg.Output.Format, "json")
 	}
@@ -285,9 +556,27 @@ jobs:
 	if cfg.Jobs[0].Name != "health-check" {
 		t.Errorf("Jobs[0].Name = %v, want %v", cfg.Jobs[0].Name, "health-check")
 	}
+
+	if cfg.Jobs[0].Settings["path"] != "/health" {
+		t.Errorf("Jobs[0].Settings = %v, want path /health", cfg.Jobs[0].Settings)
+	}
+
+	if cfg.Jobs[0].Dir != dir {
+		t.Errorf("Jobs[0].Dir = %q, want %q", cfg.Jobs[0].Dir, dir)
+	}
+
+	assertions := cfg.Jobs[0].Assertions
+	if len(assertions.JSON) != 1 || assertions.JSON[0].Equals != "healthy" {
+		t.Errorf("Assertions.JSON = %+v, want equals healthy", assertions.JSON)
+	}
+
+	if len(assertions.CSS) != 1 || assertions.CSS[0].Contains != "operational" {
+		t.Errorf("Assertions.CSS = %+v, want contains operational", assertions.CSS)
+	}
 }
 
 func TestLoadFromDirectory(t *testing.T) {
+	registerProbeType(t)
 	dir := t.TempDir()
 
 	configContent := `
@@ -302,7 +591,7 @@ defaults:
 	envContent := `
 environments:
   test-env:
-    type: http
+    type: probe
     url: http://localhost:8080
 `
 	if err := os.WriteFile(filepath.Join(dir, "environments.yaml"), []byte(envContent), 0644); err != nil {
@@ -317,7 +606,7 @@ environments:
 jobs:
   - name: health-check
     environment: test-env
-    type: http
+    type: probe
     method: GET
     path: /health
 `
@@ -339,6 +628,10 @@ jobs:
 	}
 
 	if len(cfg.Jobs) != 1 {
-		t.Errorf("len(Jobs) = %d, want 1", len(cfg.Jobs))
+		t.Fatalf("len(Jobs) = %d, want 1", len(cfg.Jobs))
+	}
+
+	if want := filepath.Join(dir, "jobs"); cfg.Jobs[0].Dir != want {
+		t.Errorf("Jobs[0].Dir = %q, want %q", cfg.Jobs[0].Dir, want)
 	}
 }
@@ -40,29 +40,45 @@ func ExpandEnvVarsInAuth(auth *Auth) {
 }
 
 // ExpandEnvVarsInConfig expands all environment variables in the config.
+// Provider settings are expanded wherever they hold strings.
 func ExpandEnvVarsInConfig(cfg *Config) {
 	for name, env := range cfg.Environments {
 		env.URL = ExpandEnvVars(env.URL)
+		env.TLS.CAFile = ExpandEnvVars(env.TLS.CAFile)
+		env.TLS.CertFile = ExpandEnvVars(env.TLS.CertFile)
+		env.TLS.KeyFile = ExpandEnvVars(env.TLS.KeyFile)
 		ExpandEnvVarsInAuth(&env.Auth)
 		env.Headers = ExpandEnvVarsInMap(env.Headers)
+		expandEnvVarsInBody(env.Spec)
+		expandEnvVarsInBody(env.Settings)
 		cfg.Environments[name] = env
 	}
 
 	for i := range cfg.Jobs {
-		cfg.Jobs[i].Headers = ExpandEnvVarsInMap(cfg.Jobs[i].Headers)
-		cfg.Jobs[i].Options = ExpandEnvVarsInMap(cfg.Jobs[i].Options)
-		expandEnvVarsInBody(cfg.Jobs[i].Body)
+		expandEnvVarsInBody(cfg.Jobs[i].Spec)
+		expandEnvVarsInBody(cfg.Jobs[i].Settings)
 	}
 }
 
 // expandEnvVarsInBody recursively expands environment variables in body.
 func expandEnvVarsInBody(body map[string]any) {
 	for k, v := range body {
-		switch val := v.(type) {
-		case string:
-			body[k] = ExpandEnvVars(val)
-		case map[string]any:
-			expandEnvVarsInBody(val)
+		body[k] = expandEnvVarsInValue(v)
+	}
+}
+
+// expandEnvVarsInValue expands environment variables in the strings of a
+// decoded YAML value.
+func expandEnvVarsInValue(v any) any {
+	switch val := v.(type) {
+	case string:
+		return ExpandEnvVars(val)
+	case map[string]any:
+		expandEnvVarsInBody(val)
+	case []any:
+		for i, item := range val {
+			val[i] = expandEnvVarsInValue(item)
 		}
 	}
+	return v
 }
@@ -15,6 +15,7 @@ func DefaultConfig() *Config {
 		Defaults: Defaults{
 			Timeout:      10 * time.Minute,
 			PollInterval: 10 * time.Second,
+			MaxBodySize:  10 * Megabyte,
 		},
 		Output: OutputConfig{
 			Console: ConsoleConfig{
@@ -102,6 +103,8 @@ func loadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	setJobDir(fileCfg.Jobs, filepath.Dir(path))
+
 	if fileCfg.Defaults != nil {
 		if fileCfg.Defaults.Timeout > 0 {
 			cfg.Defaults.Timeout = fileCfg.Defaults.Timeout
@@ -109,6 +112,9 @@ func loadFromFile(path string) (*Config, error) {
 		if fileCfg.Defaults.PollInterval > 0 {
 			cfg.Defaults.PollInterval = fileCfg.Defaults.PollInterval
 		}
+		if fileCfg.Defaults.MaxBodySize > 0 {
+			cfg.Defaults.MaxBodySize = fileCfg.Defaults.MaxBodySize
+		}
 	}
 
 	if fileCfg.Output != nil {
@@ -149,6 +155,9 @@ func mergeConfigFile(cfg *Config, path string) error {
 		if fileCfg.Defaults.PollInterval > 0 {
 			cfg.Defaults.PollInterval = fileCfg.Defaults.PollInterval
 		}
+		if fileCfg.Defaults.MaxBodySize > 0 {
+			cfg.Defaults.MaxBodySize = fileCfg.Defaults.MaxBodySize
+		}
 	}
 
 	if fileCfg.Output != nil {
@@ -221,7 +230,17 @@ func loadJobsFile(cfg *Config, path string) error {
 		return err
 	}
 
+	setJobDir(jobsCfg.Jobs, filepath.Dir(path))
+
 	cfg.Jobs = append(cfg.Jobs, jobsCfg.Jobs...)
 
 	return nil
 }
+
+// setJobDir records dir, the directory of the YAML file that defines the
+// jobs, so providers can resolve the files jobs refer to.
+func setJobDir(jobs []Job, dir string) {
+	for i := range jobs {
+		jobs[i].Dir = dir
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ByteSize represents a size in bytes. In YAML it may be written as a plain
+// integer or with a unit suffix such as 512KB, 10MB or 1GB (powers of 1024).
+type ByteSize int64
+
+// Common byte sizes.
+const (
+	Byte     ByteSize = 1
+	Kilobyte          = 1024 * Byte
+	Megabyte          = 1024 * Kilobyte
+	Gigabyte          = 1024 * Megabyte
+)
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"GB", Gigabyte},
+	{"MB", Megabyte},
+	{"KB", Kilobyte},
+	{"B", Byte},
+}
+
+// ParseByteSize parses a size string such as "10MB" into a ByteSize.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	upper := strings.ToUpper(s)
+
+	multiplier := Byte
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(upper, unit.suffix) {
+			multiplier = unit.size
+			upper = strings.TrimSpace(strings.TrimSuffix(upper, unit.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s'", s)
+	}
+
+	return ByteSize(n) * multiplier, nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (b *ByteSize) UnmarshalYAML(value *yaml.Node) error {
+	size, err := ParseByteSize(value.Value)
+	if err != nil {
+		return err
+	}
+	*b = size
+	return nil
+}
+
+// String returns the size using the largest unit that divides it evenly.
+func (b ByteSize) String() string {
+	for _, unit := range byteSizeUnits {
+		if b != 0 && b%unit.size == 0 {
+			return fmt.Sprintf("%d%s", b/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", int64(b))
+}
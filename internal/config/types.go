@@ -0,0 +1,207 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TypeValidator validates the environments and jobs of one provider type.
+// Every provider type registers one, so the rules for a type live with its
+// provider and adding a provider does not require changes to this package.
+// Providers decode their settings with DecodeJobSpec and
+// DecodeEnvironmentSpec. Returned fields are relative to the environment or
+// job, for example "path" or "assertions.exit_code".
+type TypeValidator interface {
+	// ValidateEnvironment validates an environment of the type.
+	ValidateEnvironment(env Environment) ValidationErrors
+
+	// ValidateJob validates a job of the type and its environment.
+	ValidateJob(job Job, env Environment) ValidationErrors
+}
+
+var (
+	typesMu        sync.RWMutex
+	typeValidators = make(map[string]TypeValidator)
+)
+
+// RegisterType makes name a valid environment and job type whose
+// configuration is validated by v.
+func RegisterType(name string, v TypeValidator) {
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	typeValidators[name] = v
+}
+
+// UnregisterType removes a type added by RegisterType.
+func UnregisterType(name string) {
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	delete(typeValidators, name)
+}
+
+// typeValidator returns the validator registered for name, if any.
+func typeValidator(name string) (TypeValidator, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	v, ok := typeValidators[name]
+	return v, ok
+}
+
+// registeredTypeNames returns the registered type names, sorted.
+func registeredTypeNames() []string {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+
+	names := make([]string, 0, len(typeValidators))
+	for name := range typeValidators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// prefixErrors prepends prefix to the field of each error.
+func prefixErrors(errs ValidationErrors, prefix string) ValidationErrors {
+	prefixed := make(ValidationErrors, len(errs))
+	for i, err := range errs {
+		field := prefix
+		if err.Field != "" {
+			field += "." + err.Field
+		}
+		prefixed[i] = ValidationError{Field: field, Message: err.Message}
+	}
+	return prefixed
+}
+
+// unknownFieldPattern matches the yaml error for a key with no struct field.
+var unknownFieldPattern = regexp.MustCompile(`^field (\S+) not found in type`)
+
+// linePattern matches the position yaml puts before each error.
+var linePattern = regexp.MustCompile(`^line (\d+): `)
+
+// DecodeSpec decodes a spec sub-document into out, which must be a pointer
+// to a struct with yaml tags. Unknown keys are rejected. Errors are reported
+// against "spec" and its keys.
+func DecodeSpec(spec map[string]any, out any) ValidationErrors {
+	return decode(spec, out, "spec.")
+}
+
+// DecodeJobSpec decodes the job's provider settings into out, which must be
+// a pointer to a struct with yaml tags, or nil for a provider that has no
+// settings. Settings are read from the job's top level and then from its
+// spec, which takes precedence. Unknown keys are rejected.
+func DecodeJobSpec(job Job, out any) ValidationErrors {
+	errs := decode(job.Settings, out, "")
+	return append(errs, decode(job.Spec, out, "spec.")...)
+}
+
+// DecodeEnvironmentSpec decodes the environment's provider settings into out
+// in the same way as DecodeJobSpec.
+func DecodeEnvironmentSpec(env Environment, out any) ValidationErrors {
+	errs := decode(env.Settings, out, "")
+	return append(errs, decode(env.Spec, out, "spec.")...)
+}
+
+// DecodeAssertions decodes the job's provider-specific assertions into out,
+// which must be a pointer to a struct with yaml tags, or nil for a provider
+// that has none. Unknown assertions are rejected.
+func DecodeAssertions(job Job, out any) ValidationErrors {
+	return decode(job.Assertions.Settings, out, "assertions.")
+}
+
+// decode decodes values into out, reporting errors against prefix followed
+// by the key they belong to.
+func decode(values map[string]any, out any, prefix string) ValidationErrors {
+	if len(values) == 0 {
+		return nil
+	}
+	if out == nil {
+		out = &struct{}{}
+	}
+
+	field := strings.TrimSuffix(prefix, ".")
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return ValidationErrors{{Field: field, Message: err.Error()}}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(out)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return ValidationErrors{{Field: field, Message: err.Error()}}
+	}
+
+	keys := keyLines(data)
+	var errs ValidationErrors
+	for _, msg := range typeErr.Errors {
+		name := ""
+		if m := linePattern.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			name = keyAt(keys, line)
+			msg = strings.TrimPrefix(msg, m[0])
+		}
+
+		if m := unknownFieldPattern.FindStringSubmatch(msg); m != nil {
+			if name != m[1] && name != "" {
+				name += "." + m[1]
+			} else {
+				name = m[1]
+			}
+			errs = append(errs, ValidationError{Field: prefix + name, Message: "unknown field"})
+			continue
+		}
+
+		key := field
+		if name != "" {
+			key = prefix + name
+		}
+		errs = append(errs, ValidationError{Field: key, Message: strings.TrimPrefix(msg, "yaml: ")})
+	}
+	return errs
+}
+
+// keyLine is a top-level key of a document and the line it starts on.
+type keyLine struct {
+	name string
+	line int
+}
+
+// keyLines returns the top-level keys of a mapping document in order.
+func keyLines(data []byte) []keyLine {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+
+	var keys []keyLine
+	mapping := doc.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		keys = append(keys, keyLine{name: mapping.Content[i].Value, line: mapping.Content[i].Line})
+	}
+	return keys
+}
+
+// keyAt returns the top-level key whose value contains line.
+func keyAt(keys []keyLine, line int) string {
+	name := ""
+	for _, k := range keys {
+		if k.line > line {
+			break
+		}
+		name = k.name
+	}
+	return name
+}
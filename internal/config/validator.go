@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
@@ -62,6 +63,13 @@ func validateDefaults(defaults Defaults) ValidationErrors {
 		})
 	}
 
+	if defaults.MaxBodySize < 0 {
+		errs = append(errs, ValidationError{
+			Field:   "defaults.max_body_size",
+			Message: "must not be negative",
+		})
+	}
+
 	return errs
 }
 
@@ -87,29 +95,87 @@ func validateEnvironments(environments map[string]Environment) ValidationErrors
 	var errs ValidationErrors
 
 	for name, env := range environments {
+		prefix := fmt.Sprintf("environments.%s", name)
+
 		if env.Type == "" {
 			errs = append(errs, ValidationError{
-				Field:   fmt.Sprintf("environments.%s.type", name),
+				Field:   prefix + ".type",
 				Message: "is required",
 			})
+		} else if validator, ok := typeValidator(env.Type); ok {
+			errs = append(errs, prefixErrors(validator.ValidateEnvironment(env), prefix)...)
+		} else {
+			errs = append(errs, invalidType(env.Type, prefix+".type"))
 		}
 
-		validTypes := map[string]bool{
-			"rundeck": true,
-			"http":    true,
-		}
+		errs = append(errs, validateAuth(env.Auth, prefix+".auth")...)
+		errs = append(errs, validateTransport(env.Transport, prefix+".transport")...)
+		errs = append(errs, validateTLS(env.TLS, prefix+".tls")...)
+	}
+
+	return errs
+}
+
+// invalidType reports a type that no provider registered.
+func invalidType(typ, field string) ValidationError {
+	return ValidationError{
+		Field:   field,
+		Message: fmt.Sprintf("invalid type '%s', must be one of: %s", typ, strings.Join(registeredTypeNames(), ", ")),
+	}
+}
+
+func validateAuth(auth Auth, prefix string) ValidationErrors {
+	var errs ValidationErrors
 
-		if env.Type != "" && !validTypes[env.Type] {
+	validTypes := map[string]bool{
+		"":        true,
+		"none":    true,
+		"bearer":  true,
+		"basic":   true,
+		"api_key": true,
+		"digest":  true,
+	}
+
+	if !validTypes[auth.Type] {
+		errs = append(errs, ValidationError{
+			Field:   prefix + ".type",
+			Message: fmt.Sprintf("invalid type '%s', must be one of: none, bearer, basic, api_key, digest", auth.Type),
+		})
+		return errs
+	}
+
+	switch auth.Type {
+	case "basic", "digest":
+		if auth.Username == "" {
 			errs = append(errs, ValidationError{
-				Field:   fmt.Sprintf("environments.%s.type", name),
-				Message: fmt.Sprintf("invalid type '%s', must be one of: rundeck, http", env.Type),
+				Field:   prefix + ".username",
+				Message: fmt.Sprintf("is required for %s auth", auth.Type),
 			})
 		}
+	}
 
-		if env.URL == "" {
+	return errs
+}
+
+func validateTransport(t Transport, prefix string) ValidationErrors {
+	var errs ValidationErrors
+
+	fields := []struct {
+		name     string
+		negative bool
+	}{
+		{"keep_alive", t.KeepAlive < 0},
+		{"idle_conn_timeout", t.IdleConnTimeout < 0},
+		{"max_idle_conns", t.MaxIdleConns < 0},
+		{"max_idle_conns_per_host", t.MaxIdleConnsPerHost < 0},
+		{"max_conns_per_host", t.MaxConnsPerHost < 0},
+	}
+
+	for _, f := range fields {
+		if f.negative {
 			errs = append(errs, ValidationError{
-				Field:   fmt.Sprintf("environments.%s.url", name),
-				Message: "is required",
+				Field:   prefix + "." + f.name,
+				Message: "must not be negative",
 			})
 		}
 	}
@@ -117,6 +183,19 @@ func validateEnvironments(environments map[string]Environment) ValidationErrors
 	return errs
 }
 
+func validateTLS(t TLS, prefix string) ValidationErrors {
+	var errs ValidationErrors
+
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		errs = append(errs, ValidationError{
+			Field:   prefix,
+			Message: "cert_file and key_file must be set together",
+		})
+	}
+
+	return errs
+}
+
 func validateJobs(cfg *Config) ValidationErrors {
 	var errs ValidationErrors
 	jobNames := make(map[string]bool)
@@ -156,73 +235,71 @@ func validateJobs(cfg *Config) ValidationErrors {
 				Field:   prefix + ".type",
 				Message: "is required",
 			})
+		} else if validator, ok := typeValidator(job.Type); ok {
+			errs = append(errs, prefixErrors(validator.ValidateJob(job, cfg.Environments[job.Environment]), prefix)...)
+		} else {
+			errs = append(errs, invalidType(job.Type, prefix+".type"))
 		}
-
-		validTypes := map[string]bool{
-			"rundeck": true,
-			"http":    true,
-		}
-
-		if job.Type != "" && !validTypes[job.Type] {
-			errs = append(errs, ValidationError{
-				Field:   prefix + ".type",
-				Message: fmt.Sprintf("invalid type '%s', must be one of: rundeck, http", job.Type),
-			})
-		}
-
-		errs = append(errs, validateJobByType(job, prefix)...)
 	}
 
 	return errs
 }
 
-func validateJobByType(job Job, prefix string) ValidationErrors {
+// ValidateBodyAssertions validates JSON, XPath, CSS and text assertions.
+// Providers that inspect a response body use it from their TypeValidator.
+func ValidateBodyAssertions(a Assertions, prefix string) ValidationErrors {
 	var errs ValidationErrors
 
-	switch job.Type {
-	case "rundeck":
-		if job.JobID == "" {
+	for i, assertion := range a.JSON {
+		field := fmt.Sprintf("%s.json[%d]", prefix, i)
+		if !strings.HasPrefix(assertion.Path, "$.") {
 			errs = append(errs, ValidationError{
-				Field:   prefix + ".job_id",
-				Message: "is required for rundeck jobs",
+				Field:   field + ".path",
+				Message: "must start with $.",
 			})
 		}
-		if job.Project == "" {
+		errs = append(errs, ValidateMatcher(assertion.Matcher, field)...)
+	}
+
+	for i, assertion := range a.XPath {
+		field := fmt.Sprintf("%s.xpath[%d]", prefix, i)
+		if assertion.Path == "" {
 			errs = append(errs, ValidationError{
-				Field:   prefix + ".project",
-				Message: "is required for rundeck jobs",
+				Field:   field + ".path",
+				Message: "is required",
 			})
 		}
+		errs = append(errs, ValidateMatcher(assertion.Matcher, field)...)
+	}
 
-	case "http":
-		if job.Method == "" {
+	for i, assertion := range a.CSS {
+		field := fmt.Sprintf("%s.css[%d]", prefix, i)
+		if assertion.Selector == "" {
 			errs = append(errs, ValidationError{
-				Field:   prefix + ".method",
-				Message: "is required for http jobs",
+				Field:   field + ".selector",
+				Message: "is required",
 			})
 		}
+		errs = append(errs, ValidateMatcher(assertion.Matcher, field)...)
+	}
 
-		validMethods := map[string]bool{
-			"GET":     true,
-			"POST":    true,
-			"PUT":     true,
-			"DELETE":  true,
-			"PATCH":   true,
-			"HEAD":    true,
-			"OPTIONS": true,
-		}
+	for i, m := range a.Text {
+		errs = append(errs, ValidateMatcher(m, fmt.Sprintf("%s.text[%d]", prefix, i))...)
+	}
 
-		if job.Method != "" && !validMethods[job.Method] {
-			errs = append(errs, ValidationError{
-				Field:   prefix + ".method",
-				Message: fmt.Sprintf("invalid method '%s'", job.Method),
-			})
-		}
+	return errs
+}
+
+// ValidateMatcher validates a matcher's operators. Providers use it for
+// the matchers in their own assertions.
+func ValidateMatcher(m Matcher, prefix string) ValidationErrors {
+	var errs ValidationErrors
 
-		if job.Path == "" {
+	if m.Matches != "" {
+		if _, err := regexp.Compile(m.Matches); err != nil {
 			errs = append(errs, ValidationError{
-				Field:   prefix + ".path",
-				Message: "is required for http jobs",
+				Field:   prefix + ".matches",
+				Message: fmt.Sprintf("invalid pattern: %v", err),
 			})
 		}
 	}
@@ -73,7 +73,12 @@ func (w *ConsoleWriter) WriteJobComplete(index, total int, result *providers.Res
 	statusStr := w.formatStatus(result.Status, result.Passed())
 	duration := result.Duration.Round(time.Millisecond)
 
-	if result.Passed() {
+	if result.Status == providers.StatusSkipped {
+		if reason, ok := result.Details["reason"].(string); ok {
+			w.printf("      %s\n", reason)
+		}
+		w.printf("      %s\n\n", statusStr)
+	} else if result.Passed() {
 		w.printf("      Completed in %s\n", duration)
 		w.printf("      %s\n\n", statusStr)
 	} else {
@@ -94,6 +99,9 @@ func (w *ConsoleWriter) WriteResult(result *runner.RunResult) {
 	w.printf("Total:    %d\n", result.Summary.Total)
 	w.printf("Passed:   %s%d%s\n", w.color(colorGreen), result.Summary.Passed, w.color(colorReset))
 	w.printf("Failed:   %s%d%s\n", w.failedColor(result.Summary.Failed), result.Summary.Failed, w.color(colorReset))
+	if result.Summary.Skipped > 0 {
+		w.printf("Skipped:  %s%d%s\n", w.color(colorYellow), result.Summary.Skipped, w.color(colorReset))
+	}
 	w.printf("Duration: %s\n", result.Duration.Round(time.Second))
 
 	if failed := result.FailedResults(); len(failed) > 0 {
@@ -113,6 +121,12 @@ func (w *ConsoleWriter) WriteResult(result *runner.RunResult) {
 
 // formatStatus formats a status for display.
 func (w *ConsoleWriter) formatStatus(status providers.Status, passed bool) string {
+	switch {
+	case status == providers.StatusSkipped:
+		return fmt.Sprintf("%s[SKIP]%s", w.color(colorYellow), w.color(colorReset))
+	case status == providers.StatusPreflightPassed:
+		return fmt.Sprintf("%s[PREFLIGHT OK]%s", w.color(colorGreen), w.color(colorReset))
+	}
 	if passed {
 		return fmt.Sprintf("%s[PASS]%s", w.color(colorGreen), w.color(colorReset))
 	}
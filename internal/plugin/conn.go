@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxMessageSize is the largest message accepted from a plugin.
+	maxMessageSize = 16 << 20
+
+	// stderrTailSize is the number of bytes of plugin stderr kept for error
+	// messages.
+	stderrTailSize = 4096
+
+	// shutdownTimeout is how long a plugin has to exit after shutdown before
+	// it is killed.
+	shutdownTimeout = 5 * time.Second
+)
+
+// conn is a JSON-RPC connection to a running plugin process.
+type conn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr *tailBuffer
+	notify func(method string, params json.RawMessage)
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan *message
+	err     error
+	done    chan struct{}
+}
+
+// startConn starts the plugin at path. notify is called for every
+// notification the plugin sends.
+func startConn(path string, notify func(method string, params json.RawMessage)) (*conn, error) {
+	cmd := exec.Command(path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &tailBuffer{max: stderrTailSize}
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	c := &conn{
+		cmd:     cmd,
+		stdin:   stdin,
+		stderr:  stderr,
+		notify:  notify,
+		pending: make(map[int64]chan *message),
+		done:    make(chan struct{}),
+	}
+	go c.read(stdout)
+	return c, nil
+}
+
+// read dispatches messages from the plugin until its stdout closes, then
+// fails all pending calls.
+func (c *conn) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+
+	var err error
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var msg message
+		if err = json.Unmarshal(line, &msg); err != nil {
+			err = fmt.Errorf("invalid message from plugin: %w", err)
+			break
+		}
+
+		if msg.ID == nil {
+			if msg.Method != "" && c.notify != nil {
+				c.notify(msg.Method, msg.Params)
+			}
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[*msg.ID]
+		delete(c.pending, *msg.ID)
+		c.mu.Unlock()
+		if ok {
+			ch <- &msg
+		}
+	}
+	if err == nil {
+		err = scanner.Err()
+	}
+
+	waitErr := c.cmd.Wait()
+	if err == nil {
+		err = errors.New("plugin exited")
+		if waitErr != nil {
+			err = fmt.Errorf("plugin exited: %w", waitErr)
+		}
+	}
+	if tail := strings.TrimSpace(c.stderr.String()); tail != "" {
+		err = fmt.Errorf("%w: %s", err, tail)
+	}
+
+	c.mu.Lock()
+	c.err = err
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+	close(c.done)
+}
+
+// call sends a request and decodes the result into out. If ctx is done
+// first, the plugin is sent a cancel notification and ctx's error returned.
+func (c *conn) call(ctx context.Context, method string, params, out any) error {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan *message, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	if err := c.send(&id, method, params); err != nil {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		// A write fails when the plugin has exited; prefer the exit error,
+		// which includes its stderr.
+		select {
+		case <-c.done:
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.err
+		case <-time.After(time.Second):
+			return err
+		}
+	}
+
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.err
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if out != nil {
+			if err := json.Unmarshal(msg.Result, out); err != nil {
+				return fmt.Errorf("invalid %s result from plugin: %w", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		_ = c.send(nil, MethodCancel, CancelParams{ID: id})
+		return ctx.Err()
+	}
+}
+
+// send writes a request, or a notification when id is nil.
+func (c *conn) send(id *int64, method string, params any) error {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s params: %w", method, err)
+	}
+	data, err := json.Marshal(message{JSONRPC: "2.0", ID: id, Method: method, Params: raw})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write to plugin: %w", err)
+	}
+	return nil
+}
+
+// close sends shutdown and closes the plugin's stdin, then waits for it to
+// exit, killing it if it does not exit in time. The shutdown response is not
+// awaited; plugins that ignore shutdown still see end of input.
+func (c *conn) close() error {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.mu.Unlock()
+
+	_ = c.send(&id, MethodShutdown, struct{}{})
+	c.stdin.Close()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-time.After(shutdownTimeout):
+		_ = c.cmd.Process.Kill()
+		<-c.done
+		return errors.New("plugin did not exit after shutdown and was killed")
+	}
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
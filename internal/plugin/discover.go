@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// Discover returns the plugin executables found in dirs, then in the
+// directories on PATH when searchPath is set. When several plugins provide
+// the same type, the first one found wins. Missing directories are skipped.
+func Discover(dirs []string, searchPath bool) ([]string, error) {
+	if searchPath {
+		dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+	}
+
+	seen := make(map[string]bool)
+	var paths []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin directory %s: %w", dir, err)
+		}
+
+		var found []string
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), ExecutablePrefix) || entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			name := nameFromPath(path)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			found = append(found, path)
+		}
+		sort.Strings(found)
+		paths = append(paths, found...)
+	}
+
+	return paths, nil
+}
+
+// Load registers a provider for each plugin path with registry, which makes
+// its type valid in configuration. Plugins may not replace a provider that
+// is already registered. The returned providers must be closed.
+func Load(registry *providers.Registry, paths []string) ([]*Provider, error) {
+	var loaded []*Provider
+	for _, path := range paths {
+		p := NewProvider(path)
+		if _, err := registry.Get(p.Name()); err == nil {
+			return loaded, fmt.Errorf("plugin %s: provider %q is already registered", path, p.Name())
+		}
+
+		registry.Register(p)
+		loaded = append(loaded, p)
+	}
+	return loaded, nil
+}
+
+// CloseAll shuts down the plugins and returns the first error.
+func CloseAll(plugins []*Provider) error {
+	var first error
+	for _, p := range plugins {
+		if err := p.Close(); err != nil && first == nil {
+			first = fmt.Errorf("plugin %s: %w", p.Name(), err)
+		}
+	}
+	return first
+}
+
+// isExecutable reports whether path is a regular file that can be run.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return info.Mode().Perm()&0o111 != 0
+}
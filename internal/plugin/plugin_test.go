@@ -0,0 +1,337 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+var (
+	buildOnce  sync.Once
+	pluginPath string
+	buildErr   error
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if pluginPath != "" {
+		os.RemoveAll(filepath.Dir(pluginPath))
+	}
+	os.Exit(code)
+}
+
+// referencePlugin builds the reference file plugin once per test run.
+func referencePlugin(t *testing.T) string {
+	t.Helper()
+
+	buildOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "jprobe-plugin")
+		if err != nil {
+			buildErr = err
+			return
+		}
+		pluginPath = filepath.Join(dir, ExecutablePrefix+"file")
+		if runtime.GOOS == "windows" {
+			pluginPath += ".exe"
+		}
+		cmd := exec.Command("go", "build", "-o", pluginPath, "../../examples/plugins/jprobe-provider-file")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			buildErr = err
+			t.Logf("%s", out)
+		}
+	})
+	if buildErr != nil {
+		t.Fatalf("failed to build reference plugin: %v", buildErr)
+	}
+	return pluginPath
+}
+
+// conformancePlugin returns the plugin the conformance tests run against:
+// $JPROBE_PLUGIN when set, otherwise the reference plugin.
+func conformancePlugin(t *testing.T) string {
+	if path := os.Getenv("JPROBE_PLUGIN"); path != "" {
+		return path
+	}
+	return referencePlugin(t)
+}
+
+// writeScript writes a shell script plugin named jprobe-provider-<name>.
+func writeScript(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on windows")
+	}
+	path := filepath.Join(dir, ExecutablePrefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatalf("failed to write plugin: %v", err)
+	}
+	return path
+}
+
+// TestConformance checks the protocol behaviour every plugin must have. Run
+// it against your own plugin with:
+//
+//	JPROBE_PLUGIN=/path/to/jprobe-provider-x go test ./internal/plugin -run Conformance
+func TestConformance(t *testing.T) {
+	path := conformancePlugin(t)
+	name := nameFromPath(path)
+
+	c, err := startConn(path, nil)
+	if err != nil {
+		t.Fatalf("failed to start plugin: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Run("initialize", func(t *testing.T) {
+		var info InitializeResult
+		if err := c.call(ctx, MethodInitialize, InitializeParams{ProtocolVersion: ProtocolVersion, HostVersion: "test"}, &info); err != nil {
+			t.Fatalf("initialize error = %v", err)
+		}
+		if info.Name != name {
+			t.Errorf("name = %q, want %q", info.Name, name)
+		}
+		if info.ProtocolVersion != ProtocolVersion {
+			t.Errorf("protocol_version = %d, want %d", info.ProtocolVersion, ProtocolVersion)
+		}
+	})
+
+	env := Environment{Name: "conformance", Type: name}
+	job := Job{Name: "conformance", Environment: "conformance", Type: name, TimeoutMS: 5000}
+
+	t.Run("validate_environment", func(t *testing.T) {
+		var out ValidateResult
+		if err := c.call(ctx, MethodValidateEnvironment, ValidateEnvironmentParams{Environment: env}, &out); err != nil {
+			t.Fatalf("validate_environment error = %v", err)
+		}
+	})
+
+	t.Run("validate_job", func(t *testing.T) {
+		var out ValidateResult
+		if err := c.call(ctx, MethodValidateJob, ValidateJobParams{Job: job, Environment: env}, &out); err != nil {
+			t.Fatalf("validate_job error = %v", err)
+		}
+		for _, e := range out.Errors {
+			if e.Field == "" || e.Message == "" {
+				t.Errorf("validation error %+v must have a field and message", e)
+			}
+		}
+	})
+
+	t.Run("execute returns a terminal status", func(t *testing.T) {
+		var out ExecuteResult
+		if err := c.call(ctx, MethodExecute, ExecuteParams{Job: job, Environment: env}, &out); err != nil {
+			t.Fatalf("execute error = %v", err)
+		}
+		if _, ok := terminalStatuses[out.Status]; !ok {
+			t.Errorf("status = %q, want succeeded, failed, aborted or timed_out", out.Status)
+		}
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		err := c.call(ctx, "no_such_method", struct{}{}, nil)
+		if _, ok := err.(*RPCError); !ok {
+			t.Errorf("error = %v, want a JSON-RPC error response", err)
+		}
+	})
+
+	t.Run("cancel notification is ignored or honoured", func(t *testing.T) {
+		if err := c.send(nil, MethodCancel, CancelParams{ID: 999}); err != nil {
+			t.Fatalf("cancel error = %v", err)
+		}
+		var info InitializeResult
+		if err := c.call(ctx, MethodInitialize, InitializeParams{ProtocolVersion: ProtocolVersion}, &info); err != nil {
+			t.Errorf("plugin stopped responding after cancel: %v", err)
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		if err := c.close(); err != nil {
+			t.Errorf("close error = %v", err)
+		}
+	})
+}
+
+func TestFilePlugin(t *testing.T) {
+	provider := NewProvider(referencePlugin(t))
+	defer provider.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "backup.tar.gz"), []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env := config.Environment{Type: "file", Spec: map[string]any{"root": dir}}
+
+	var messages []string
+	provider.SetProgressCallback(func(jobName string, status providers.Status, message string) {
+		messages = append(messages, jobName+": "+message)
+	})
+
+	tests := []struct {
+		name       string
+		spec       map[string]any
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "exists",
+			spec:       map[string]any{"path": "backup.tar.gz", "max_age": "1h"},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "too small",
+			spec:       map[string]any{"path": "backup.tar.gz", "min_size": 1024},
+			wantStatus: providers.StatusFailed,
+			wantError:  "file is 10 bytes, min 1024",
+		},
+		{
+			name:       "missing",
+			spec:       map[string]any{"path": "missing.tar.gz"},
+			wantStatus: providers.StatusFailed,
+			wantError:  "no such file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{Name: tt.name, Environment: "backups", Type: "file", Timeout: 5 * time.Second, Spec: tt.spec}
+
+			result, err := provider.Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+
+	// Progress is delivered before the response, so it has arrived by now.
+	if len(messages) != len(tests) || !strings.HasPrefix(messages[0], "exists: Checking ") {
+		t.Errorf("progress messages = %q", messages)
+	}
+}
+
+func TestLoadValidatesWithPlugin(t *testing.T) {
+	registry := providers.NewRegistry()
+	loaded, err := Load(registry, []string{referencePlugin(t)})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	defer CloseAll(loaded)
+	defer config.UnregisterType("file")
+
+	if _, err := registry.Get("file"); err != nil {
+		t.Fatalf("plugin not registered: %v", err)
+	}
+
+	cfg := &config.Config{
+		Defaults:     config.Defaults{Timeout: time.Minute, PollInterval: time.Second},
+		Environments: map[string]config.Environment{"backups": {Type: "file"}},
+		Jobs: []config.Job{
+			{Name: "ok", Environment: "backups", Type: "file", Spec: map[string]any{"path": "a"}},
+			{Name: "bad", Environment: "backups", Type: "file", Spec: map[string]any{"max_age": "soon"}},
+		},
+	}
+
+	err = config.Validate(cfg)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"jobs[1].spec.path: is required", "jobs[1].spec.max_age: must be a positive duration"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "jobs[0]") || strings.Contains(err.Error(), "environments.backups") {
+		t.Errorf("unexpected errors: %v", err)
+	}
+
+	if _, err := Load(registry, []string{referencePlugin(t)}); err == nil {
+		t.Error("loading a plugin twice should fail")
+	}
+}
+
+func TestPluginCrash(t *testing.T) {
+	path := writeScript(t, t.TempDir(), "broken", "echo 'missing credentials' >&2\nexit 3\n")
+
+	provider := NewProvider(path)
+	defer provider.Close()
+
+	job := config.Job{Name: "crash", Type: "broken", Timeout: 5 * time.Second}
+	result, _ := provider.Execute(context.Background(), job, config.Environment{Type: "broken"})
+	if result.Status != providers.StatusFailed {
+		t.Errorf("Status = %s, want failed", result.Status)
+	}
+	if !strings.Contains(result.Error, "plugin initialize failed") || !strings.Contains(result.Error, "missing credentials") {
+		t.Errorf("Error = %q, want initialize failure with stderr", result.Error)
+	}
+}
+
+func TestPluginTimeout(t *testing.T) {
+	// The plugin answers initialize and then never answers execute.
+	init := `{"jsonrpc":"2.0","id":1,"result":{"name":"slow","protocol_version":1}}`
+	path := writeScript(t, t.TempDir(), "slow", "read line\necho '"+init+"'\nwhile read line; do :; done\n")
+
+	provider := NewProvider(path)
+	defer provider.Close()
+
+	job := config.Job{Name: "slow", Type: "slow", Timeout: 200 * time.Millisecond}
+	result, _ := provider.Execute(context.Background(), job, config.Environment{Type: "slow"})
+	if result.Status != providers.StatusTimedOut || result.Error != "timeout after 200ms" {
+		t.Errorf("result = %s %q, want timed_out", result.Status, result.Error)
+	}
+}
+
+func TestPluginNameMismatch(t *testing.T) {
+	init := `{"jsonrpc":"2.0","id":1,"result":{"name":"other","protocol_version":1}}`
+	path := writeScript(t, t.TempDir(), "named", "read line\necho '"+init+"'\nwhile read line; do :; done\n")
+
+	provider := NewProvider(path)
+	defer provider.Close()
+
+	errs := provider.ValidateEnvironment(config.Environment{Type: "named"})
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `plugin reports name "other", want "named"`) {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not used on windows")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+	writeScript(t, first, "alpha", "")
+	writeScript(t, second, "alpha", "")
+	writeScript(t, second, "beta", "")
+	if err := os.WriteFile(filepath.Join(second, ExecutablePrefix+"gamma"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(second, "other-tool"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := Discover([]string{first, filepath.Join(first, "missing"), second}, false)
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []string{filepath.Join(first, ExecutablePrefix+"alpha"), filepath.Join(second, ExecutablePrefix+"beta")}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Discover() = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,146 @@
+// Package plugin runs external provider plugins. A plugin is an executable
+// named jprobe-provider-<type> that speaks JSON-RPC 2.0 over stdin and
+// stdout, one message per line, and provides the job type <type>.
+//
+// jprobe sends the requests initialize, validate_environment, validate_job,
+// execute and shutdown, and the notification cancel. Plugins may send
+// progress notifications while executing a job. Anything written to stderr
+// is kept for error messages.
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// ProtocolVersion is the plugin protocol version spoken by this host.
+const ProtocolVersion = 1
+
+// ExecutablePrefix is the file name prefix of plugin executables.
+const ExecutablePrefix = "jprobe-provider-"
+
+// Method names.
+const (
+	MethodInitialize          = "initialize"
+	MethodValidateEnvironment = "validate_environment"
+	MethodValidateJob         = "validate_job"
+	MethodExecute             = "execute"
+	MethodShutdown            = "shutdown"
+	MethodCancel              = "cancel"
+	MethodProgress            = "progress"
+)
+
+// message is a JSON-RPC 2.0 request, notification or response.
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is a JSON-RPC error returned by a plugin.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return e.Message
+}
+
+// InitializeParams is sent once when a plugin is started.
+type InitializeParams struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	HostVersion     string `json:"host_version"`
+}
+
+// InitializeResult describes the plugin. Name must match the executable's
+// type suffix.
+type InitializeResult struct {
+	Name            string                  `json:"name"`
+	ProtocolVersion int                     `json:"protocol_version"`
+	Description     string                  `json:"description,omitempty"`
+	Capabilities    *providers.Capabilities `json:"capabilities,omitempty"`
+}
+
+// Auth is the authentication configured for an environment.
+type Auth struct {
+	Type     string `json:"type,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	APIKey   string `json:"api_key,omitempty"`
+	Header   string `json:"header,omitempty"`
+}
+
+// Environment is the environment a job runs against.
+type Environment struct {
+	Name    string            `json:"name,omitempty"`
+	Type    string            `json:"type"`
+	URL     string            `json:"url,omitempty"`
+	Auth    Auth              `json:"auth"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Spec    map[string]any    `json:"spec,omitempty"`
+}
+
+// Job is a job definition. TimeoutMS is the time the plugin has to finish.
+type Job struct {
+	Name           string         `json:"name"`
+	Environment    string         `json:"environment"`
+	Type           string         `json:"type"`
+	TimeoutMS      int64          `json:"timeout_ms"`
+	PollIntervalMS int64          `json:"poll_interval_ms,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`
+	Spec           map[string]any `json:"spec,omitempty"`
+}
+
+// ValidateEnvironmentParams asks the plugin to check an environment.
+type ValidateEnvironmentParams struct {
+	Environment Environment `json:"environment"`
+}
+
+// ValidateJobParams asks the plugin to check a job and its environment.
+type ValidateJobParams struct {
+	Job         Job         `json:"job"`
+	Environment Environment `json:"environment"`
+}
+
+// FieldError is a validation error for a field such as "spec.path".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidateResult lists validation errors; an empty list means valid.
+type ValidateResult struct {
+	Errors []FieldError `json:"errors"`
+}
+
+// ExecuteParams asks the plugin to run a job.
+type ExecuteParams struct {
+	Job         Job         `json:"job"`
+	Environment Environment `json:"environment"`
+}
+
+// ExecuteResult is the outcome of a job. Status is one of succeeded,
+// failed, aborted or timed_out.
+type ExecuteResult struct {
+	Status  string         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// ProgressParams reports progress of a running job.
+type ProgressParams struct {
+	Job     string `json:"job"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// CancelParams asks the plugin to stop the request with the given ID.
+type CancelParams struct {
+	ID int64 `json:"id"`
+}
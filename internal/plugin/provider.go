@@ -0,0 +1,314 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultTimeout is used when neither the job nor the defaults set one.
+	defaultTimeout = 10 * time.Minute
+
+	// validateTimeout bounds each validation request.
+	validateTimeout = 10 * time.Second
+
+	// initializeTimeout bounds the handshake with a newly started plugin.
+	initializeTimeout = 10 * time.Second
+)
+
+// HostVersion is reported to plugins during the handshake.
+var HostVersion = "dev"
+
+// terminalStatuses are the statuses a plugin may return from execute.
+var terminalStatuses = map[string]providers.Status{
+	string(providers.StatusSucceeded): providers.StatusSucceeded,
+	string(providers.StatusFailed):    providers.StatusFailed,
+	string(providers.StatusAborted):   providers.StatusAborted,
+	string(providers.StatusTimedOut):  providers.StatusTimedOut,
+}
+
+// Provider runs jobs through a plugin executable. The plugin is started on
+// first use and kept running until Close.
+type Provider struct {
+	name string
+	path string
+
+	mu         sync.Mutex
+	conn       *conn
+	info       InitializeResult
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a provider for the plugin executable at path. The
+// provider name is the part of the file name after ExecutablePrefix.
+func NewProvider(path string) *Provider {
+	return &Provider{name: nameFromPath(path), path: path}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// Path returns the plugin executable path.
+func (p *Provider) Path() string {
+	return p.path
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onProgress = cb
+}
+
+// Init starts the plugin if it is not running, so a plugin that cannot
+// start fails before its first job rather than during it.
+func (p *Provider) Init(ctx context.Context, name string, env config.Environment) error {
+	_, err := p.connect()
+	return err
+}
+
+// Capabilities returns the capabilities the plugin reported during the
+// handshake, starting it if needed. A plugin that cannot start reports none.
+func (p *Provider) Capabilities() providers.Capabilities {
+	if _, err := p.connect(); err != nil {
+		return providers.Capabilities{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.info.Capabilities == nil {
+		return providers.Capabilities{}
+	}
+	return *p.info.Capabilities
+}
+
+// Execute sends the job to the plugin and returns the result it reports.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        p.name,
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     map[string]interface{}{"plugin": p.path},
+	}
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c, err := p.connect()
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	params := ExecuteParams{
+		Job:         wireJob(job, timeout),
+		Environment: wireEnvironment(job.Environment, env),
+	}
+
+	var out ExecuteResult
+	if err := c.call(ctx, MethodExecute, params, &out); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Status = providers.StatusTimedOut
+			result.Error = fmt.Sprintf("timeout after %s", timeout)
+			result.FinishedAt = time.Now()
+			result.Duration = result.FinishedAt.Sub(result.StartedAt)
+			return result, nil
+		}
+		return p.fail(result, fmt.Sprintf("plugin execute failed: %v", err)), nil
+	}
+
+	status, ok := terminalStatuses[out.Status]
+	if !ok {
+		return p.fail(result, fmt.Sprintf("plugin returned invalid status %q", out.Status)), nil
+	}
+
+	for k, v := range out.Details {
+		result.Details[k] = v
+	}
+	result.Status = status
+	result.Error = out.Error
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	if status == providers.StatusSucceeded && job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		result.Status = providers.StatusFailed
+		result.Error = fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration)
+	}
+
+	return result, nil
+}
+
+// ValidateEnvironment asks the plugin to validate an environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	params := ValidateEnvironmentParams{Environment: wireEnvironment("", env)}
+	return p.validate(MethodValidateEnvironment, params)
+}
+
+// ValidateJob asks the plugin to validate a job and its environment.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	params := ValidateJobParams{
+		Job:         wireJob(job, job.GetTimeout(config.Defaults{Timeout: defaultTimeout})),
+		Environment: wireEnvironment(job.Environment, env),
+	}
+	return p.validate(MethodValidateJob, params)
+}
+
+// validate runs a validation request. Failing to reach the plugin is
+// reported as an error on the type field.
+func (p *Provider) validate(method string, params any) config.ValidationErrors {
+	c, err := p.connect()
+	if err != nil {
+		return config.ValidationErrors{{Field: "type", Message: err.Error()}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), validateTimeout)
+	defer cancel()
+
+	var out ValidateResult
+	if err := c.call(ctx, method, params, &out); err != nil {
+		return config.ValidationErrors{{Field: "type", Message: fmt.Sprintf("plugin %s failed: %v", method, err)}}
+	}
+
+	errs := make(config.ValidationErrors, len(out.Errors))
+	for i, e := range out.Errors {
+		errs[i] = config.ValidationError{Field: e.Field, Message: e.Message}
+	}
+	return errs
+}
+
+// Close shuts the plugin down if it was started.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	c := p.conn
+	p.conn = nil
+	p.mu.Unlock()
+
+	if c == nil {
+		return nil
+	}
+	return c.close()
+}
+
+// connect returns the connection to the plugin, starting it and performing
+// the handshake if it is not running.
+func (p *Provider) connect() (*conn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn != nil {
+		select {
+		case <-p.conn.done:
+			p.conn = nil
+		default:
+			return p.conn, nil
+		}
+	}
+
+	c, err := startConn(p.path, p.handleNotification)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), initializeTimeout)
+	defer cancel()
+
+	var info InitializeResult
+	params := InitializeParams{ProtocolVersion: ProtocolVersion, HostVersion: HostVersion}
+	if err := c.call(ctx, MethodInitialize, params, &info); err != nil {
+		c.close()
+		return nil, fmt.Errorf("plugin initialize failed: %w", err)
+	}
+	if info.ProtocolVersion != ProtocolVersion {
+		c.close()
+		return nil, fmt.Errorf("plugin speaks protocol version %d, want %d", info.ProtocolVersion, ProtocolVersion)
+	}
+	if info.Name != p.name {
+		c.close()
+		return nil, fmt.Errorf("plugin reports name %q, want %q", info.Name, p.name)
+	}
+
+	p.conn = c
+	p.info = info
+	return c, nil
+}
+
+// handleNotification forwards progress notifications to the callback.
+func (p *Provider) handleNotification(method string, params json.RawMessage) {
+	if method != MethodProgress {
+		return
+	}
+
+	var progress ProgressParams
+	if err := json.Unmarshal(params, &progress); err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	cb := p.onProgress
+	p.mu.Unlock()
+	if cb != nil {
+		cb(progress.Job, providers.Status(progress.Status), progress.Message)
+	}
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// wireJob converts a job to its protocol form.
+func wireJob(job config.Job, timeout time.Duration) Job {
+	return Job{
+		Name:           job.Name,
+		Environment:    job.Environment,
+		Type:           job.Type,
+		TimeoutMS:      timeout.Milliseconds(),
+		PollIntervalMS: job.PollInterval.Milliseconds(),
+		Tags:           job.Tags,
+		Spec:           job.Spec,
+	}
+}
+
+// wireEnvironment converts an environment to its protocol form.
+func wireEnvironment(name string, env config.Environment) Environment {
+	return Environment{
+		Name: name,
+		Type: env.Type,
+		URL:  env.URL,
+		Auth: Auth{
+			Type:     env.Auth.Type,
+			Token:    env.Auth.Token,
+			Username: env.Auth.Username,
+			Password: env.Auth.Password,
+			APIKey:   env.Auth.APIKey,
+			Header:   env.Auth.Header,
+		},
+		Headers: env.Headers,
+		Spec:    env.Spec,
+	}
+}
+
+// nameFromPath derives the provider name from a plugin file name, dropping
+// the prefix and any .exe extension.
+func nameFromPath(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), ExecutablePrefix)
+	return strings.TrimSuffix(name, ".exe")
+}
@@ -0,0 +1,272 @@
+package airflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// Provider implements the Airflow DAG run provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+}
+
+// NewProvider creates a new Airflow provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "airflow"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Capabilities describes what the Airflow provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Polling: true}
+}
+
+// Execute triggers a DAG run, waits for it to finish and returns the result.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "airflow",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.Timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+
+	// A paused DAG accepts new runs but never schedules them, which would
+	// otherwise surface as a confusing timeout.
+	dag, err := client.GetDag(ctx, spec.JobID)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to get DAG: %v", err)), nil
+	}
+	if dag.IsPaused {
+		return p.fail(result, fmt.Sprintf("DAG '%s' is paused, unpause it before triggering runs", spec.JobID)), nil
+	}
+
+	p.reportProgress(job.Name, providers.StatusPending, "Triggering DAG run...")
+
+	run, err := client.TriggerDagRun(ctx, spec.JobID, spec.Conf)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to trigger DAG run: %v", err)), nil
+	}
+	result.Details["dag_run_id"] = run.DagRunID
+	result.Status = providers.StatusRunning
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("DAG run %s started", run.DagRunID))
+
+	run, err = p.pollDagRun(ctx, client, job.Name, spec.JobID, run.DagRunID, job.PollInterval)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("polling failed: %v", timeoutError(err, timeout))), nil
+	}
+
+	result.Details["dag_run_state"] = string(run.State)
+	result.Status = mapState(run.State)
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if job.Assertions.Status != "" {
+		if strings.EqualFold(string(run.State), job.Assertions.Status) {
+			result.Status = providers.StatusSucceeded
+		} else {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("expected state '%s', got '%s'",
+				strings.ToLower(job.Assertions.Status), run.State))
+		}
+	} else if result.Status != providers.StatusSucceeded {
+		failures = append(failures, fmt.Sprintf("DAG run finished with state %s", run.State))
+	}
+
+	if job.Assertions.MaxDuration > 0 {
+		duration := runDuration(run, result.Duration)
+		if duration > job.Assertions.MaxDuration {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+				duration, job.Assertions.MaxDuration))
+		}
+	}
+
+	if run.State == DagRunStateFailed || len(assertions.Tasks) > 0 {
+		tasks, err := client.GetTaskInstances(ctx, spec.JobID, run.DagRunID)
+		if err != nil {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("failed to get task instances: %v", err))
+		} else {
+			if failed := failedTasks(tasks); len(failed) > 0 {
+				result.Details["failed_tasks"] = failed
+			}
+			if taskFailures := checkTasks(tasks, assertions.Tasks); len(taskFailures) > 0 {
+				result.Status = providers.StatusFailed
+				failures = append(failures, taskFailures...)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("DAG run %s finished: %s", run.DagRunID, run.State))
+
+	return result, nil
+}
+
+// pollDagRun polls a DAG run until it reaches a terminal state.
+func (p *Provider) pollDagRun(ctx context.Context, client *Client, jobName, dagID, runID string, interval time.Duration) (*DagRun, error) {
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-ticker.C:
+			run, err := client.GetDagRun(ctx, dagID, runID)
+			if err != nil {
+				return nil, err
+			}
+
+			p.reportProgress(jobName, providers.StatusRunning,
+				fmt.Sprintf("Polling... (%s) state=%s", time.Since(start).Round(time.Second), run.State))
+
+			if run.State.IsTerminal() {
+				return run, nil
+			}
+		}
+	}
+}
+
+// failedTasks lists the failed task instances with their try numbers.
+func failedTasks(tasks []TaskInstance) []map[string]interface{} {
+	var failed []map[string]interface{}
+	for _, ti := range tasks {
+		if ti.State == "failed" {
+			failed = append(failed, map[string]interface{}{
+				"task_id":    ti.TaskID,
+				"try_number": ti.TryNumber,
+			})
+		}
+	}
+	return failed
+}
+
+// checkTasks compares task instance states against the expected states.
+func checkTasks(tasks []TaskInstance, expected map[string]string) []string {
+	byID := make(map[string]TaskInstance, len(tasks))
+	for _, ti := range tasks {
+		byID[ti.TaskID] = ti
+	}
+
+	taskIDs := make([]string, 0, len(expected))
+	for taskID := range expected {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	var failures []string
+	for _, taskID := range taskIDs {
+		want := strings.ToLower(expected[taskID])
+		ti, ok := byID[taskID]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("task '%s' not found in DAG run", taskID))
+			continue
+		}
+		if ti.State != want {
+			failures = append(failures, fmt.Sprintf("task '%s' expected state '%s', got '%s' (try %d)",
+				taskID, want, ti.State, ti.TryNumber))
+		}
+	}
+	return failures
+}
+
+// runDuration returns the duration reported by Airflow, falling back to the
+// locally measured duration if the run's dates are missing.
+func runDuration(run *DagRun, fallback time.Duration) time.Duration {
+	start, err := time.Parse(time.RFC3339, run.StartDate)
+	if err != nil {
+		return fallback
+	}
+	end, err := time.Parse(time.RFC3339, run.EndDate)
+	if err != nil {
+		return fallback
+	}
+	return end.Sub(start)
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// mapState maps an Airflow DAG run state to a provider status.
+func mapState(state DagRunState) providers.Status {
+	switch state {
+	case DagRunStateSuccess:
+		return providers.StatusSucceeded
+	case DagRunStateFailed:
+		return providers.StatusFailed
+	case DagRunStateQueued:
+		return providers.StatusPending
+	default:
+		return providers.StatusRunning
+	}
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
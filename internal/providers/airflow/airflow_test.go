@@ -0,0 +1,282 @@
+package airflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// fakeAirflow simulates the parts of the Airflow REST API used by the provider.
+type fakeAirflow struct {
+	mu       sync.Mutex
+	paused   bool
+	state    DagRunState
+	tasks    string
+	polls    int
+	conf     map[string]any
+	triggers int
+}
+
+func (f *fakeAirflow) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/dags/etl_daily", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "admin" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"title":"Unauthorized","status":401}`)
+			return
+		}
+		fmt.Fprintf(w, `{"dag_id":"etl_daily","is_paused":%t,"is_active":true}`, f.paused)
+	})
+
+	mux.HandleFunc("/api/v1/dags/etl_daily/dagRuns", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req TriggerDagRunRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		f.conf = req.Conf
+		f.triggers++
+		f.mu.Unlock()
+
+		fmt.Fprint(w, `{"dag_id":"etl_daily","dag_run_id":"manual__1","state":"queued"}`)
+	})
+
+	mux.HandleFunc("/api/v1/dags/etl_daily/dagRuns/manual__1", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.polls++
+		if f.polls < 2 {
+			fmt.Fprint(w, `{"dag_id":"etl_daily","dag_run_id":"manual__1","state":"running"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"dag_id":"etl_daily","dag_run_id":"manual__1","state":"%s",`+
+			`"start_date":"2024-01-01T00:00:00Z","end_date":"2024-01-01T00:05:00Z"}`, f.state)
+	})
+
+	mux.HandleFunc("/api/v1/dags/etl_daily/dagRuns/manual__1/taskInstances", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, f.tasks)
+	})
+
+	return mux
+}
+
+const (
+	successTasks = `{"task_instances":[
+		{"task_id":"extract","state":"success","try_number":1},
+		{"task_id":"load_warehouse","state":"success","try_number":1}
+	],"total_entries":2}`
+
+	failedTasksJSON = `{"task_instances":[
+		{"task_id":"extract","state":"success","try_number":1},
+		{"task_id":"load_warehouse","state":"failed","try_number":3},
+		{"task_id":"notify","state":"upstream_failed","try_number":0}
+	],"total_entries":3}`
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      DagRunState
+		tasks      string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+		wantFailed []map[string]interface{}
+	}{
+		{
+			name:       "success",
+			state:      DagRunStateSuccess,
+			tasks:      successTasks,
+			assertions: config.Assertions{Settings: map[string]any{"tasks": map[string]any{"load_warehouse": "success"}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "failed run reports failed tasks",
+			state:      DagRunStateFailed,
+			tasks:      failedTasksJSON,
+			wantStatus: providers.StatusFailed,
+			wantError:  "DAG run finished with state failed",
+			wantFailed: []map[string]interface{}{{"task_id": "load_warehouse", "try_number": 3}},
+		},
+		{
+			name:       "task assertion fails",
+			state:      DagRunStateFailed,
+			tasks:      failedTasksJSON,
+			assertions: config.Assertions{Status: "failed", Settings: map[string]any{"tasks": map[string]any{"load_warehouse": "success"}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "task 'load_warehouse' expected state 'success', got 'failed' (try 3)",
+			wantFailed: []map[string]interface{}{{"task_id": "load_warehouse", "try_number": 3}},
+		},
+		{
+			name:       "missing task",
+			state:      DagRunStateSuccess,
+			tasks:      successTasks,
+			assertions: config.Assertions{Settings: map[string]any{"tasks": map[string]any{"publish": "success"}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "task 'publish' not found in DAG run",
+		},
+		{
+			name:       "duration exceeded",
+			state:      DagRunStateSuccess,
+			tasks:      successTasks,
+			assertions: config.Assertions{MaxDuration: time.Minute},
+			wantStatus: providers.StatusFailed,
+			wantError:  "duration 5m0s exceeded max 1m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeAirflow{state: tt.state, tasks: tt.tasks}
+			server := httptest.NewServer(fake.handler())
+			defer server.Close()
+
+			result, err := NewProvider().Execute(context.Background(), testJob(tt.assertions), testEnv(server.URL))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if result.Details["dag_run_id"] != "manual__1" {
+				t.Errorf("dag_run_id = %v, want manual__1", result.Details["dag_run_id"])
+			}
+			if fake.conf["target"] != "staging" {
+				t.Errorf("conf = %v, want target=staging", fake.conf)
+			}
+
+			failed, _ := result.Details["failed_tasks"].([]map[string]interface{})
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("failed_tasks = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestExecutePausedDag(t *testing.T) {
+	fake := &fakeAirflow{paused: true}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	result, err := NewProvider().Execute(context.Background(), testJob(config.Assertions{}), testEnv(server.URL))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Status != providers.StatusFailed || !strings.Contains(result.Error, "DAG 'etl_daily' is paused") {
+		t.Errorf("Status = %s, Error = %q, want failed with paused error", result.Status, result.Error)
+	}
+	if fake.triggers != 0 {
+		t.Errorf("triggers = %d, want 0", fake.triggers)
+	}
+}
+
+func TestExecuteAuthError(t *testing.T) {
+	server := httptest.NewServer((&fakeAirflow{}).handler())
+	defer server.Close()
+
+	env := testEnv(server.URL)
+	env.Auth.Password = "wrong"
+
+	result, err := NewProvider().Execute(context.Background(), testJob(config.Assertions{}), env)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(result.Error, "airflow error [401 Unauthorized]") {
+		t.Errorf("Error = %q, want airflow 401 error", result.Error)
+	}
+}
+
+func TestClientAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   config.Auth
+		header string
+		want   string
+	}{
+		{
+			name:   "bearer",
+			auth:   config.Auth{Type: "bearer", Token: "t0ken"},
+			header: "Authorization",
+			want:   "Bearer t0ken",
+		},
+		{
+			name:   "api_key default header",
+			auth:   config.Auth{Type: "api_key", APIKey: "k3y"},
+			header: "X-API-Key",
+			want:   "k3y",
+		},
+		{
+			name:   "api_key custom header",
+			auth:   config.Auth{Type: "api_key", Header: "X-Airflow-Key", APIKey: "k3y"},
+			header: "X-Airflow-Key",
+			want:   "k3y",
+		},
+		{
+			name:   "digest",
+			auth:   config.Auth{Type: "digest", Username: "admin", Password: "secret"},
+			header: "Authorization",
+			want:   "Digest ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasPrefix(r.Header.Get(tt.header), tt.want) {
+					if tt.auth.Type == "digest" {
+						w.Header().Set("WWW-Authenticate", `Digest realm="airflow", nonce="abc", qop="auth"`)
+					}
+					w.WriteHeader(http.StatusUnauthorized)
+					fmt.Fprint(w, `{"title":"Unauthorized","status":401}`)
+					return
+				}
+				fmt.Fprint(w, `{"dag_id":"etl_daily","is_paused":false,"is_active":true}`)
+			}))
+			defer server.Close()
+
+			env := config.Environment{Type: "airflow", URL: server.URL, Auth: tt.auth}
+			if _, err := NewClient(env, nil).GetDag(context.Background(), "etl_daily"); err != nil {
+				t.Errorf("GetDag() error = %v", err)
+			}
+		})
+	}
+}
+
+func testJob(assertions config.Assertions) config.Job {
+	return config.Job{
+		Name:         "etl",
+		Type:         "airflow",
+		Spec:         map[string]any{"job_id": "etl_daily", "conf": map[string]any{"target": "staging"}},
+		Timeout:      5 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+		Assertions:   assertions,
+	}
+}
+
+func testEnv(url string) config.Environment {
+	return config.Environment{
+		Type: "airflow",
+		URL:  url,
+		Auth: config.Auth{Type: "basic", Username: "admin", Password: "secret"},
+	}
+}
@@ -0,0 +1,150 @@
+package airflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// taskInstancePageSize is the number of task instances requested per page.
+const taskInstancePageSize = 100
+
+// Client is an Airflow REST API client.
+type Client struct {
+	baseURL    string
+	auth       config.Auth
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Airflow client. If httpClient is nil, a dedicated
+// client is created; otherwise it is shared, and should come from a
+// providers.ClientPool, which handles digest auth for the environment.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = providers.NewEnvironmentClient(env)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(env.URL, "/"),
+		auth:       env.Auth,
+		headers:    env.Headers,
+		httpClient: httpClient,
+	}
+}
+
+// GetDag retrieves a DAG.
+func (c *Client) GetDag(ctx context.Context, dagID string) (*Dag, error) {
+	var dag Dag
+	if err := c.do(ctx, http.MethodGet, "/dags/"+url.PathEscape(dagID), nil, &dag); err != nil {
+		return nil, err
+	}
+	return &dag, nil
+}
+
+// TriggerDagRun triggers a new run of a DAG.
+func (c *Client) TriggerDagRun(ctx context.Context, dagID string, conf map[string]any) (*DagRun, error) {
+	if conf == nil {
+		conf = map[string]any{}
+	}
+
+	var run DagRun
+	path := "/dags/" + url.PathEscape(dagID) + "/dagRuns"
+	if err := c.do(ctx, http.MethodPost, path, TriggerDagRunRequest{Conf: conf}, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// GetDagRun retrieves a DAG run.
+func (c *Client) GetDagRun(ctx context.Context, dagID, runID string) (*DagRun, error) {
+	var run DagRun
+	path := "/dags/" + url.PathEscape(dagID) + "/dagRuns/" + url.PathEscape(runID)
+	if err := c.do(ctx, http.MethodGet, path, nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// GetTaskInstances retrieves all task instances of a DAG run.
+func (c *Client) GetTaskInstances(ctx context.Context, dagID, runID string) ([]TaskInstance, error) {
+	var all []TaskInstance
+
+	for offset := 0; ; offset += taskInstancePageSize {
+		var page TaskInstanceList
+		path := fmt.Sprintf("/dags/%s/dagRuns/%s/taskInstances?limit=%d&offset=%d",
+			url.PathEscape(dagID), url.PathEscape(runID), taskInstancePageSize, offset)
+		if err := c.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+
+		all = append(all, page.TaskInstances...)
+		if len(page.TaskInstances) < taskInstancePageSize || len(all) >= page.TotalEntries {
+			return all, nil
+		}
+	}
+}
+
+// do performs an API request and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		jsonData, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/api/v1"+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	providers.ApplyAuth(req, c.auth)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.parseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// parseError parses an error response from Airflow.
+func (c *Client) parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Title != "" {
+		if errResp.Detail != "" {
+			return fmt.Errorf("airflow error [%d %s]: %s", resp.StatusCode, errResp.Title, errResp.Detail)
+		}
+		return fmt.Errorf("airflow error [%d %s]", resp.StatusCode, errResp.Title)
+	}
+
+	return fmt.Errorf("airflow request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
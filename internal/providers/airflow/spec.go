@@ -0,0 +1,92 @@
+package airflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of an airflow job.
+type JobSpec struct {
+	// JobID is the ID of the DAG to trigger.
+	JobID string `yaml:"job_id"`
+
+	// Conf is passed to the DAG run as its configuration.
+	Conf map[string]any `yaml:"conf"`
+}
+
+// Assertions holds the Airflow-specific assertions of a job.
+type Assertions struct {
+	// Tasks maps task IDs to their expected state.
+	Tasks map[string]string `yaml:"tasks"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// validStates are the DAG run states that may be asserted.
+var validStates = map[string]bool{
+	"":        true,
+	"success": true,
+	"failed":  true,
+}
+
+// validTaskStates are the task instance states that may be asserted.
+var validTaskStates = map[string]bool{
+	"success":         true,
+	"failed":          true,
+	"skipped":         true,
+	"upstream_failed": true,
+}
+
+// ValidateEnvironment validates an Airflow environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.RequireURL(env)...)
+}
+
+// ValidateJob validates an Airflow job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.JobID == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "job_id",
+			Message: "is required for airflow jobs (DAG ID)",
+		})
+	}
+
+	if !validStates[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid dag run state '%s', must be one of: success, failed", job.Assertions.Status),
+		})
+	}
+
+	taskIDs := make([]string, 0, len(assertions.Tasks))
+	for taskID := range assertions.Tasks {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	for _, taskID := range taskIDs {
+		state := assertions.Tasks[taskID]
+		if !validTaskStates[strings.ToLower(state)] {
+			errs = append(errs, config.ValidationError{
+				Field:   "assertions.tasks." + taskID,
+				Message: fmt.Sprintf("invalid task state '%s', must be one of: success, failed, skipped, upstream_failed", state),
+			})
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,60 @@
+package airflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid",
+			job: config.Job{
+				Settings:   map[string]any{"job_id": "etl_daily", "conf": map[string]any{"target": "staging"}},
+				Assertions: config.Assertions{Status: "success", Settings: map[string]any{"tasks": map[string]any{"load": "skipped"}}},
+			},
+		},
+		{
+			name: "missing job_id",
+			job:  config.Job{},
+			want: []string{"job_id: is required for airflow jobs"},
+		},
+		{
+			name: "invalid task state",
+			job: config.Job{
+				Settings:   map[string]any{"job_id": "etl_daily"},
+				Assertions: config.Assertions{Settings: map[string]any{"tasks": map[string]any{"load_warehouse": "done"}}},
+			},
+			want: []string{"assertions.tasks.load_warehouse: invalid task state 'done'"},
+		},
+		{
+			name: "unknown assertion",
+			job: config.Job{
+				Settings:   map[string]any{"job_id": "etl_daily"},
+				Assertions: config.Assertions{Settings: map[string]any{"exit_code": 0}},
+			},
+			want: []string{"assertions.exit_code: unknown field"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{URL: "http://airflow"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Package airflow provides an Apache Airflow DAG run provider using the
+// Airflow 2 stable REST API.
+package airflow
+
+// DagRunState represents the state of an Airflow DAG run.
+type DagRunState string
+
+const (
+	DagRunStateQueued  DagRunState = "queued"
+	DagRunStateRunning DagRunState = "running"
+	DagRunStateSuccess DagRunState = "success"
+	DagRunStateFailed  DagRunState = "failed"
+)
+
+// IsTerminal returns true if the state is a terminal state.
+func (s DagRunState) IsTerminal() bool {
+	return s == DagRunStateSuccess || s == DagRunStateFailed
+}
+
+// Dag represents an Airflow DAG.
+type Dag struct {
+	DagID    string `json:"dag_id"`
+	IsPaused bool   `json:"is_paused"`
+	IsActive bool   `json:"is_active"`
+}
+
+// TriggerDagRunRequest represents a request to trigger a DAG run.
+type TriggerDagRunRequest struct {
+	Conf map[string]any `json:"conf"`
+}
+
+// DagRun represents an Airflow DAG run.
+type DagRun struct {
+	DagID     string      `json:"dag_id"`
+	DagRunID  string      `json:"dag_run_id"`
+	State     DagRunState `json:"state"`
+	StartDate string      `json:"start_date"`
+	EndDate   string      `json:"end_date"`
+}
+
+// TaskInstance represents a task instance within a DAG run.
+type TaskInstance struct {
+	TaskID    string `json:"task_id"`
+	State     string `json:"state"`
+	TryNumber int    `json:"try_number"`
+}
+
+// TaskInstanceList represents a page of task instances.
+type TaskInstanceList struct {
+	TaskInstances []TaskInstance `json:"task_instances"`
+	TotalEntries  int            `json:"total_entries"`
+}
+
+// ErrorResponse represents an Airflow API problem response.
+type ErrorResponse struct {
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+	Status int    `json:"status"`
+}
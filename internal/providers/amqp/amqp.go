@@ -0,0 +1,220 @@
+// Package amqp provides a provider that checks the depth and consumer count
+// of AMQP 0-9-1 queues, such as those on RabbitMQ.
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// defaultTimeout is used when neither the job nor the defaults set one.
+const defaultTimeout = 10 * time.Second
+
+// Provider implements the AMQP provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new AMQP provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "amqp"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Execute passively declares the job's queue, which fails if it does not
+// exist, and asserts on its message and consumer counts.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "amqp",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     map[string]interface{}{},
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+	result.Details["queue"] = spec.Queue
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cfg, err := dialConfig(env, timeout)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Inspecting queue %s...", spec.Queue))
+
+	queue, err := inspectQueue(ctx, env.URL, cfg, spec.Queue)
+	if err != nil {
+		return p.fail(result, timeoutError(err, timeout).Error()), nil
+	}
+	result.Details["messages"] = queue.Messages
+	result.Details["consumers"] = queue.Consumers
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if assertions.Messages != nil {
+		if err := assertion.Evaluate(queue.Messages, nil, *assertions.Messages); err != nil {
+			failures = append(failures, fmt.Sprintf("messages: %v", err))
+		}
+	}
+
+	if assertions.Consumers != nil {
+		if err := assertion.Evaluate(queue.Consumers, nil, *assertions.Consumers); err != nil {
+			failures = append(failures, fmt.Sprintf("consumers: %v", err))
+		}
+	}
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("%d message(s), %d consumer(s)", queue.Messages, queue.Consumers))
+
+	return result, nil
+}
+
+// inspectQueue connects and passively declares the queue. The client library
+// does not take a context, so the connection is closed when ctx is done to
+// unblock any pending call.
+func inspectQueue(ctx context.Context, url string, cfg amqp091.Config, name string) (amqp091.Queue, error) {
+	type outcome struct {
+		queue amqp091.Queue
+		err   error
+	}
+	done := make(chan outcome, 1)
+	connCh := make(chan *amqp091.Connection, 1)
+
+	go func() {
+		conn, err := amqp091.DialConfig(url, cfg)
+		if err != nil {
+			done <- outcome{err: fmt.Errorf("failed to connect: %w", err)}
+			return
+		}
+		connCh <- conn
+		defer conn.Close()
+
+		ch, err := conn.Channel()
+		if err != nil {
+			done <- outcome{err: fmt.Errorf("failed to open channel: %w", err)}
+			return
+		}
+
+		queue, err := ch.QueueDeclarePassive(name, false, false, false, false, nil)
+		var amqpErr *amqp091.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp091.NotFound {
+			err = fmt.Errorf("queue %s does not exist", name)
+		} else if err != nil {
+			err = fmt.Errorf("failed to inspect queue: %w", err)
+		}
+		done <- outcome{queue: queue, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.queue, out.err
+	case <-ctx.Done():
+		select {
+		case conn := <-connCh:
+			conn.Close()
+		default:
+		}
+		return amqp091.Queue{}, ctx.Err()
+	}
+}
+
+// dialConfig builds the connection settings. Basic auth credentials override
+// those in the URL, and amqps:// uses the environment's TLS settings.
+func dialConfig(env config.Environment, timeout time.Duration) (amqp091.Config, error) {
+	uri, err := amqp091.ParseURI(env.URL)
+	if err != nil {
+		return amqp091.Config{}, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	cfg := amqp091.Config{
+		Locale: "en_US",
+		Dial:   amqp091.DefaultDial(timeout),
+	}
+
+	if env.Auth.Type == "basic" {
+		cfg.SASL = []amqp091.Authentication{&amqp091.PlainAuth{
+			Username: env.Auth.Username,
+			Password: env.Auth.Password,
+		}}
+	}
+
+	if uri.Scheme == "amqps" {
+		tlsConfig, err := providers.NewTLSConfig(env.TLS)
+		if err != nil {
+			return amqp091.Config{}, err
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = uri.Host
+		}
+		cfg.TLSClientConfig = tlsConfig
+	}
+
+	return cfg, nil
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,282 @@
+package amqp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// fakeQueue is the state reported for a queue by the fake broker.
+type fakeQueue struct {
+	messages  uint32
+	consumers uint32
+}
+
+// fakeBroker speaks just enough AMQP 0-9-1 to open a connection and channel
+// and answer passive queue declarations.
+type fakeBroker struct {
+	listener net.Listener
+	queues   map[string]fakeQueue
+}
+
+func newFakeBroker(t *testing.T, queues map[string]fakeQueue) *fakeBroker {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	b := &fakeBroker{listener: listener, queues: queues}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeBroker) url() string {
+	return "amqp://guest:guest@" + b.listener.Addr().String() + "/"
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return
+	}
+
+	// connection.start
+	start := method(10, 10)
+	start.WriteByte(0)
+	start.WriteByte(9)
+	writeTable(start)
+	writeLongString(start, "PLAIN")
+	writeLongString(start, "en_US")
+	writeFrame(conn, 0, start)
+
+	for {
+		channel, class, id, args, err := readMethod(r)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case class == 10 && id == 11: // connection.start-ok
+			tune := method(10, 30)
+			binary.Write(tune, binary.BigEndian, uint16(0))
+			binary.Write(tune, binary.BigEndian, uint32(131072))
+			binary.Write(tune, binary.BigEndian, uint16(0))
+			writeFrame(conn, 0, tune)
+		case class == 10 && id == 40: // connection.open
+			openOK := method(10, 41)
+			writeShortString(openOK, "")
+			writeFrame(conn, 0, openOK)
+		case class == 10 && id == 50: // connection.close
+			writeFrame(conn, 0, method(10, 51))
+			return
+		case class == 20 && id == 10: // channel.open
+			openOK := method(20, 11)
+			writeLongString(openOK, "")
+			writeFrame(conn, channel, openOK)
+		case class == 20 && id == 40: // channel.close
+			writeFrame(conn, channel, method(20, 41))
+		case class == 50 && id == 10: // queue.declare
+			name := readShortString(args[2:])
+			queue, ok := b.queues[name]
+			if !ok {
+				closeChannel := method(20, 40)
+				binary.Write(closeChannel, binary.BigEndian, uint16(404))
+				writeShortString(closeChannel, "NOT_FOUND - no queue '"+name+"' in vhost '/'")
+				binary.Write(closeChannel, binary.BigEndian, uint16(50))
+				binary.Write(closeChannel, binary.BigEndian, uint16(10))
+				writeFrame(conn, channel, closeChannel)
+				continue
+			}
+			declareOK := method(50, 11)
+			writeShortString(declareOK, name)
+			binary.Write(declareOK, binary.BigEndian, queue.messages)
+			binary.Write(declareOK, binary.BigEndian, queue.consumers)
+			writeFrame(conn, channel, declareOK)
+		}
+	}
+}
+
+// readMethod reads frames until a method frame arrives, skipping heartbeats.
+func readMethod(r *bufio.Reader) (channel, class, id uint16, args []byte, err error) {
+	for {
+		header := make([]byte, 7)
+		if _, err = io.ReadFull(r, header); err != nil {
+			return
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(header[3:])+1)
+		if _, err = io.ReadFull(r, payload); err != nil {
+			return
+		}
+		if header[0] != 1 {
+			continue
+		}
+		channel = binary.BigEndian.Uint16(header[1:])
+		class = binary.BigEndian.Uint16(payload[0:])
+		id = binary.BigEndian.Uint16(payload[2:])
+		return channel, class, id, payload[4 : len(payload)-1], nil
+	}
+}
+
+func method(class, id uint16) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	binary.Write(buf, binary.BigEndian, class)
+	binary.Write(buf, binary.BigEndian, id)
+	return buf
+}
+
+func writeFrame(w io.Writer, channel uint16, payload *bytes.Buffer) {
+	frame := &bytes.Buffer{}
+	frame.WriteByte(1)
+	binary.Write(frame, binary.BigEndian, channel)
+	binary.Write(frame, binary.BigEndian, uint32(payload.Len()))
+	frame.Write(payload.Bytes())
+	frame.WriteByte(0xCE)
+	w.Write(frame.Bytes())
+}
+
+func writeShortString(buf *bytes.Buffer, s string) {
+	buf.WriteByte(byte(len(s)))
+	buf.WriteString(s)
+}
+
+func writeLongString(buf *bytes.Buffer, s string) {
+	binary.Write(buf, binary.BigEndian, uint32(len(s)))
+	buf.WriteString(s)
+}
+
+func writeTable(buf *bytes.Buffer) {
+	binary.Write(buf, binary.BigEndian, uint32(0))
+}
+
+func readShortString(b []byte) string {
+	return string(b[1 : 1+int(b[0])])
+}
+
+func TestExecute(t *testing.T) {
+	broker := newFakeBroker(t, map[string]fakeQueue{
+		"orders":      {messages: 3, consumers: 2},
+		"dead-letter": {messages: 120, consumers: 0},
+	})
+
+	tests := []struct {
+		name       string
+		queue      string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "queue exists",
+			queue:      "orders",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:  "depth and consumers",
+			queue: "orders",
+			assertions: config.Assertions{Settings: map[string]any{
+				"messages":  map[string]any{"less_than": 10},
+				"consumers": map[string]any{"greater_than": 0},
+			}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:  "backlog without consumers",
+			queue: "dead-letter",
+			assertions: config.Assertions{Settings: map[string]any{
+				"messages":  map[string]any{"less_than": 100},
+				"consumers": map[string]any{"greater_than": 0},
+			}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "messages: expected less than 100, got 120; consumers: expected greater than 0, got 0",
+		},
+		{
+			name:       "queue missing",
+			queue:      "refunds",
+			wantStatus: providers.StatusFailed,
+			wantError:  "queue refunds does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "queue",
+				Type:       "amqp",
+				Spec:       map[string]any{"queue": tt.queue},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+			env := config.Environment{Type: "amqp", URL: broker.url()}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	job := config.Job{Name: "queue", Type: "amqp", Spec: map[string]any{"queue": "orders"}, Timeout: 5 * time.Second}
+	env := config.Environment{Type: "amqp", URL: "amqp://" + addr + "/"}
+
+	result, _ := NewProvider().Execute(context.Background(), job, env)
+	if result.Status != providers.StatusFailed || !strings.Contains(result.Error, "failed to connect") {
+		t.Errorf("result = %s %q, want failed to connect", result.Status, result.Error)
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	p := NewProvider()
+	env := config.Environment{Type: "amqp", URL: "amqp://rabbit:5672/"}
+
+	job := config.Job{Settings: map[string]any{"queue": "orders"}, Assertions: config.Assertions{Settings: map[string]any{"consumers": map[string]any{"greater_than": 0}}}}
+	if errs := p.ValidateJob(job, env); len(errs) > 0 {
+		t.Errorf("ValidateJob() = %v, want no errors", errs)
+	}
+
+	job = config.Job{Assertions: config.Assertions{Settings: map[string]any{"depth": 0}}}
+	errs := p.ValidateJob(job, env)
+	for _, want := range []string{"queue: is required for amqp jobs", "assertions.depth: unknown field"} {
+		if !strings.Contains(errs.Error(), want) {
+			t.Errorf("expected %q in %v", want, errs)
+		}
+	}
+}
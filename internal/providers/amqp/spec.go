@@ -0,0 +1,59 @@
+package amqp
+
+import (
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of an amqp job.
+type JobSpec struct {
+	// Queue is the queue to inspect. It must exist.
+	Queue string `yaml:"queue"`
+}
+
+// Assertions holds the AMQP-specific assertions of a job.
+type Assertions struct {
+	// Messages matches the number of ready messages.
+	Messages *config.Matcher `yaml:"messages"`
+
+	// Consumers matches the number of consumers.
+	Consumers *config.Matcher `yaml:"consumers"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// ValidateEnvironment validates an AMQP environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	errs = append(errs, providers.RequireURL(env)...)
+	errs = append(errs, providers.ValidateURLScheme(env, "amqp://", "amqps://")...)
+	return append(errs, providers.ValidateAuthType(env, "amqp", "basic")...)
+}
+
+// ValidateJob validates an AMQP job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.Queue == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "queue",
+			Message: "is required for amqp jobs",
+		})
+	}
+
+	if assertions.Messages != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.Messages, "assertions.messages")...)
+	}
+	if assertions.Consumers != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.Consumers, "assertions.consumers")...)
+	}
+
+	return errs
+}
@@ -0,0 +1,294 @@
+package providers
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// digestTransport is an http.RoundTripper that performs HTTP Digest
+// authentication (RFC 7616). It answers 401 challenges transparently and
+// reuses the last challenge for subsequent requests to avoid a round trip.
+type digestTransport struct {
+	username string
+	password string
+	next     http.RoundTripper
+
+	mu        sync.Mutex
+	challenge *digestChallenge
+	nc        int
+}
+
+// digestChallenge holds the parameters of a WWW-Authenticate: Digest header.
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       string
+}
+
+// NewDigestTransport creates a digest round-tripper wrapping next. Share it
+// between requests to the same server so the cached challenge is reused.
+func NewDigestTransport(username, password string, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &digestTransport{
+		username: username,
+		password: password,
+		next:     next,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.GetBody == nil {
+		return nil, fmt.Errorf("digest auth requires a replayable request body")
+	}
+
+	first := req.Clone(req.Context())
+	if auth, ok := t.authorize(first); ok {
+		first.Header.Set("Authorization", auth)
+	}
+
+	resp, err := t.next.RoundTrip(first)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	chal, err := parseDigestChallenge(resp.Header.Values("WWW-Authenticate"))
+	if err != nil {
+		return resp, nil
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	t.mu.Lock()
+	t.challenge = chal
+	t.nc = 0
+	t.mu.Unlock()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		retry.Body = body
+	}
+
+	auth, _ := t.authorize(retry)
+	retry.Header.Set("Authorization", auth)
+
+	return t.next.RoundTrip(retry)
+}
+
+// authorize builds an Authorization header from the cached challenge.
+func (t *digestTransport) authorize(req *http.Request) (string, bool) {
+	t.mu.Lock()
+	chal := t.challenge
+	if chal == nil {
+		t.mu.Unlock()
+		return "", false
+	}
+	t.nc++
+	nc := t.nc
+	t.mu.Unlock()
+
+	auth, err := chal.authorization(t.username, t.password, req.Method, req.URL.RequestURI(), nc)
+	if err != nil {
+		return "", false
+	}
+	return auth, true
+}
+
+// authorization computes the Authorization header value for a request.
+func (c *digestChallenge) authorization(username, password, method, uri string, nc int) (string, error) {
+	algorithm := c.algorithm
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+
+	var newHash func() hash.Hash
+	sess := false
+	switch strings.ToUpper(algorithm) {
+	case "MD5":
+		newHash = md5.New
+	case "MD5-SESS":
+		newHash, sess = md5.New, true
+	case "SHA-256":
+		newHash = sha256.New
+	case "SHA-256-SESS":
+		newHash, sess = sha256.New, true
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm '%s'", algorithm)
+	}
+
+	h := func(s string) string {
+		d := newHash()
+		io.WriteString(d, s)
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	cnonce, err := newCNonce()
+	if err != nil {
+		return "", err
+	}
+	ncValue := fmt.Sprintf("%08x", nc)
+
+	ha1 := h(username + ":" + c.realm + ":" + password)
+	if sess {
+		ha1 = h(ha1 + ":" + c.nonce + ":" + cnonce)
+	}
+	ha2 := h(method + ":" + uri)
+
+	var response string
+	qop := c.selectQop()
+	if qop == "" {
+		response = h(ha1 + ":" + c.nonce + ":" + ha2)
+	} else {
+		response = h(ha1 + ":" + c.nonce + ":" + ncValue + ":" + cnonce + ":" + qop + ":" + ha2)
+	}
+
+	parts := []string{
+		"username=" + quoteString(username),
+		"realm=" + quoteString(c.realm),
+		"nonce=" + quoteString(c.nonce),
+		"uri=" + quoteString(uri),
+		"algorithm=" + algorithm,
+		"response=" + quoteString(response),
+	}
+	if c.opaque != "" {
+		parts = append(parts, "opaque="+quoteString(c.opaque))
+	}
+	if qop != "" {
+		parts = append(parts,
+			"qop="+qop,
+			"nc="+ncValue,
+			"cnonce="+quoteString(cnonce))
+	}
+
+	return "Digest " + strings.Join(parts, ", "), nil
+}
+
+// selectQop returns "auth" if the server offers it, or "" for legacy RFC 2069.
+func (c *digestChallenge) selectQop() string {
+	for _, q := range strings.Split(c.qop, ",") {
+		if strings.TrimSpace(q) == "auth" {
+			return "auth"
+		}
+	}
+	return ""
+}
+
+// parseDigestChallenge finds and parses a Digest challenge among the
+// WWW-Authenticate header values.
+func parseDigestChallenge(values []string) (*digestChallenge, error) {
+	for _, v := range values {
+		if len(v) < 7 || !strings.EqualFold(v[:7], "Digest ") {
+			continue
+		}
+
+		params := parseAuthParams(v[7:])
+		chal := &digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+			qop:       params["qop"],
+		}
+		if chal.nonce == "" {
+			return nil, fmt.Errorf("digest challenge missing nonce")
+		}
+		if chal.qop != "" && chal.selectQop() == "" {
+			return nil, fmt.Errorf("unsupported digest qop '%s'", chal.qop)
+		}
+		return chal, nil
+	}
+	return nil, fmt.Errorf("no digest challenge found")
+}
+
+// parseAuthParams parses a comma-separated list of key=value pairs where
+// values may be quoted strings containing commas.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if s == "" {
+			return params
+		}
+
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			return params
+		}
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			var b strings.Builder
+			i := 1
+			for ; i < len(s); i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					b.WriteByte(s[i])
+					continue
+				}
+				if s[i] == '"' {
+					break
+				}
+				b.WriteByte(s[i])
+			}
+			value = b.String()
+			if i < len(s) {
+				i++
+			}
+			s = s[i:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			value = strings.TrimSpace(s[:end])
+			s = s[end:]
+		}
+
+		params[key] = value
+	}
+}
+
+// quoteString renders s as an RFC 7230 quoted-string, escaping backslashes
+// and double quotes so the value can be recovered by parseAuthParams.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || s[i] == '"' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// newCNonce generates a random client nonce.
+func newCNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate cnonce: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
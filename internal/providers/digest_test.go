@@ -0,0 +1,196 @@
+package providers
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// newDigestServer returns a test server that requires digest auth using the
+// given algorithm and counts the requests it receives.
+func newDigestServer(t *testing.T, algorithm string, newHash func() hash.Hash, requests *int) *httptest.Server {
+	t.Helper()
+
+	const (
+		realm    = "appliance"
+		nonce    = "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+		username = "admin"
+		password = "secret"
+	)
+
+	h := func(s string) string {
+		d := newHash()
+		io.WriteString(d, s)
+		return hex.EncodeToString(d.Sum(nil))
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		auth := r.Header.Get("Authorization")
+		if len(auth) < 7 || auth[:7] != "Digest " {
+			w.Header().Set("WWW-Authenticate",
+				`Digest realm="`+realm+`", nonce="`+nonce+`", qop="auth,auth-int", algorithm=`+algorithm+`, opaque="xyz"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		p := parseAuthParams(auth[7:])
+		ha1 := h(username + ":" + realm + ":" + password)
+		ha2 := h(r.Method + ":" + p["uri"])
+		want := h(ha1 + ":" + nonce + ":" + p["nc"] + ":" + p["cnonce"] + ":" + p["qop"] + ":" + ha2)
+
+		if p["username"] != username || p["response"] != want || p["opaque"] != "xyz" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"ok","echo":` + string(orNull(body)) + `}`))
+	}))
+}
+
+func orNull(b []byte) []byte {
+	if len(b) == 0 {
+		return []byte("null")
+	}
+	return b
+}
+
+// send makes a request through client and returns the status and body.
+func send(t *testing.T, client *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestDigestAuth(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		newHash   func() hash.Hash
+	}{
+		{"MD5", md5.New},
+		{"SHA-256", sha256.New},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			var requests int
+			server := newDigestServer(t, tt.algorithm, tt.newHash, &requests)
+			defer server.Close()
+
+			env := config.Environment{
+				URL: server.URL,
+				Auth: config.Auth{
+					Type:     "digest",
+					Username: "admin",
+					Password: "secret",
+				},
+			}
+			pool := NewClientPool()
+			defer pool.Close()
+
+			status, body := send(t, pool.Client("appliance", env), "POST", server.URL+"/status?x=1", `{"ping":true}`)
+			if status != http.StatusOK {
+				t.Fatalf("StatusCode = %d, want %d", status, http.StatusOK)
+			}
+			if body != `{"status":"ok","echo":{"ping":true}}` {
+				t.Errorf("Body = %s, body was not replayed on retry", body)
+			}
+			if requests != 2 {
+				t.Errorf("requests = %d, want 2 (challenge + authorized)", requests)
+			}
+
+			// Later jobs in the environment reuse the cached challenge
+			// without another 401.
+			status, _ = send(t, pool.Client("appliance", env), "GET", server.URL+"/status", "")
+			if status != http.StatusOK {
+				t.Fatalf("StatusCode = %d, want %d", status, http.StatusOK)
+			}
+			if requests != 3 {
+				t.Errorf("requests = %d, want 3", requests)
+			}
+		})
+	}
+}
+
+func TestDigestAuthWrongPassword(t *testing.T) {
+	var requests int
+	server := newDigestServer(t, "MD5", md5.New, &requests)
+	defer server.Close()
+
+	client := NewEnvironmentClient(config.Environment{
+		URL: server.URL,
+		Auth: config.Auth{
+			Type:     "digest",
+			Username: "admin",
+			Password: "wrong",
+		},
+	})
+
+	if status, _ := send(t, client, "GET", server.URL+"/status", ""); status != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d, want %d", status, http.StatusUnauthorized)
+	}
+}
+
+func TestParseAuthParams(t *testing.T) {
+	params := parseAuthParams(`realm="a, b", nonce="n\"1", qop=auth, algorithm=SHA-256`)
+
+	want := map[string]string{
+		"realm":     "a, b",
+		"nonce":     `n"1`,
+		"qop":       "auth",
+		"algorithm": "SHA-256",
+	}
+	for k, v := range want {
+		if params[k] != v {
+			t.Errorf("params[%q] = %q, want %q", k, params[k], v)
+		}
+	}
+}
+
+func TestDigestAuthorizationEscaping(t *testing.T) {
+	chal := &digestChallenge{realm: `my "realm"`, nonce: `n\1`, qop: "auth"}
+
+	auth, err := chal.authorization(`dom\"user`, "secret", http.MethodGet, `/a"b`, 1)
+	if err != nil {
+		t.Fatalf("authorization() error = %v", err)
+	}
+
+	params := parseAuthParams(strings.TrimPrefix(auth, "Digest "))
+	want := map[string]string{
+		"username": `dom\"user`,
+		"realm":    `my "realm"`,
+		"nonce":    `n\1`,
+		"uri":      `/a"b`,
+		"qop":      "auth",
+	}
+	for k, v := range want {
+		if params[k] != v {
+			t.Errorf("params[%q] = %q, want %q", k, params[k], v)
+		}
+	}
+}
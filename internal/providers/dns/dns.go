@@ -0,0 +1,285 @@
+// Package dns provides a DNS resolution probe provider.
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultTimeout is used when neither the job nor the defaults set one.
+	defaultTimeout = 5 * time.Second
+
+	// resolvConf is read to find the system resolver.
+	resolvConf = "/etc/resolv.conf"
+)
+
+// recordTypes maps supported record type names to DNS query types.
+var recordTypes = map[string]uint16{
+	"A":     mdns.TypeA,
+	"AAAA":  mdns.TypeAAAA,
+	"CNAME": mdns.TypeCNAME,
+	"TXT":   mdns.TypeTXT,
+	"SRV":   mdns.TypeSRV,
+}
+
+// Answer is a single resource record of the requested type.
+type Answer struct {
+	Value string `json:"value"`
+	TTL   uint32 `json:"ttl"`
+}
+
+// Provider implements the DNS probe provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new DNS provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "dns"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Execute resolves the domain and checks the response code, answers and TTLs.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "dns",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	recordType := strings.ToUpper(spec.RecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+	qtype, ok := recordTypes[recordType]
+	if !ok {
+		return p.fail(result, fmt.Sprintf("unsupported record type %s", recordType)), nil
+	}
+
+	resolver, err := resolverAddress(spec.Resolver, env.URL)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+	result.Details["resolver"] = resolver
+	result.Details["record_type"] = recordType
+
+	p.reportProgress(job.Name, providers.StatusRunning,
+		fmt.Sprintf("Resolving %s %s via %s...", spec.Domain, recordType, resolver))
+
+	resp, err := query(ctx, resolver, mdns.Fqdn(spec.Domain), qtype)
+	if err != nil {
+		if ctx.Err() != nil {
+			return p.fail(result, fmt.Sprintf("query failed: timeout after %s", timeout)), nil
+		}
+		return p.fail(result, fmt.Sprintf("query failed: %v", err)), nil
+	}
+
+	rcode := mdns.RcodeToString[resp.Rcode]
+	answers := extractAnswers(resp, qtype)
+	result.Details["rcode"] = rcode
+	result.Details["answers"] = answers
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	wantRcode := "NOERROR"
+	if job.Assertions.Status != "" {
+		wantRcode = strings.ToUpper(job.Assertions.Status)
+	}
+	if rcode != wantRcode {
+		failures = append(failures, fmt.Sprintf("expected response code %s, got %s", wantRcode, rcode))
+	}
+
+	failures = append(failures, checkAnswers(answers, assertions)...)
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("%s: %d answer(s)", rcode, len(answers)))
+
+	return result, nil
+}
+
+// query sends the question over UDP, retrying over TCP if the answer was
+// truncated.
+func query(ctx context.Context, resolver, name string, qtype uint16) (*mdns.Msg, error) {
+	msg := new(mdns.Msg)
+	msg.SetQuestion(name, qtype)
+
+	client := &mdns.Client{Net: "udp"}
+	resp, _, err := client.ExchangeContext(ctx, msg, resolver)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Truncated {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, resolver)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// extractAnswers returns the answers of the requested type. Records of other
+// types, such as the CNAMEs leading to an A record, are skipped.
+func extractAnswers(resp *mdns.Msg, qtype uint16) []Answer {
+	answers := []Answer{}
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype != qtype {
+			continue
+		}
+
+		var value string
+		switch r := rr.(type) {
+		case *mdns.A:
+			value = r.A.String()
+		case *mdns.AAAA:
+			value = r.AAAA.String()
+		case *mdns.CNAME:
+			value = strings.TrimSuffix(r.Target, ".")
+		case *mdns.TXT:
+			value = strings.Join(r.Txt, "")
+		case *mdns.SRV:
+			value = fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, strings.TrimSuffix(r.Target, "."))
+		default:
+			continue
+		}
+
+		answers = append(answers, Answer{Value: value, TTL: rr.Header().Ttl})
+	}
+	return answers
+}
+
+// checkAnswers checks that every answer matcher matches at least one answer
+// and that every answer's TTL satisfies the TTL matcher.
+func checkAnswers(answers []Answer, a Assertions) []string {
+	var failures []string
+
+	for _, m := range a.Answers {
+		if !anyAnswerMatches(answers, m) {
+			failures = append(failures, fmt.Sprintf("no answer matched %s", describeMatcher(m)))
+		}
+	}
+
+	if a.TTL != nil {
+		for _, answer := range answers {
+			if err := assertion.Evaluate(float64(answer.TTL), nil, *a.TTL); err != nil {
+				failures = append(failures, fmt.Sprintf("TTL of %s: %v", answer.Value, err))
+			}
+		}
+	}
+
+	return failures
+}
+
+// anyAnswerMatches returns true if at least one answer satisfies m.
+func anyAnswerMatches(answers []Answer, m config.Matcher) bool {
+	for _, answer := range answers {
+		if assertion.Evaluate(answer.Value, nil, m) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// describeMatcher renders a matcher for error messages.
+func describeMatcher(m config.Matcher) string {
+	switch {
+	case m.Equals != nil:
+		return fmt.Sprintf("equals %v", m.Equals)
+	case m.Contains != "":
+		return fmt.Sprintf("contains %q", m.Contains)
+	case m.Matches != "":
+		return fmt.Sprintf("matches %q", m.Matches)
+	case m.NotEquals != nil:
+		return fmt.Sprintf("not_equals %v", m.NotEquals)
+	default:
+		return "exists"
+	}
+}
+
+// resolverAddress returns the resolver to query: the job's resolver, then the
+// environment URL, then the first nameserver in /etc/resolv.conf.
+func resolverAddress(jobResolver, envURL string) (string, error) {
+	if jobResolver != "" {
+		return jobResolver, nil
+	}
+
+	if envURL != "" {
+		addr := strings.TrimPrefix(strings.TrimPrefix(envURL, "udp://"), "dns://")
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, "53")
+		}
+		return addr, nil
+	}
+
+	cfg, err := mdns.ClientConfigFromFile(resolvConf)
+	if err != nil || len(cfg.Servers) == 0 {
+		return "", fmt.Errorf("no resolver configured and none found in %s", resolvConf)
+	}
+	return net.JoinHostPort(cfg.Servers[0], cfg.Port), nil
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,262 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	mdns "github.com/miekg/dns"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// startDNSServer starts an in-process DNS server with a small fixed zone.
+func startDNSServer(t *testing.T) string {
+	t.Helper()
+
+	zone := []string{
+		"api.example.com. 300 IN A 10.0.0.1",
+		"api.example.com. 300 IN A 10.0.0.2",
+		"api.example.com. 300 IN AAAA 2001:db8::1",
+		"www.example.com. 60 IN CNAME api.example.com.",
+		"example.com. 3600 IN TXT \"v=spf1 -all\"",
+		"_sip._tcp.example.com. 86400 IN SRV 10 60 5060 sip.example.com.",
+	}
+
+	records := make(map[string][]mdns.RR)
+	for _, line := range zone {
+		rr, err := mdns.NewRR(line)
+		if err != nil {
+			t.Fatalf("invalid record %q: %v", line, err)
+		}
+		key := rr.Header().Name + mdns.TypeToString[rr.Header().Rrtype]
+		records[key] = append(records[key], rr)
+	}
+
+	handler := mdns.HandlerFunc(func(w mdns.ResponseWriter, req *mdns.Msg) {
+		resp := new(mdns.Msg)
+		resp.SetReply(req)
+
+		q := req.Question[0]
+		if cname, ok := records[q.Name+"CNAME"]; ok && q.Qtype != mdns.TypeCNAME {
+			resp.Answer = append(resp.Answer, cname...)
+			q.Name = cname[0].(*mdns.CNAME).Target
+		}
+
+		answers, ok := records[q.Name+mdns.TypeToString[q.Qtype]]
+		if !ok && len(resp.Answer) == 0 {
+			resp.Rcode = mdns.RcodeNameError
+		}
+		resp.Answer = append(resp.Answer, answers...)
+		w.WriteMsg(resp)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	started := make(chan struct{})
+	server := &mdns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { server.Shutdown() })
+
+	return pc.LocalAddr().String()
+}
+
+func TestExecute(t *testing.T) {
+	resolver := startDNSServer(t)
+
+	tests := []struct {
+		name       string
+		domain     string
+		recordType string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+		wantCount  int
+	}{
+		{
+			name:   "A records",
+			domain: "api.example.com",
+			assertions: config.Assertions{Settings: map[string]any{
+				"answers": []any{map[string]any{"equals": "10.0.0.2"}},
+				"ttl":     map[string]any{"greater_than": 60},
+			}},
+			wantStatus: providers.StatusSucceeded,
+			wantCount:  2,
+		},
+		{
+			name:       "AAAA record",
+			domain:     "api.example.com",
+			recordType: "aaaa",
+			assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"equals": "2001:db8::1"}}}},
+			wantStatus: providers.StatusSucceeded,
+			wantCount:  1,
+		},
+		{
+			name:       "A through CNAME",
+			domain:     "www.example.com",
+			assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"matches": `^10\.0\.0\.`}}}},
+			wantStatus: providers.StatusSucceeded,
+			wantCount:  2,
+		},
+		{
+			name:       "CNAME record",
+			domain:     "www.example.com",
+			recordType: "CNAME",
+			assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"equals": "api.example.com"}}}},
+			wantStatus: providers.StatusSucceeded,
+			wantCount:  1,
+		},
+		{
+			name:       "TXT record",
+			domain:     "example.com",
+			recordType: "TXT",
+			assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"contains": "spf1"}}}},
+			wantStatus: providers.StatusSucceeded,
+			wantCount:  1,
+		},
+		{
+			name:       "SRV record",
+			domain:     "_sip._tcp.example.com",
+			recordType: "SRV",
+			assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"equals": "10 60 5060 sip.example.com"}}}},
+			wantStatus: providers.StatusSucceeded,
+			wantCount:  1,
+		},
+		{
+			name:       "missing answer",
+			domain:     "api.example.com",
+			assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"equals": "10.0.0.9"}}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "no answer matched equals 10.0.0.9",
+			wantCount:  2,
+		},
+		{
+			name:       "TTL too low",
+			domain:     "www.example.com",
+			recordType: "CNAME",
+			assertions: config.Assertions{Settings: map[string]any{"ttl": map[string]any{"greater_than": 300}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "TTL of api.example.com: expected greater than 300, got 60",
+			wantCount:  1,
+		},
+		{
+			name:       "NXDOMAIN",
+			domain:     "missing.example.com",
+			wantStatus: providers.StatusFailed,
+			wantError:  "expected response code NOERROR, got NXDOMAIN",
+		},
+		{
+			name:       "NXDOMAIN expected",
+			domain:     "missing.example.com",
+			assertions: config.Assertions{Status: "nxdomain"},
+			wantStatus: providers.StatusSucceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name: "dns",
+				Type: "dns",
+				Spec: map[string]any{
+					"domain":      tt.domain,
+					"record_type": tt.recordType,
+					"resolver":    resolver,
+				},
+				Timeout:    2 * time.Second,
+				Assertions: tt.assertions,
+			}
+
+			result, err := NewProvider().Execute(context.Background(), job, config.Environment{Type: "dns"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if answers, _ := result.Details["answers"].([]Answer); len(answers) != tt.wantCount {
+				t.Errorf("answers = %v, want %d", answers, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestResolverAddress(t *testing.T) {
+	tests := []struct {
+		job, env, want string
+	}{
+		{job: "10.0.0.53:5353", env: "1.1.1.1", want: "10.0.0.53:5353"},
+		{env: "1.1.1.1", want: "1.1.1.1:53"},
+		{env: "udp://9.9.9.9:53", want: "9.9.9.9:53"},
+	}
+
+	for _, tt := range tests {
+		got, err := resolverAddress(tt.job, tt.env)
+		if err != nil || got != tt.want {
+			t.Errorf("resolverAddress(%q, %q) = %q, %v, want %q", tt.job, tt.env, got, err, tt.want)
+		}
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid",
+			job: config.Job{
+				Settings:   map[string]any{"domain": "example.com", "record_type": "txt", "resolver": "10.0.0.53:53"},
+				Assertions: config.Assertions{Status: "NOERROR", Settings: map[string]any{"ttl": map[string]any{"greater_than": 60}}},
+			},
+		},
+		{
+			name: "invalid settings",
+			job: config.Job{
+				Settings:   map[string]any{"record_type": "MX", "resolver": "10.0.0.53"},
+				Assertions: config.Assertions{Status: "timeout"},
+			},
+			want: []string{
+				"domain: is required",
+				"record_type: invalid record type 'MX'",
+				"resolver: must be host:port",
+				"assertions.status: invalid response code 'timeout'",
+			},
+		},
+		{
+			name: "invalid answer pattern",
+			job: config.Job{
+				Settings:   map[string]any{"domain": "example.com"},
+				Assertions: config.Assertions{Settings: map[string]any{"answers": []any{map[string]any{"matches": "("}}}},
+			},
+			want: []string{"assertions.answers[0].matches: invalid pattern"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "dns"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
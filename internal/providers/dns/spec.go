@@ -0,0 +1,100 @@
+package dns
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a dns job.
+type JobSpec struct {
+	// Domain is the name to resolve.
+	Domain string `yaml:"domain"`
+
+	// RecordType is the record type to query. Empty queries A records.
+	RecordType string `yaml:"record_type"`
+
+	// Resolver is the host:port of the DNS server, overriding the
+	// environment URL and the system resolver.
+	Resolver string `yaml:"resolver"`
+}
+
+// Assertions holds the DNS-specific assertions of a job.
+type Assertions struct {
+	// Answers must each match at least one answer.
+	Answers []config.Matcher `yaml:"answers"`
+
+	// TTL must match the TTL of every answer.
+	TTL *config.Matcher `yaml:"ttl"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// validRcodes are the response codes that may be asserted.
+var validRcodes = map[string]bool{
+	"":         true,
+	"noerror":  true,
+	"nxdomain": true,
+	"servfail": true,
+	"refused":  true,
+}
+
+// ValidateEnvironment validates a DNS environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.ValidateAuthType(env, "dns")...)
+}
+
+// ValidateJob validates a DNS job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.Domain == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "domain",
+			Message: "is required for dns jobs",
+		})
+	}
+
+	if _, ok := recordTypes[strings.ToUpper(spec.RecordType)]; !ok && spec.RecordType != "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "record_type",
+			Message: fmt.Sprintf("invalid record type '%s', must be one of: A, AAAA, CNAME, TXT, SRV", spec.RecordType),
+		})
+	}
+
+	if spec.Resolver != "" {
+		if _, _, err := net.SplitHostPort(spec.Resolver); err != nil {
+			errs = append(errs, config.ValidationError{
+				Field:   "resolver",
+				Message: "must be host:port",
+			})
+		}
+	}
+
+	if !validRcodes[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid response code '%s', must be one of: NOERROR, NXDOMAIN, SERVFAIL, REFUSED", job.Assertions.Status),
+		})
+	}
+
+	for i, m := range assertions.Answers {
+		errs = append(errs, config.ValidateMatcher(m, fmt.Sprintf("assertions.answers[%d]", i))...)
+	}
+	if assertions.TTL != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.TTL, "assertions.ttl")...)
+	}
+
+	return errs
+}
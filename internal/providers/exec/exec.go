@@ -0,0 +1,207 @@
+// Package exec provides a provider that runs local commands and scripts.
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// detailOutputLimit is the number of output bytes kept in result details.
+	detailOutputLimit = 4096
+
+	// waitDelay bounds how long to wait for output pipes to close after the
+	// process exits or is killed.
+	waitDelay = 5 * time.Second
+)
+
+// Provider implements the local command provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new exec provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "exec"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Capabilities describes what the exec provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Logs: true}
+}
+
+// Execute runs the command and checks its exit code and output.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "exec",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.Timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: int64(job.MaxBodySize)}
+	stderr := &limitedBuffer{limit: int64(job.MaxBodySize)}
+
+	cmd := osexec.CommandContext(ctx, spec.Command, spec.Args...)
+	cmd.Dir = spec.WorkingDir
+	cmd.Env = commandEnv(spec.Env)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return killProcessGroup(cmd)
+	}
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Running %s...", spec.Command))
+	result.Status = providers.StatusRunning
+
+	runErr := cmd.Run()
+
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	p.attachOutput(result, "stdout", stdout)
+	p.attachOutput(result, "stderr", stderr)
+
+	var exitErr *osexec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return p.fail(result, fmt.Sprintf("timeout after %s", timeout)), nil
+		}
+		result.Status = providers.StatusAborted
+		result.Error = "canceled"
+		return result, nil
+
+	case errors.As(runErr, &exitErr):
+		// The command ran; its exit code is checked below.
+
+	case runErr != nil:
+		return p.fail(result, fmt.Sprintf("failed to run command: %v", runErr)), nil
+	}
+
+	exitCode := cmd.ProcessState.ExitCode()
+	result.Details["exit_code"] = exitCode
+	result.Status = providers.StatusSucceeded
+
+	var failures []string
+
+	wantExitCode := 0
+	if assertions.ExitCode != nil {
+		wantExitCode = *assertions.ExitCode
+	}
+	if exitCode != wantExitCode {
+		failures = append(failures, fmt.Sprintf("expected exit code %d, got %d", wantExitCode, exitCode))
+	}
+
+	for _, m := range assertions.Stdout {
+		if err := assertion.Evaluate(string(stdout.Bytes()), nil, m); err != nil {
+			failures = append(failures, fmt.Sprintf("stdout: %v", err))
+		}
+	}
+
+	for _, m := range assertions.Stderr {
+		if err := assertion.Evaluate(string(stderr.Bytes()), nil, m); err != nil {
+			failures = append(failures, fmt.Sprintf("stderr: %v", err))
+		}
+	}
+
+	if len(job.Assertions.JSON) > 0 {
+		failures = append(failures, assertion.CheckBody(stdout.Bytes(), assertion.FormatJSON,
+			config.Assertions{JSON: job.Assertions.JSON})...)
+	}
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("Exited with code %d", exitCode))
+
+	return result, nil
+}
+
+// attachOutput adds the start of a captured stream to the result details.
+func (p *Provider) attachOutput(result *providers.Result, name string, b *limitedBuffer) {
+	if b.total == 0 {
+		return
+	}
+	result.Details[name] = truncate(string(b.Bytes()), detailOutputLimit)
+	result.Details[name+"_bytes"] = b.total
+	if b.Truncated() {
+		result.Details[name+"_truncated"] = true
+	}
+}
+
+// commandEnv returns the process environment with the job's variables
+// applied on top, in a stable order.
+func commandEnv(vars map[string]string) []string {
+	env := os.Environ()
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		env = append(env, k+"="+vars[k])
+	}
+	return env
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
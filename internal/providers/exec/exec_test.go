@@ -0,0 +1,189 @@
+//go:build unix
+
+package exec
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:   "success",
+			script: `echo "hello $GREETING"`,
+			assertions: config.Assertions{Settings: map[string]any{
+				"stdout": []any{map[string]any{"matches": `^hello w\w+`}},
+			}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "non-zero exit code",
+			script:     `echo boom >&2; exit 3`,
+			wantStatus: providers.StatusFailed,
+			wantError:  "expected exit code 0, got 3",
+		},
+		{
+			name:   "expected exit code and stderr",
+			script: `echo "disk usage high" >&2; exit 1`,
+			assertions: config.Assertions{Settings: map[string]any{
+				"exit_code": 1,
+				"stderr":    []any{map[string]any{"contains": "disk usage"}},
+			}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:   "json on stdout",
+			script: `echo '{"healthy":true,"lag":12}'`,
+			assertions: config.Assertions{JSON: []config.JSONAssertion{
+				{Path: "$.healthy", Matcher: config.Matcher{Equals: true}},
+				{Path: "$.lag", Matcher: config.Matcher{LessThan: floatPtr(10)}},
+			}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "JSON path $.lag",
+		},
+		{
+			name:   "stdout mismatch",
+			script: `echo ok`,
+			assertions: config.Assertions{Settings: map[string]any{
+				"stdout": []any{map[string]any{"contains": "ready"}},
+			}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "stdout:",
+		},
+		{
+			name:       "duration exceeded",
+			script:     `sleep 0.2`,
+			assertions: config.Assertions{MaxDuration: 50 * time.Millisecond},
+			wantStatus: providers.StatusFailed,
+			wantError:  "exceeded max 50ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name: "script",
+				Type: "exec",
+				Spec: map[string]any{
+					"command": "sh",
+					"args":    []any{"-c", tt.script},
+					"env":     map[string]any{"GREETING": "world"},
+				},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+
+			result, err := NewProvider().Execute(context.Background(), job.WithDefaults(config.DefaultConfig().Defaults), config.Environment{Type: "exec"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteTimeoutKillsProcessGroup(t *testing.T) {
+	// The background sleep keeps stdout open; without killing the whole
+	// process group, Run would block until waitDelay expires.
+	job := config.Job{
+		Name:    "hang",
+		Type:    "exec",
+		Spec:    map[string]any{"command": "sh", "args": []any{"-c", "sleep 30 & sleep 30"}},
+		Timeout: 100 * time.Millisecond,
+	}
+
+	start := time.Now()
+	result, err := NewProvider().Execute(context.Background(), job.WithDefaults(config.DefaultConfig().Defaults), config.Environment{Type: "exec"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Status != providers.StatusFailed || result.Error != "timeout after 100ms" {
+		t.Errorf("Status = %s, Error = %q, want failed with timeout", result.Status, result.Error)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Execute() took %s, want process group killed promptly", elapsed)
+	}
+}
+
+func TestExecuteCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	job := config.Job{
+		Name:    "cancel",
+		Type:    "exec",
+		Spec:    map[string]any{"command": "sleep", "args": []any{"30"}},
+		Timeout: 5 * time.Second,
+	}
+
+	result, err := NewProvider().Execute(ctx, job.WithDefaults(config.DefaultConfig().Defaults), config.Environment{Type: "exec"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != providers.StatusAborted {
+		t.Errorf("Status = %s, want aborted", result.Status)
+	}
+}
+
+func TestExecuteTruncatesOutput(t *testing.T) {
+	job := config.Job{
+		Name:        "chatty",
+		Type:        "exec",
+		Spec:        map[string]any{"command": "sh", "args": []any{"-c", "head -c 10000 /dev/zero | tr '\\0' x"}},
+		Timeout:     5 * time.Second,
+		MaxBodySize: 2 * config.Kilobyte,
+	}
+
+	result, err := NewProvider().Execute(context.Background(), job.WithDefaults(config.DefaultConfig().Defaults), config.Environment{Type: "exec"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if result.Details["stdout_bytes"] != int64(10000) {
+		t.Errorf("stdout_bytes = %v, want 10000", result.Details["stdout_bytes"])
+	}
+	if result.Details["stdout_truncated"] != true {
+		t.Error("stdout_truncated not set")
+	}
+	if stdout, _ := result.Details["stdout"].(string); len(stdout) != 2048 {
+		t.Errorf("len(stdout) = %d, want 2048", len(stdout))
+	}
+}
+
+func TestExecuteCommandNotFound(t *testing.T) {
+	job := config.Job{
+		Name:    "missing",
+		Type:    "exec",
+		Spec:    map[string]any{"command": "jprobe-no-such-command"},
+		Timeout: time.Second,
+	}
+
+	result, err := NewProvider().Execute(context.Background(), job.WithDefaults(config.DefaultConfig().Defaults), config.Environment{Type: "exec"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(result.Error, "failed to run command:") {
+		t.Errorf("Error = %q, want run failure", result.Error)
+	}
+}
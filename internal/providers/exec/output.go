@@ -0,0 +1,41 @@
+package exec
+
+// limitedBuffer keeps the first limit bytes written to it and counts the
+// rest, so that a chatty command cannot exhaust memory.
+type limitedBuffer struct {
+	buf   []byte
+	limit int64
+	total int64
+}
+
+// Write implements io.Writer. It never returns an error so that the command
+// is not blocked or killed once the limit is reached.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.total += int64(len(p))
+	if remaining := b.limit - int64(len(b.buf)); remaining > 0 {
+		if int64(len(p)) > remaining {
+			b.buf = append(b.buf, p[:remaining]...)
+		} else {
+			b.buf = append(b.buf, p...)
+		}
+	}
+	return len(p), nil
+}
+
+// Bytes returns the captured output.
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf
+}
+
+// Truncated returns true if output beyond the limit was discarded.
+func (b *limitedBuffer) Truncated() bool {
+	return b.total > int64(len(b.buf))
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "... (truncated)"
+}
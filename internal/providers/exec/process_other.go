@@ -0,0 +1,16 @@
+//go:build !unix
+
+package exec
+
+import (
+	osexec "os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups.
+func setProcessGroup(cmd *osexec.Cmd) {}
+
+// killProcessGroup kills the command's process. Children are not tracked on
+// platforms without process groups.
+func killProcessGroup(cmd *osexec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,77 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of an exec job.
+type JobSpec struct {
+	// Command is the program to run, looked up in PATH.
+	Command string `yaml:"command"`
+
+	// Args are passed to the command as-is, without a shell.
+	Args []string `yaml:"args"`
+
+	// Env is added to the environment of the process.
+	Env map[string]string `yaml:"env"`
+
+	// WorkingDir is the directory the command runs in.
+	WorkingDir string `yaml:"working_dir"`
+}
+
+// Assertions holds the exec-specific assertions of a job.
+type Assertions struct {
+	// ExitCode is the expected exit code. Unset expects 0.
+	ExitCode *int `yaml:"exit_code"`
+
+	// Stdout and Stderr are matched against the captured output.
+	Stdout []config.Matcher `yaml:"stdout"`
+	Stderr []config.Matcher `yaml:"stderr"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// ValidateEnvironment validates an exec environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.ValidateAuthType(env, "exec")...)
+}
+
+// ValidateJob validates an exec job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.Command == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "command",
+			Message: "is required for exec jobs",
+		})
+	}
+
+	if assertions.ExitCode != nil && (*assertions.ExitCode < 0 || *assertions.ExitCode > 255) {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.exit_code",
+			Message: "must be between 0 and 255",
+		})
+	}
+
+	for i, m := range assertions.Stdout {
+		errs = append(errs, config.ValidateMatcher(m, fmt.Sprintf("assertions.stdout[%d]", i))...)
+	}
+	for i, m := range assertions.Stderr {
+		errs = append(errs, config.ValidateMatcher(m, fmt.Sprintf("assertions.stderr[%d]", i))...)
+	}
+	errs = append(errs, config.ValidateBodyAssertions(config.Assertions{JSON: job.Assertions.JSON}, "assertions")...)
+
+	return errs
+}
@@ -0,0 +1,68 @@
+package exec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid",
+			job: config.Job{
+				Settings: map[string]any{"command": "./check.sh", "args": []any{"--fast"}, "working_dir": "scripts"},
+				Assertions: config.Assertions{Settings: map[string]any{
+					"exit_code": 2,
+					"stdout":    []any{map[string]any{"contains": "ok"}},
+				}},
+			},
+		},
+		{
+			name: "missing command",
+			job:  config.Job{Assertions: config.Assertions{Settings: map[string]any{"exit_code": 300}}},
+			want: []string{"command: is required", "assertions.exit_code: must be between 0 and 255"},
+		},
+		{
+			name: "invalid stderr pattern",
+			job: config.Job{
+				Settings:   map[string]any{"command": "true"},
+				Assertions: config.Assertions{Settings: map[string]any{"stderr": []any{map[string]any{"matches": "("}}}},
+			},
+			want: []string{"assertions.stderr[0].matches: invalid pattern"},
+		},
+		{
+			name: "unknown setting",
+			job:  config.Job{Settings: map[string]any{"command": "true", "cmd": "true"}},
+			want: []string{"cmd: unknown field"},
+		},
+		{
+			name: "log assertions",
+			job: config.Job{
+				Settings:   map[string]any{"command": "true"},
+				Assertions: config.Assertions{Settings: map[string]any{"log": []any{map[string]any{"matches": "done"}}}},
+			},
+			want: []string{"assertions.log: unknown field"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "exec"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package fixture records the HTTP traffic of the clients providers share
+// and replays it, so a run can be reproduced offline.
+//
+// A recording is a directory with one subdirectory per environment, holding
+// one JSON file per request in the order requests were sent. Credentials are
+// redacted before anything is written.
+package fixture
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Redacted replaces credentials in recordings.
+const Redacted = "REDACTED"
+
+// Interaction is a recorded request and the response or error it got.
+type Interaction struct {
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeaders http.Header `json:"request_headers,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+
+	Status  int         `json:"status,omitempty"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+
+	// Error is the transport error the request failed with, such as a
+	// refused connection, instead of a response.
+	Error string `json:"error,omitempty"`
+}
+
+// key identifies the requests an interaction answers during replay.
+func (i Interaction) key() string {
+	return i.Method + " " + i.URL + "\n" + i.RequestBody
+}
+
+// sensitiveHeaders are always redacted.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":        true,
+	"Proxy-Authorization":  true,
+	"Cookie":               true,
+	"Set-Cookie":           true,
+	"X-Rundeck-Auth-Token": true,
+	"X-Api-Key":            true,
+}
+
+// sensitiveWords mark other header names as carrying credentials.
+var sensitiveWords = []string{"token", "secret", "password", "api-key", "apikey"}
+
+// redactor removes an environment's credentials from requests and
+// responses. Besides the well-known credential headers, it redacts every
+// header the environment sets, since their values are often credentials
+// too.
+type redactor struct {
+	// configured holds the canonical names of the headers the environment
+	// sets, including its api_key header.
+	configured map[string]bool
+	secrets    []string
+}
+
+func newRedactor(env config.Environment) redactor {
+	r := redactor{configured: make(map[string]bool, len(env.Headers)+1)}
+	if env.Auth.Header != "" {
+		r.configured[http.CanonicalHeaderKey(env.Auth.Header)] = true
+	}
+	for name := range env.Headers {
+		r.configured[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, secret := range []string{env.Auth.Token, env.Auth.Password, env.Auth.APIKey} {
+		if secret != "" {
+			r.secrets = append(r.secrets, secret, url.QueryEscape(secret))
+		}
+	}
+	return r
+}
+
+// text replaces the environment's secrets in s.
+func (r redactor) text(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
+
+// headers returns a copy of h with credentials redacted.
+func (r redactor) headers(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+
+	out := make(http.Header, len(h))
+	for name, values := range h {
+		if r.sensitive(name) {
+			out[name] = []string{Redacted}
+			continue
+		}
+		for _, value := range values {
+			out[name] = append(out[name], r.text(value))
+		}
+	}
+	return out
+}
+
+func (r redactor) sensitive(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	if sensitiveHeaders[name] || r.configured[name] {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, word := range sensitiveWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// request describes req with credentials redacted, and restores its body
+// so it can still be sent.
+func (r redactor) request(req *http.Request) (Interaction, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return Interaction{}, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return Interaction{
+		Method:         req.Method,
+		URL:            r.text(req.URL.String()),
+		RequestHeaders: r.headers(req.Header),
+		RequestBody:    r.text(string(body)),
+	}, nil
+}
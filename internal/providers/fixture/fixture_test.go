@@ -0,0 +1,217 @@
+package fixture
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// get sends a request through the pool's client for env and returns the
+// response status and body.
+func get(t *testing.T, pool *providers.ClientPool, env config.Environment, method, path, body string) (string, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, env.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+env.Auth.Token)
+
+	resp, err := pool.Client("prod", env).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return fmt.Sprintf("%d %s", resp.StatusCode, data), nil
+}
+
+func TestRecordReplay(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/run":
+			body, _ := io.ReadAll(r.Body)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+			fmt.Fprintf(w, `{"id":1,"echo":%q}`, body)
+		case "/status":
+			polls++
+			if polls < 2 {
+				fmt.Fprint(w, `{"status":"running"}`)
+				return
+			}
+			fmt.Fprint(w, `{"status":"succeeded"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	env := config.Environment{URL: server.URL, Auth: config.Auth{Type: "bearer", Token: "s3cret-token"}}
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	recorder, err := NewRecorder(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := providers.NewClientPool()
+	pool.SetTransportWrapper(recorder.Wrap)
+
+	calls := []struct{ method, path, body string }{
+		{http.MethodPost, "/run", `{"token":"s3cret-token"}`},
+		{http.MethodGet, "/status", ""},
+		{http.MethodGet, "/status", ""},
+		{http.MethodGet, "/missing", ""},
+	}
+	var recorded []string
+	for _, c := range calls {
+		got, err := get(t, pool, env, c.method, c.path, c.body)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", c.method, c.path, err)
+		}
+		recorded = append(recorded, got)
+	}
+	server.Close()
+	if err := recorder.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "prod", "*.json"))
+	if len(files) != len(calls) {
+		t.Fatalf("recorded %d files, want %d", len(files), len(calls))
+	}
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		if strings.Contains(string(data), "s3cret-token") || strings.Contains(string(data), "abc123") {
+			t.Errorf("%s contains a credential:\n%s", file, data)
+		}
+	}
+
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool = providers.NewClientPool()
+	pool.SetTransportWrapper(replayer.Wrap)
+
+	for i, c := range calls {
+		got, err := get(t, pool, env, c.method, c.path, c.body)
+		if err != nil {
+			t.Fatalf("replay %s %s error = %v", c.method, c.path, err)
+		}
+		want := strings.ReplaceAll(recorded[i], "s3cret-token", Redacted)
+		if got != want {
+			t.Errorf("replay %s %s = %q, want %q", c.method, c.path, got, want)
+		}
+	}
+
+	// Polling past the recording repeats the last response.
+	if got, _ := get(t, pool, env, http.MethodGet, "/status", ""); got != `200 {"status":"succeeded"}` {
+		t.Errorf("extra poll = %q", got)
+	}
+	if err := replayer.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestRecordConfiguredHeadersAndBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Repeat("x", 100))
+	}))
+	defer server.Close()
+	env := config.Environment{URL: server.URL, Headers: map[string]string{"x-tenant": "tenant-key-123"}}
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	recorder, err := NewRecorder(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := providers.NewClientPool()
+	pool.SetTransportWrapper(recorder.Wrap)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/big", nil)
+	req.Header.Set("X-Tenant", "tenant-key-123")
+	resp, err := pool.Client("prod", env).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if len(body) != 100 {
+		t.Errorf("client read %d bytes, want the whole body", len(body))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "prod", "0001.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tenant-key-123") {
+		t.Errorf("recording contains a configured header value:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"body": "`+strings.Repeat("x", 11)+`"`) {
+		t.Errorf("recording does not hold the body up to the limit plus one byte:\n%s", data)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "prod"), 0o755)
+	os.WriteFile(filepath.Join(dir, "prod", "0001.json"), []byte(`{"method":"POST","url":"http://rundeck/run","request_body":"{}","status":200,"body":"ok"}`), 0o644)
+	os.WriteFile(filepath.Join(dir, "prod", "0002.json"), []byte(`{"method":"GET","url":"http://rundeck/down","error":"connection refused"}`), 0o644)
+
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := providers.NewClientPool()
+	pool.SetTransportWrapper(replayer.Wrap)
+	env := config.Environment{URL: "http://rundeck"}
+
+	if got, err := get(t, pool, env, http.MethodPost, "/run", "{}"); err != nil || got != "200 ok" {
+		t.Fatalf("recorded request = %q, %v", got, err)
+	}
+	if _, err := get(t, pool, env, http.MethodGet, "/down", ""); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("recorded error = %v", err)
+	}
+	if replayer.Err() != nil {
+		t.Fatalf("Err() = %v before any mismatch", replayer.Err())
+	}
+
+	mismatched := []struct{ method, path, body string }{
+		{http.MethodPost, "/run", "{}"},
+		{http.MethodPost, "/run", `{"other":true}`},
+		{http.MethodGet, "/other", ""},
+	}
+	for _, m := range mismatched {
+		if _, err := get(t, pool, env, m.method, m.path, m.body); err == nil || !strings.Contains(err.Error(), "replay: ") {
+			t.Errorf("%s %s %s error = %v, want a replay error", m.method, m.path, m.body, err)
+		}
+	}
+
+	err = replayer.Err()
+	want := "replay: 3 request(s) did not match the recording: " +
+		"POST http://rundeck/run in environment prod was sent more often than recorded; " +
+		"no recorded response for POST http://rundeck/run in environment prod; " +
+		"no recorded response for GET http://rundeck/other in environment prod"
+	if err == nil || err.Error() != want {
+		t.Errorf("Err() = %v, want %s", err, want)
+	}
+}
+
+func TestNewRecorderRequiresEmptyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "old.json"), []byte("{}"), 0o644)
+
+	if _, err := NewRecorder(dir, 0); err == nil || !strings.Contains(err.Error(), "is not empty") {
+		t.Errorf("NewRecorder() error = %v, want not empty", err)
+	}
+	if _, err := NewReplayer(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no recording found") {
+		t.Errorf("NewReplayer() error = %v, want no recording", err)
+	}
+}
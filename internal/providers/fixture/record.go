@@ -0,0 +1,140 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Recorder writes the traffic of wrapped transports to a directory.
+type Recorder struct {
+	dir         string
+	maxBodySize config.ByteSize
+
+	mu    sync.Mutex
+	count map[string]int
+	err   error
+}
+
+// NewRecorder creates a recorder writing to dir, creating it if needed. The
+// directory must be empty so recordings are not mixed.
+//
+// Response bodies are recorded up to maxBodySize plus one byte, which is as
+// much as a client enforcing that limit reads, so replay rejects an oversized
+// body the same way. Zero records bodies whole.
+func NewRecorder(dir string, maxBodySize config.ByteSize) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read record directory: %w", err)
+	}
+	if len(entries) > 0 {
+		return nil, fmt.Errorf("record directory %s is not empty", dir)
+	}
+
+	return &Recorder{dir: dir, maxBodySize: maxBodySize, count: make(map[string]int)}, nil
+}
+
+// Wrap returns a transport that sends requests through next and records
+// them under the environment's name. It is a providers.TransportWrapper.
+func (r *Recorder) Wrap(name string, env config.Environment, next http.RoundTripper) http.RoundTripper {
+	return &recordTransport{recorder: r, name: name, redact: newRedactor(env), next: next}
+}
+
+// Err returns the first error writing the recording, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// save writes an interaction as the environment's next file.
+func (r *Recorder) save(name string, interaction Interaction) {
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		r.fail(err)
+		return
+	}
+
+	r.mu.Lock()
+	r.count[name]++
+	n := r.count[name]
+	r.mu.Unlock()
+
+	dir := filepath.Join(r.dir, url.PathEscape(name))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		r.fail(err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("%04d.json", n)), append(data, '\n'), 0o644); err != nil {
+		r.fail(err)
+	}
+}
+
+func (r *Recorder) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = fmt.Errorf("failed to record request: %w", err)
+	}
+}
+
+type recordTransport struct {
+	recorder *Recorder
+	name     string
+	redact   redactor
+	next     http.RoundTripper
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	interaction, err := t.redact.request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		interaction.Error = t.redact.text(err.Error())
+		t.recorder.save(t.name, interaction)
+		return nil, err
+	}
+
+	var r io.Reader = resp.Body
+	if t.recorder.maxBodySize > 0 {
+		r = io.LimitReader(resp.Body, int64(t.recorder.maxBodySize)+1)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	// The client reads what was recorded, then whatever was left unread.
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+	interaction.Status = resp.StatusCode
+	interaction.Headers = t.redact.headers(resp.Header)
+	interaction.Body = t.redact.text(string(body))
+	t.recorder.save(t.name, interaction)
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport.
+func (t *recordTransport) CloseIdleConnections() {
+	if closer, ok := t.next.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
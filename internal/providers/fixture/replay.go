@@ -0,0 +1,171 @@
+package fixture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Replayer answers requests from a recording instead of the network.
+// Identical requests get the responses recorded for them in order; once
+// they run out, GET and HEAD requests get the last one again, so polling
+// can take more rounds than it did when recording. Any other request fails
+// and is reported by Err.
+type Replayer struct {
+	mu           sync.Mutex
+	environments map[string]map[string]*replies
+	mismatches   []string
+}
+
+// replies are the recorded responses to one request.
+type replies struct {
+	interactions []Interaction
+	next         int
+}
+
+// NewReplayer loads the recording in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay directory: %w", err)
+	}
+
+	r := &Replayer{environments: make(map[string]map[string]*replies)}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("invalid environment directory %s: %w", entry.Name(), err)
+		}
+		recorded, err := load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		r.environments[name] = recorded
+	}
+
+	if len(r.environments) == 0 {
+		return nil, fmt.Errorf("no recording found in %s", dir)
+	}
+	return r, nil
+}
+
+// load reads an environment's interactions in the order they were recorded.
+func load(dir string) (map[string]*replies, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	recorded := make(map[string]*replies)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+
+		key := interaction.key()
+		if recorded[key] == nil {
+			recorded[key] = &replies{}
+		}
+		recorded[key].interactions = append(recorded[key].interactions, interaction)
+	}
+	return recorded, nil
+}
+
+// Wrap returns a transport that answers the environment's requests from
+// the recording; next is never used. It is a providers.TransportWrapper.
+func (r *Replayer) Wrap(name string, env config.Environment, next http.RoundTripper) http.RoundTripper {
+	return &replayTransport{replayer: r, name: name, redact: newRedactor(env)}
+}
+
+// Err reports the requests that did not match the recording, if any.
+func (r *Replayer) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.mismatches) == 0 {
+		return nil
+	}
+	return fmt.Errorf("replay: %d request(s) did not match the recording: %s", len(r.mismatches), strings.Join(r.mismatches, "; "))
+}
+
+// reply returns the next recorded interaction for a request.
+func (r *Replayer) reply(name string, req Interaction) (Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded, ok := r.environments[name][req.key()]
+	switch {
+	case !ok:
+		return r.mismatch(fmt.Sprintf("no recorded response for %s %s in environment %s", req.Method, req.URL, name))
+	case recorded.next < len(recorded.interactions):
+		recorded.next++
+		return recorded.interactions[recorded.next-1], nil
+	case req.Method == http.MethodGet || req.Method == http.MethodHead:
+		return recorded.interactions[len(recorded.interactions)-1], nil
+	default:
+		return r.mismatch(fmt.Sprintf("%s %s in environment %s was sent more often than recorded", req.Method, req.URL, name))
+	}
+}
+
+func (r *Replayer) mismatch(message string) (Interaction, error) {
+	r.mismatches = append(r.mismatches, message)
+	return Interaction{}, errors.New("replay: " + message)
+}
+
+type replayTransport struct {
+	replayer *Replayer
+	name     string
+	redact   redactor
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	interaction, err := t.redact.request(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, err := t.replayer.reply(t.name, interaction)
+	if err != nil {
+		return nil, err
+	}
+	if recorded.Error != "" {
+		return nil, errors.New(recorded.Error)
+	}
+
+	header := recorded.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
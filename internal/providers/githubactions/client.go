@@ -0,0 +1,157 @@
+package githubactions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// pageSize is the number of items requested per page.
+const pageSize = 100
+
+// Client is a GitHub Actions REST API client.
+type Client struct {
+	baseURL    string
+	token      string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewClient creates a new GitHub Actions client. The environment URL is the
+// API root, e.g. https://api.github.com or https://ghe.example.com/api/v3.
+// If httpClient is nil, a dedicated client is created; otherwise it is shared.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = providers.NewHTTPClient(env.Transport)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(env.URL, "/"),
+		token:      env.Auth.Token,
+		headers:    env.Headers,
+		httpClient: httpClient,
+	}
+}
+
+// Dispatch triggers a workflow_dispatch event for the workflow. The API does
+// not return the created run.
+func (c *Client) Dispatch(ctx context.Context, repo, workflow, ref string, inputs map[string]string) error {
+	path := fmt.Sprintf("/repos/%s/actions/workflows/%s/dispatches", repo, url.PathEscape(workflow))
+	return c.do(ctx, http.MethodPost, path, DispatchRequest{Ref: ref, Inputs: inputs}, nil)
+}
+
+// ListDispatchRuns lists the most recent workflow_dispatch runs of the
+// workflow on the given ref.
+func (c *Client) ListDispatchRuns(ctx context.Context, repo, workflow, ref string) ([]WorkflowRun, error) {
+	query := url.Values{
+		"event":    {"workflow_dispatch"},
+		"branch":   {ref},
+		"per_page": {"30"},
+	}
+
+	var list WorkflowRunList
+	path := fmt.Sprintf("/repos/%s/actions/workflows/%s/runs?%s", repo, url.PathEscape(workflow), query.Encode())
+	if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+		return nil, err
+	}
+	return list.WorkflowRuns, nil
+}
+
+// GetRun retrieves a workflow run.
+func (c *Client) GetRun(ctx context.Context, repo string, id int64) (*WorkflowRun, error) {
+	var run WorkflowRun
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/actions/runs/%d", repo, id), nil, &run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CancelRun cancels a workflow run.
+func (c *Client) CancelRun(ctx context.Context, repo string, id int64) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/actions/runs/%d/cancel", repo, id), nil, nil)
+}
+
+// ListJobs lists the jobs of the latest attempt of a workflow run.
+func (c *Client) ListJobs(ctx context.Context, repo string, id int64) ([]Job, error) {
+	var all []Job
+
+	for page := 1; ; page++ {
+		var list JobList
+		path := fmt.Sprintf("/repos/%s/actions/runs/%d/jobs?per_page=%d&page=%d", repo, id, pageSize, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &list); err != nil {
+			return nil, err
+		}
+
+		all = append(all, list.Jobs...)
+		if len(list.Jobs) < pageSize || len(all) >= list.TotalCount {
+			return all, nil
+		}
+	}
+}
+
+// do performs an API request and decodes the JSON response into out, if set.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		jsonData, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.parseError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// parseError builds an error from a non-successful GitHub response.
+func (c *Client) parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		return fmt.Errorf("github error [%d]: %s", resp.StatusCode, errResp.Message)
+	}
+
+	return fmt.Errorf("github request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
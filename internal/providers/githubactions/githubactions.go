@@ -0,0 +1,357 @@
+package githubactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// lookupTimeout bounds the last look for a dispatched run after waiting for
+// it timed out.
+const lookupTimeout = 10 * time.Second
+
+// Provider implements the GitHub Actions workflow provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+}
+
+// NewProvider creates a new GitHub Actions provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "github_actions"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Capabilities describes what the GitHub Actions provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Polling: true, Abort: true}
+}
+
+// Abort cancels the workflow run behind the result if it was found and had
+// not finished when Execute returned.
+func (p *Provider) Abort(ctx context.Context, job config.Job, env config.Environment, result *providers.Result) (bool, error) {
+	id, ok := result.Details["run_id"].(int64)
+	if !ok || !result.RemoteRunning {
+		return false, nil
+	}
+
+	spec, _, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return false, fmt.Errorf("invalid spec: %v", errs)
+	}
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+	if err := client.CancelRun(ctx, spec.repo(), id); err != nil {
+		return false, fmt.Errorf("failed to cancel run %d: %w", id, err)
+	}
+	return true, nil
+}
+
+// Execute dispatches a workflow, waits for the created run to finish and
+// returns the result.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "github_actions",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.Timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+	repo := spec.repo()
+
+	// The dispatch API does not return the run it creates, so remember the
+	// existing runs and wait for a new one to appear.
+	existing, err := client.ListDispatchRuns(ctx, repo, spec.JobID, spec.Ref)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to list workflow runs: %v", err)), nil
+	}
+	known := make(map[int64]bool, len(existing))
+	for _, run := range existing {
+		known[run.ID] = true
+	}
+
+	p.reportProgress(job.Name, providers.StatusPending, "Dispatching workflow...")
+
+	if err := client.Dispatch(ctx, repo, spec.JobID, spec.Ref, spec.Options); err != nil {
+		return p.fail(result, fmt.Sprintf("failed to dispatch workflow: %v", err)), nil
+	}
+
+	pollInterval := job.PollInterval
+
+	run, err := p.waitForRun(ctx, client, job.Name, spec, known, pollInterval)
+	if err != nil {
+		// The run may have been created just as we gave up waiting. Look
+		// once more so that Abort can cancel it.
+		if run := findRun(ctx, client, spec, known); run != nil {
+			result.Details["run_id"] = run.ID
+			result.Details["run_url"] = run.HTMLURL
+			result.RemoteRunning = true
+		}
+		return p.fail(result, timeoutError(err, timeout).Error()), nil
+	}
+	result.Details["run_id"] = run.ID
+	result.Details["run_url"] = run.HTMLURL
+	result.RemoteRunning = true
+	result.Status = providers.StatusRunning
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Run %d started", run.ID))
+
+	run, err = p.pollRun(ctx, client, job, repo, run.ID, pollInterval)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("polling failed: %v", timeoutError(err, timeout))), nil
+	}
+
+	result.Details["conclusion"] = run.Conclusion
+	result.RemoteRunning = false
+	result.Status = mapConclusion(run.Conclusion)
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if job.Assertions.Status != "" {
+		if strings.EqualFold(run.Conclusion, job.Assertions.Status) {
+			result.Status = providers.StatusSucceeded
+		} else {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("expected conclusion '%s', got '%s'",
+				strings.ToLower(job.Assertions.Status), run.Conclusion))
+		}
+	} else if result.Status != providers.StatusSucceeded {
+		failures = append(failures, fmt.Sprintf("workflow run finished with conclusion %s", run.Conclusion))
+	}
+
+	if job.Assertions.MaxDuration > 0 {
+		duration := runDuration(run, result.Duration)
+		if duration > job.Assertions.MaxDuration {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+				duration, job.Assertions.MaxDuration))
+		}
+	}
+
+	if run.Conclusion != ConclusionSuccess || len(assertions.Tasks) > 0 {
+		jobs, err := client.ListJobs(ctx, repo, run.ID)
+		if err != nil {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("failed to list jobs: %v", err))
+		} else {
+			if failed := failedJobs(jobs); len(failed) > 0 {
+				result.Details["failed_jobs"] = failed
+			}
+			if jobFailures := checkJobs(jobs, assertions.Tasks); len(jobFailures) > 0 {
+				result.Status = providers.StatusFailed
+				failures = append(failures, jobFailures...)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("Run %d finished: %s", run.ID, run.Conclusion))
+
+	return result, nil
+}
+
+// waitForRun waits until the dispatched run shows up in the run list.
+func (p *Provider) waitForRun(ctx context.Context, client *Client, jobName string, spec JobSpec, known map[int64]bool, interval time.Duration) (*WorkflowRun, error) {
+	for {
+		runs, err := client.ListDispatchRuns(ctx, spec.repo(), spec.JobID, spec.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+		if found := newRun(runs, known); found != nil {
+			return found, nil
+		}
+
+		p.reportProgress(jobName, providers.StatusPending, "Waiting for workflow run to be created...")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// findRun lists the runs once more after waitForRun gave up. It uses a
+// fresh deadline because ctx is usually done by now.
+func findRun(ctx context.Context, client *Client, spec JobSpec, known map[int64]bool) *WorkflowRun {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lookupTimeout)
+	defer cancel()
+
+	runs, err := client.ListDispatchRuns(ctx, spec.repo(), spec.JobID, spec.Ref)
+	if err != nil {
+		return nil
+	}
+	return newRun(runs, known)
+}
+
+// newRun returns the run that is not among the known runs. Runs are listed
+// newest first; it takes the oldest new run in case another dispatch
+// happened right after ours.
+func newRun(runs []WorkflowRun, known map[int64]bool) *WorkflowRun {
+	var found *WorkflowRun
+	for i := range runs {
+		if !known[runs[i].ID] {
+			found = &runs[i]
+		}
+	}
+	return found
+}
+
+// pollRun polls a workflow run until it completes.
+func (p *Provider) pollRun(ctx context.Context, client *Client, job config.Job, repo string, id int64, interval time.Duration) (*WorkflowRun, error) {
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-ticker.C:
+			run, err := client.GetRun(ctx, repo, id)
+			if err != nil {
+				return nil, err
+			}
+
+			p.reportProgress(job.Name, providers.StatusRunning,
+				fmt.Sprintf("Polling... (%s) status=%s", time.Since(start).Round(time.Second), run.Status))
+
+			if run.Status == RunStatusCompleted {
+				return run, nil
+			}
+		}
+	}
+}
+
+// failedJobs lists the names and URLs of failed jobs.
+func failedJobs(jobs []Job) []map[string]interface{} {
+	var failed []map[string]interface{}
+	for _, j := range jobs {
+		if j.Conclusion == ConclusionFailure || j.Conclusion == ConclusionTimedOut {
+			failed = append(failed, map[string]interface{}{
+				"name": j.Name,
+				"url":  j.HTMLURL,
+			})
+		}
+	}
+	return failed
+}
+
+// checkJobs compares job conclusions against the expected conclusions.
+func checkJobs(jobs []Job, expected map[string]string) []string {
+	byName := make(map[string]Job, len(jobs))
+	for _, j := range jobs {
+		byName[j.Name] = j
+	}
+
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		want := strings.ToLower(expected[name])
+		j, ok := byName[name]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("job '%s' not found in workflow run", name))
+			continue
+		}
+		if j.Conclusion != want {
+			failures = append(failures, fmt.Sprintf("job '%s' expected conclusion '%s', got '%s'",
+				name, want, j.Conclusion))
+		}
+	}
+	return failures
+}
+
+// runDuration returns the run duration reported by GitHub, falling back to
+// the locally measured duration.
+func runDuration(run *WorkflowRun, fallback time.Duration) time.Duration {
+	if run.RunStartedAt.IsZero() || run.UpdatedAt.IsZero() {
+		return fallback
+	}
+	return run.UpdatedAt.Sub(run.RunStartedAt)
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// mapConclusion maps a workflow run conclusion to a provider status.
+func mapConclusion(conclusion string) providers.Status {
+	switch conclusion {
+	case ConclusionSuccess:
+		return providers.StatusSucceeded
+	case ConclusionCancelled:
+		return providers.StatusAborted
+	case ConclusionTimedOut:
+		return providers.StatusTimedOut
+	default:
+		return providers.StatusFailed
+	}
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
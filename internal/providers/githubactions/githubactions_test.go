@@ -0,0 +1,261 @@
+package githubactions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// fakeGitHub simulates the parts of the GitHub Actions API used by the provider.
+type fakeGitHub struct {
+	mu         sync.Mutex
+	conclusion string
+	dispatched *DispatchRequest
+	listCalls  int
+	runPolls   int
+}
+
+func (f *fakeGitHub) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/repos/acme/ops/actions/workflows/maintenance.yml/dispatches", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Bad credentials"}`)
+			return
+		}
+
+		var req DispatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		f.dispatched = &req
+		f.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("/repos/acme/ops/actions/workflows/maintenance.yml/runs", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.listCalls++
+
+		if r.URL.Query().Get("event") != "workflow_dispatch" || r.URL.Query().Get("branch") != "main" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// The new run only shows up on the third listing.
+		if f.listCalls < 3 {
+			fmt.Fprint(w, `{"total_count":1,"workflow_runs":[{"id":100,"status":"completed","conclusion":"success"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"total_count":2,"workflow_runs":[{"id":101,"status":"queued"},{"id":100,"status":"completed","conclusion":"success"}]}`)
+	})
+
+	mux.HandleFunc("/repos/acme/ops/actions/runs/101", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.runPolls++
+		if f.runPolls < 2 {
+			fmt.Fprint(w, `{"id":101,"status":"in_progress"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":101,"status":"completed","conclusion":"%s","html_url":"https://github.com/acme/ops/actions/runs/101",`+
+			`"run_started_at":"2024-01-01T00:00:00Z","updated_at":"2024-01-01T00:03:00Z"}`, f.conclusion)
+	})
+
+	mux.HandleFunc("/repos/acme/ops/actions/runs/101/jobs", func(w http.ResponseWriter, r *http.Request) {
+		success := f.conclusion == ConclusionSuccess
+		fmt.Fprintf(w, `{"total_count":2,"jobs":[
+			{"id":1,"name":"vacuum","status":"completed","conclusion":"success","html_url":"https://github.com/acme/ops/actions/runs/101/job/1"},
+			{"id":2,"name":"reindex","status":"completed","conclusion":"%s","html_url":"https://github.com/acme/ops/actions/runs/101/job/2"}
+		]}`, map[bool]string{true: "success", false: "failure"}[success])
+	})
+
+	return mux
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		conclusion string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+		wantFailed []map[string]interface{}
+	}{
+		{
+			name:       "success",
+			conclusion: ConclusionSuccess,
+			assertions: config.Assertions{Settings: map[string]any{"tasks": map[string]any{"reindex": "success"}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "failure reports failed jobs",
+			conclusion: ConclusionFailure,
+			wantStatus: providers.StatusFailed,
+			wantError:  "workflow run finished with conclusion failure",
+			wantFailed: []map[string]interface{}{
+				{"name": "reindex", "url": "https://github.com/acme/ops/actions/runs/101/job/2"},
+			},
+		},
+		{
+			name:       "cancelled",
+			conclusion: ConclusionCancelled,
+			wantStatus: providers.StatusAborted,
+			wantError:  "workflow run finished with conclusion cancelled",
+		},
+		{
+			name:       "job assertion",
+			conclusion: ConclusionFailure,
+			assertions: config.Assertions{Status: "failure", Settings: map[string]any{"tasks": map[string]any{"reindex": "success"}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "job 'reindex' expected conclusion 'success', got 'failure'",
+		},
+		{
+			name:       "duration exceeded",
+			conclusion: ConclusionSuccess,
+			assertions: config.Assertions{MaxDuration: time.Minute},
+			wantStatus: providers.StatusFailed,
+			wantError:  "duration 3m0s exceeded max 1m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGitHub{conclusion: tt.conclusion}
+			server := httptest.NewServer(fake.handler())
+			defer server.Close()
+
+			env := config.Environment{
+				Type: "github_actions",
+				URL:  server.URL,
+				Auth: config.Auth{Type: "bearer", Token: "gh-token"},
+			}
+			job := config.Job{
+				Name:         "maintenance",
+				Type:         "github_actions",
+				Timeout:      5 * time.Second,
+				PollInterval: 10 * time.Millisecond,
+				Assertions:   tt.assertions,
+				Spec: map[string]any{
+					"project": "acme/ops",
+					"job_id":  "maintenance.yml",
+					"ref":     "main",
+					"options": map[string]any{"dry_run": "false"},
+				},
+			}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if result.Details["run_id"] != int64(101) {
+				t.Errorf("run_id = %v, want 101", result.Details["run_id"])
+			}
+			if fake.dispatched == nil || fake.dispatched.Ref != "main" || fake.dispatched.Inputs["dry_run"] != "false" {
+				t.Errorf("dispatch request = %+v", fake.dispatched)
+			}
+
+			failed, _ := result.Details["failed_jobs"].([]map[string]interface{})
+			if tt.wantFailed != nil && !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("failed_jobs = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestExecuteBadCredentials(t *testing.T) {
+	server := httptest.NewServer((&fakeGitHub{}).handler())
+	defer server.Close()
+
+	job := config.Job{
+		Name:    "maintenance",
+		Type:    "github_actions",
+		Spec:    map[string]any{"project": "acme/ops", "job_id": "maintenance.yml", "ref": "main"},
+		Timeout: time.Second,
+	}
+
+	result, err := NewProvider().Execute(context.Background(), job, config.Environment{Type: "github_actions", URL: server.URL})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != "failed to dispatch workflow: github error [401]: Bad credentials" {
+		t.Errorf("Error = %q", result.Error)
+	}
+}
+
+func TestExecuteRunAppearsAfterTimeout(t *testing.T) {
+	fake := &fakeGitHub{conclusion: ConclusionSuccess}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	env := config.Environment{
+		Type: "github_actions",
+		URL:  server.URL,
+		Auth: config.Auth{Type: "bearer", Token: "gh-token"},
+	}
+	job := config.Job{
+		Name:         "maintenance",
+		Type:         "github_actions",
+		Timeout:      50 * time.Millisecond,
+		PollInterval: time.Hour,
+		Spec:         map[string]any{"project": "acme/ops", "job_id": "maintenance.yml", "ref": "main"},
+	}
+
+	// The run only shows up on the listing after the wait timed out.
+	result, err := NewProvider().Execute(context.Background(), job, env)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != providers.StatusFailed || result.Error != "timeout after 50ms" {
+		t.Errorf("result = %s %q, want timeout", result.Status, result.Error)
+	}
+	if result.Details["run_id"] != int64(101) {
+		t.Errorf("run_id = %v, want 101 so Abort can cancel the run", result.Details["run_id"])
+	}
+}
+
+func TestAbort(t *testing.T) {
+	var cancelled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancelled = append(cancelled, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "github_actions", URL: server.URL}
+	job := config.Job{Name: "maintenance", Type: "github_actions", Spec: map[string]any{"project": "acme/ops", "job_id": "maintenance.yml"}}
+
+	running := &providers.Result{Status: providers.StatusFailed, RemoteRunning: true, Details: map[string]interface{}{"run_id": int64(101)}}
+	aborted, err := NewProvider().Abort(context.Background(), job, env, running)
+	if err != nil || !aborted {
+		t.Fatalf("Abort() = %t, %v, want run cancelled", aborted, err)
+	}
+	if len(cancelled) != 1 || cancelled[0] != "POST /repos/acme/ops/actions/runs/101/cancel" {
+		t.Errorf("requests = %v", cancelled)
+	}
+
+	finished := &providers.Result{Status: providers.StatusFailed, Details: map[string]interface{}{"run_id": int64(101), "conclusion": "failure"}}
+	if aborted, err := NewProvider().Abort(context.Background(), job, env, finished); aborted || err != nil {
+		t.Errorf("Abort() = %t, %v for a finished run", aborted, err)
+	}
+}
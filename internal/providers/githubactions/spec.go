@@ -0,0 +1,95 @@
+package githubactions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a github_actions job.
+type JobSpec struct {
+	// Project is the repository as owner/repo.
+	Project string `yaml:"project"`
+
+	// JobID is the workflow file name or ID.
+	JobID string `yaml:"job_id"`
+
+	// Ref is the branch or tag the workflow runs on.
+	Ref string `yaml:"ref"`
+
+	// Options are passed to the workflow as its inputs.
+	Options map[string]string `yaml:"options"`
+}
+
+// repo returns the repository without surrounding slashes.
+func (s JobSpec) repo() string {
+	return strings.Trim(s.Project, "/")
+}
+
+// Assertions holds the GitHub Actions-specific assertions of a job.
+type Assertions struct {
+	// Tasks maps job names to their expected conclusion.
+	Tasks map[string]string `yaml:"tasks"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// validConclusions are the run conclusions that may be asserted.
+var validConclusions = map[string]bool{
+	"":          true,
+	"success":   true,
+	"failure":   true,
+	"cancelled": true,
+	"skipped":   true,
+	"timed_out": true,
+	"neutral":   true,
+}
+
+// ValidateEnvironment validates a GitHub Actions environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	errs = append(errs, providers.RequireURL(env)...)
+	return append(errs, providers.ValidateAuthType(env, "github_actions", "bearer")...)
+}
+
+// ValidateJob validates a GitHub Actions job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, _, errs := decodeJob(job)
+
+	if !strings.Contains(spec.repo(), "/") {
+		errs = append(errs, config.ValidationError{
+			Field:   "project",
+			Message: "is required for github_actions jobs (owner/repo)",
+		})
+	}
+	if spec.JobID == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "job_id",
+			Message: "is required for github_actions jobs (workflow file name or ID)",
+		})
+	}
+	if spec.Ref == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "ref",
+			Message: "is required for github_actions jobs",
+		})
+	}
+
+	if !validConclusions[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid conclusion '%s', must be one of: success, failure, cancelled, skipped, timed_out, neutral", job.Assertions.Status),
+		})
+	}
+
+	return errs
+}
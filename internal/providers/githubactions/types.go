@@ -0,0 +1,59 @@
+// Package githubactions provides a GitHub Actions workflow provider.
+package githubactions
+
+import "time"
+
+// Run statuses and conclusions reported by the GitHub API.
+const (
+	RunStatusCompleted = "completed"
+
+	ConclusionSuccess   = "success"
+	ConclusionFailure   = "failure"
+	ConclusionCancelled = "cancelled"
+	ConclusionTimedOut  = "timed_out"
+)
+
+// DispatchRequest represents a workflow_dispatch request.
+type DispatchRequest struct {
+	Ref    string            `json:"ref"`
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// WorkflowRun represents a workflow run.
+type WorkflowRun struct {
+	ID           int64     `json:"id"`
+	Status       string    `json:"status"`
+	Conclusion   string    `json:"conclusion"`
+	HTMLURL      string    `json:"html_url"`
+	HeadBranch   string    `json:"head_branch"`
+	Event        string    `json:"event"`
+	CreatedAt    time.Time `json:"created_at"`
+	RunStartedAt time.Time `json:"run_started_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// WorkflowRunList represents a page of workflow runs.
+type WorkflowRunList struct {
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []WorkflowRun `json:"workflow_runs"`
+}
+
+// Job represents a job of a workflow run.
+type Job struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	HTMLURL    string `json:"html_url"`
+}
+
+// JobList represents a page of workflow run jobs.
+type JobList struct {
+	TotalCount int   `json:"total_count"`
+	Jobs       []Job `json:"jobs"`
+}
+
+// ErrorResponse represents a GitHub API error.
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,167 @@
+package gitlabci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// pageSize is the number of items requested per page.
+const pageSize = 100
+
+// Client is a GitLab REST API client.
+type Client struct {
+	baseURL    string
+	auth       config.Auth
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewClient creates a new GitLab client. If httpClient is nil, a dedicated
+// client is created; otherwise it is shared.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = providers.NewHTTPClient(env.Transport)
+	}
+
+	// api_key auth defaults to the PRIVATE-TOKEN header used by GitLab
+	// personal access tokens.
+	auth := env.Auth
+	if auth.Type == "api_key" && auth.Header == "" {
+		auth.Header = "PRIVATE-TOKEN"
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(env.URL, "/") + "/api/v4",
+		auth:       auth,
+		headers:    env.Headers,
+		httpClient: httpClient,
+	}
+}
+
+// CreatePipeline creates a pipeline for the ref with the given variables.
+func (c *Client) CreatePipeline(ctx context.Context, project, ref string, variables map[string]string) (*Pipeline, error) {
+	req := CreatePipelineRequest{Ref: ref}
+	for key, value := range variables {
+		req.Variables = append(req.Variables, Variable{Key: key, Value: value})
+	}
+	sort.Slice(req.Variables, func(i, j int) bool { return req.Variables[i].Key < req.Variables[j].Key })
+
+	var pipeline Pipeline
+	if err := c.do(ctx, http.MethodPost, projectPath(project)+"/pipeline", req, &pipeline); err != nil {
+		return nil, err
+	}
+	return &pipeline, nil
+}
+
+// GetPipeline retrieves a pipeline.
+func (c *Client) GetPipeline(ctx context.Context, project string, id int) (*Pipeline, error) {
+	var pipeline Pipeline
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/pipelines/%d", projectPath(project), id), nil, &pipeline); err != nil {
+		return nil, err
+	}
+	return &pipeline, nil
+}
+
+// CancelPipeline cancels a running pipeline.
+func (c *Client) CancelPipeline(ctx context.Context, project string, id int) (*Pipeline, error) {
+	var pipeline Pipeline
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("%s/pipelines/%d/cancel", projectPath(project), id), nil, &pipeline); err != nil {
+		return nil, err
+	}
+	return &pipeline, nil
+}
+
+// ListJobs lists the jobs of a pipeline, excluding retried attempts.
+func (c *Client) ListJobs(ctx context.Context, project string, id int) ([]Job, error) {
+	var all []Job
+
+	for page := 1; ; page++ {
+		var jobs []Job
+		path := fmt.Sprintf("%s/pipelines/%d/jobs?per_page=%d&page=%d", projectPath(project), id, pageSize, page)
+		if err := c.do(ctx, http.MethodGet, path, nil, &jobs); err != nil {
+			return nil, err
+		}
+
+		all = append(all, jobs...)
+		if len(jobs) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+// projectPath returns the API path of a project given its ID or full path.
+func projectPath(project string) string {
+	return "/projects/" + url.PathEscape(strings.Trim(project, "/"))
+}
+
+// do performs an API request and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		jsonData, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	providers.ApplyAuth(req, c.auth)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.parseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// parseError builds an error from a non-successful GitLab response.
+func (c *Client) parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil {
+		switch msg := errResp.Message.(type) {
+		case string:
+			return fmt.Errorf("gitlab error [%d]: %s", resp.StatusCode, msg)
+		case map[string]any:
+			detail, _ := json.Marshal(msg)
+			return fmt.Errorf("gitlab error [%d]: %s", resp.StatusCode, detail)
+		}
+		if errResp.Error != "" {
+			return fmt.Errorf("gitlab error [%d]: %s", resp.StatusCode, errResp.Error)
+		}
+	}
+
+	return fmt.Errorf("gitlab request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,284 @@
+package gitlabci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// Provider implements the GitLab CI pipeline provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+}
+
+// NewProvider creates a new GitLab CI provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "gitlab_ci"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Capabilities describes what the GitLab CI provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Polling: true, Abort: true}
+}
+
+// Abort cancels the pipeline behind the result if it was created and had
+// not finished when Execute returned.
+func (p *Provider) Abort(ctx context.Context, job config.Job, env config.Environment, result *providers.Result) (bool, error) {
+	id, ok := result.Details["pipeline_id"].(int)
+	if !ok || !result.RemoteRunning {
+		return false, nil
+	}
+
+	spec, _, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return false, fmt.Errorf("invalid spec: %v", errs)
+	}
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+	if _, err := client.CancelPipeline(ctx, spec.Project, id); err != nil {
+		return false, fmt.Errorf("failed to cancel pipeline #%d: %w", id, err)
+	}
+	return true, nil
+}
+
+// Execute creates a pipeline, waits for it to finish and returns the result.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "gitlab_ci",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.Timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+
+	p.reportProgress(job.Name, providers.StatusPending, "Creating pipeline...")
+
+	pipeline, err := client.CreatePipeline(ctx, spec.Project, spec.Ref, spec.Options)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to create pipeline: %v", err)), nil
+	}
+	result.Details["pipeline_id"] = pipeline.ID
+	result.Details["pipeline_url"] = pipeline.WebURL
+	result.RemoteRunning = true
+	result.Status = providers.StatusRunning
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Pipeline #%d created", pipeline.ID))
+
+	pipeline, err = p.pollPipeline(ctx, client, job.Name, spec.Project, pipeline.ID, job.PollInterval)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("polling failed: %v", timeoutError(err, timeout))), nil
+	}
+
+	result.Details["pipeline_status"] = string(pipeline.Status)
+	result.RemoteRunning = false
+	result.Status = mapStatus(pipeline.Status)
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if job.Assertions.Status != "" {
+		if strings.EqualFold(string(pipeline.Status), job.Assertions.Status) {
+			result.Status = providers.StatusSucceeded
+		} else {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("expected status '%s', got '%s'",
+				strings.ToLower(job.Assertions.Status), pipeline.Status))
+		}
+	} else if result.Status != providers.StatusSucceeded {
+		failures = append(failures, fmt.Sprintf("pipeline finished with status %s", pipeline.Status))
+	}
+
+	if job.Assertions.MaxDuration > 0 {
+		duration := result.Duration
+		if pipeline.Duration != nil {
+			duration = time.Duration(*pipeline.Duration) * time.Second
+		}
+		if duration > job.Assertions.MaxDuration {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+				duration, job.Assertions.MaxDuration))
+		}
+	}
+
+	jobs, err := client.ListJobs(ctx, spec.Project, pipeline.ID)
+	if err != nil {
+		result.Status = providers.StatusFailed
+		failures = append(failures, fmt.Sprintf("failed to list jobs: %v", err))
+	} else {
+		result.Details["jobs"] = jobCounts(jobs)
+		if failed := failedJobs(jobs); len(failed) > 0 {
+			result.Details["failed_jobs"] = failed
+		}
+		if jobFailures := checkJobs(jobs, assertions.Tasks); len(jobFailures) > 0 {
+			result.Status = providers.StatusFailed
+			failures = append(failures, jobFailures...)
+		}
+	}
+
+	if len(failures) > 0 {
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("Pipeline #%d finished: %s", pipeline.ID, pipeline.Status))
+
+	return result, nil
+}
+
+// pollPipeline polls a pipeline until it reaches a terminal status.
+func (p *Provider) pollPipeline(ctx context.Context, client *Client, jobName, project string, id int, interval time.Duration) (*Pipeline, error) {
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-ticker.C:
+			pipeline, err := client.GetPipeline(ctx, project, id)
+			if err != nil {
+				return nil, err
+			}
+
+			p.reportProgress(jobName, providers.StatusRunning,
+				fmt.Sprintf("Polling... (%s) status=%s", time.Since(start).Round(time.Second), pipeline.Status))
+
+			if pipeline.Status.IsTerminal() {
+				return pipeline, nil
+			}
+		}
+	}
+}
+
+// jobCounts counts pipeline jobs by status.
+func jobCounts(jobs []Job) map[string]int {
+	counts := make(map[string]int)
+	for _, j := range jobs {
+		counts[j.Status]++
+	}
+	return counts
+}
+
+// failedJobs lists the failed jobs with their stage and URL.
+func failedJobs(jobs []Job) []map[string]interface{} {
+	var failed []map[string]interface{}
+	for _, j := range jobs {
+		if j.Status == "failed" {
+			failed = append(failed, map[string]interface{}{
+				"name":          j.Name,
+				"stage":         j.Stage,
+				"url":           j.WebURL,
+				"allow_failure": j.AllowFailure,
+			})
+		}
+	}
+	return failed
+}
+
+// checkJobs compares job statuses against the expected statuses.
+func checkJobs(jobs []Job, expected map[string]string) []string {
+	byName := make(map[string]Job, len(jobs))
+	for _, j := range jobs {
+		byName[j.Name] = j
+	}
+
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		want := strings.ToLower(expected[name])
+		j, ok := byName[name]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("job '%s' not found in pipeline", name))
+			continue
+		}
+		if j.Status != want {
+			failures = append(failures, fmt.Sprintf("job '%s' expected status '%s', got '%s'",
+				name, want, j.Status))
+		}
+	}
+	return failures
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// mapStatus maps a pipeline status to a provider status.
+func mapStatus(status PipelineStatus) providers.Status {
+	switch status {
+	case PipelineStatusSuccess:
+		return providers.StatusSucceeded
+	case PipelineStatusCanceled:
+		return providers.StatusAborted
+	case PipelineStatusFailed, PipelineStatusSkipped, PipelineStatusManual:
+		return providers.StatusFailed
+	default:
+		return providers.StatusRunning
+	}
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,225 @@
+package gitlabci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// fakeGitLab simulates the parts of the GitLab API used by the provider.
+type fakeGitLab struct {
+	mu      sync.Mutex
+	status  PipelineStatus
+	created *CreatePipelineRequest
+	polls   int
+}
+
+func (f *fakeGitLab) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v4/projects/ops%2Fmaintenance/pipeline", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "glpat-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"401 Unauthorized"}`)
+			return
+		}
+
+		var req CreatePipelineRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		f.mu.Lock()
+		f.created = &req
+		f.mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":55,"status":"created","web_url":"https://gitlab.example.com/ops/maintenance/-/pipelines/55"}`)
+	})
+
+	mux.HandleFunc("/api/v4/projects/ops%2Fmaintenance/pipelines/55", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.polls++
+		if f.polls < 2 {
+			fmt.Fprint(w, `{"id":55,"status":"running"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"id":55,"status":"%s","duration":240,"web_url":"https://gitlab.example.com/ops/maintenance/-/pipelines/55"}`, f.status)
+	})
+
+	mux.HandleFunc("/api/v4/projects/ops%2Fmaintenance/pipelines/55/jobs", func(w http.ResponseWriter, r *http.Request) {
+		cleanup := "success"
+		if f.status == PipelineStatusFailed {
+			cleanup = "failed"
+		}
+		fmt.Fprintf(w, `[
+			{"id":1,"name":"backup","stage":"prepare","status":"success","web_url":"https://gitlab.example.com/ops/maintenance/-/jobs/1"},
+			{"id":2,"name":"cleanup","stage":"run","status":"%s","web_url":"https://gitlab.example.com/ops/maintenance/-/jobs/2"}
+		]`, cleanup)
+	})
+
+	return mux
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     PipelineStatus
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+		wantFailed []map[string]interface{}
+	}{
+		{
+			name:       "success",
+			status:     PipelineStatusSuccess,
+			assertions: config.Assertions{Settings: map[string]any{"tasks": map[string]any{"backup": "success", "cleanup": "success"}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "failed reports failed jobs",
+			status:     PipelineStatusFailed,
+			wantStatus: providers.StatusFailed,
+			wantError:  "pipeline finished with status failed",
+			wantFailed: []map[string]interface{}{{
+				"name":          "cleanup",
+				"stage":         "run",
+				"url":           "https://gitlab.example.com/ops/maintenance/-/jobs/2",
+				"allow_failure": false,
+			}},
+		},
+		{
+			name:       "canceled",
+			status:     PipelineStatusCanceled,
+			wantStatus: providers.StatusAborted,
+			wantError:  "pipeline finished with status canceled",
+		},
+		{
+			name:       "missing job",
+			status:     PipelineStatusSuccess,
+			assertions: config.Assertions{Settings: map[string]any{"tasks": map[string]any{"deploy": "success"}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "job 'deploy' not found in pipeline",
+		},
+		{
+			name:       "duration exceeded",
+			status:     PipelineStatusSuccess,
+			assertions: config.Assertions{MaxDuration: time.Minute},
+			wantStatus: providers.StatusFailed,
+			wantError:  "duration 4m0s exceeded max 1m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGitLab{status: tt.status}
+			server := httptest.NewServer(fake.handler())
+			defer server.Close()
+
+			env := config.Environment{
+				Type: "gitlab_ci",
+				URL:  server.URL,
+				Auth: config.Auth{Type: "api_key", APIKey: "glpat-123"},
+			}
+			job := config.Job{
+				Name:         "maintenance",
+				Type:         "gitlab_ci",
+				Timeout:      5 * time.Second,
+				PollInterval: 10 * time.Millisecond,
+				Assertions:   tt.assertions,
+				Spec: map[string]any{
+					"project": "ops/maintenance",
+					"ref":     "main",
+					"options": map[string]any{"MODE": "full", "DRY_RUN": "0"},
+				},
+			}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if result.Details["pipeline_id"] != 55 {
+				t.Errorf("pipeline_id = %v, want 55", result.Details["pipeline_id"])
+			}
+
+			wantVars := []Variable{{Key: "DRY_RUN", Value: "0"}, {Key: "MODE", Value: "full"}}
+			if fake.created == nil || fake.created.Ref != "main" || !reflect.DeepEqual(fake.created.Variables, wantVars) {
+				t.Errorf("create request = %+v", fake.created)
+			}
+
+			failed, _ := result.Details["failed_jobs"].([]map[string]interface{})
+			if !reflect.DeepEqual(failed, tt.wantFailed) {
+				t.Errorf("failed_jobs = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestExecuteUnauthorized(t *testing.T) {
+	server := httptest.NewServer((&fakeGitLab{}).handler())
+	defer server.Close()
+
+	job := config.Job{
+		Name:    "maintenance",
+		Type:    "gitlab_ci",
+		Spec:    map[string]any{"project": "ops/maintenance", "ref": "main"},
+		Timeout: time.Second,
+	}
+
+	result, err := NewProvider().Execute(context.Background(), job, config.Environment{Type: "gitlab_ci", URL: server.URL})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Error != "failed to create pipeline: gitlab error [401]: 401 Unauthorized" {
+		t.Errorf("Error = %q", result.Error)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	var cancelled []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancelled = append(cancelled, r.Method+" "+r.URL.EscapedPath())
+		fmt.Fprint(w, `{"id":55,"status":"canceled"}`)
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "gitlab_ci", URL: server.URL}
+	job := config.Job{Name: "maintenance", Type: "gitlab_ci", Spec: map[string]any{"project": "ops/maintenance"}}
+
+	running := &providers.Result{Status: providers.StatusFailed, RemoteRunning: true, Details: map[string]interface{}{"pipeline_id": 55}}
+	aborted, err := NewProvider().Abort(context.Background(), job, env, running)
+	if err != nil || !aborted {
+		t.Fatalf("Abort() = %t, %v, want pipeline cancelled", aborted, err)
+	}
+	if want := []string{"POST /api/v4/projects/ops%2Fmaintenance/pipelines/55/cancel"}; !reflect.DeepEqual(cancelled, want) {
+		t.Errorf("requests = %v, want %v", cancelled, want)
+	}
+
+	finished := &providers.Result{Status: providers.StatusFailed, Details: map[string]interface{}{"pipeline_id": 55, "pipeline_status": "failed"}}
+	if aborted, err := NewProvider().Abort(context.Background(), job, env, finished); aborted || err != nil {
+		t.Errorf("Abort() = %t, %v for a finished pipeline", aborted, err)
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	env := config.Environment{URL: "https://gitlab.com", Auth: config.Auth{Type: "basic", Username: "ci", Password: "secret"}}
+	if errs := NewProvider().ValidateEnvironment(env); !strings.Contains(errs.Error(), "auth.type: gitlab_ci environments support bearer or api_key auth") {
+		t.Errorf("ValidateEnvironment() = %v, want basic auth rejected", errs)
+	}
+}
@@ -0,0 +1,80 @@
+package gitlabci
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a gitlab_ci job.
+type JobSpec struct {
+	// Project is the project ID or path, e.g. group/project.
+	Project string `yaml:"project"`
+
+	// Ref is the branch or tag the pipeline runs on.
+	Ref string `yaml:"ref"`
+
+	// Options are passed to the pipeline as its variables.
+	Options map[string]string `yaml:"options"`
+}
+
+// Assertions holds the GitLab CI-specific assertions of a job.
+type Assertions struct {
+	// Tasks maps CI job names to their expected status.
+	Tasks map[string]string `yaml:"tasks"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// validStatuses are the pipeline statuses that may be asserted.
+var validStatuses = map[string]bool{
+	"":         true,
+	"success":  true,
+	"failed":   true,
+	"canceled": true,
+	"skipped":  true,
+	"manual":   true,
+}
+
+// ValidateEnvironment validates a GitLab CI environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	errs = append(errs, providers.RequireURL(env)...)
+	return append(errs, providers.ValidateAuthType(env, "gitlab_ci", "bearer", "api_key")...)
+}
+
+// ValidateJob validates a GitLab CI job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, _, errs := decodeJob(job)
+
+	if spec.Project == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "project",
+			Message: "is required for gitlab_ci jobs (project ID or path)",
+		})
+	}
+	if spec.Ref == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "ref",
+			Message: "is required for gitlab_ci jobs",
+		})
+	}
+
+	if !validStatuses[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid pipeline status '%s', must be one of: success, failed, canceled, skipped, manual", job.Assertions.Status),
+		})
+	}
+
+	return errs
+}
@@ -0,0 +1,63 @@
+// Package gitlabci provides a GitLab CI pipeline provider.
+package gitlabci
+
+// PipelineStatus represents the status of a GitLab pipeline.
+type PipelineStatus string
+
+const (
+	PipelineStatusSuccess  PipelineStatus = "success"
+	PipelineStatusFailed   PipelineStatus = "failed"
+	PipelineStatusCanceled PipelineStatus = "canceled"
+	PipelineStatusSkipped  PipelineStatus = "skipped"
+	PipelineStatusManual   PipelineStatus = "manual"
+)
+
+// IsTerminal returns true if the pipeline will not progress without
+// intervention. A manual pipeline is blocked on a manual job.
+func (s PipelineStatus) IsTerminal() bool {
+	switch s {
+	case PipelineStatusSuccess, PipelineStatusFailed, PipelineStatusCanceled,
+		PipelineStatusSkipped, PipelineStatusManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// Variable represents a pipeline variable.
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// CreatePipelineRequest represents a request to create a pipeline.
+type CreatePipelineRequest struct {
+	Ref       string     `json:"ref"`
+	Variables []Variable `json:"variables,omitempty"`
+}
+
+// Pipeline represents a GitLab pipeline.
+type Pipeline struct {
+	ID       int            `json:"id"`
+	Status   PipelineStatus `json:"status"`
+	Ref      string         `json:"ref"`
+	WebURL   string         `json:"web_url"`
+	Duration *int           `json:"duration"`
+}
+
+// Job represents a job of a pipeline.
+type Job struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Stage        string `json:"stage"`
+	Status       string `json:"status"`
+	WebURL       string `json:"web_url"`
+	AllowFailure bool   `json:"allow_failure"`
+}
+
+// ErrorResponse represents a GitLab API error. The message may be a string
+// or an object of field errors.
+type ErrorResponse struct {
+	Message any    `json:"message"`
+	Error   string `json:"error"`
+}
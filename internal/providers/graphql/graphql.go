@@ -0,0 +1,220 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+	httpprovider "github.com/user/jobprobe/internal/providers/http"
+)
+
+// Provider implements the GraphQL endpoint checking provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+}
+
+// NewProvider creates a new GraphQL provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "graphql"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Execute executes a GraphQL query or mutation and returns the result.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "graphql",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, err := decodeJobSpec(job)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	client := httpprovider.NewClient(env, p.clients.Client(job.Environment, env))
+
+	if spec.ValidateSchema {
+		p.reportProgress(job.Name, providers.StatusRunning, "Validating query against introspected schema...")
+
+		if err := p.validate(ctx, client, spec); err != nil {
+			return p.fail(result, err.Error()), nil
+		}
+		result.Details["schema_validated"] = true
+	}
+
+	req, err := buildRequest(spec, int64(job.MaxBodySize))
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	p.reportProgress(job.Name, providers.StatusRunning,
+		fmt.Sprintf("%s %s%s %s", spec.Method, env.URL, spec.Path, operationLabel(spec)))
+
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	result.Details["status_code"] = resp.StatusCode
+	result.Details["duration_ms"] = resp.Duration.Milliseconds()
+	result.Details["timing"] = resp.Timing.Details()
+	result.FinishedAt = time.Now()
+	result.Duration = resp.Duration
+
+	var errors []string
+
+	if job.Assertions.StatusCode > 0 {
+		if resp.StatusCode != job.Assertions.StatusCode {
+			errors = append(errors, fmt.Sprintf("expected status code %d, got %d",
+				job.Assertions.StatusCode, resp.StatusCode))
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errors = append(errors, fmt.Sprintf("unexpected status code %d", resp.StatusCode))
+	}
+
+	if job.Assertions.MaxDuration > 0 && resp.Duration > job.Assertions.MaxDuration {
+		errors = append(errors, fmt.Sprintf("duration %s exceeded max %s",
+			resp.Duration, job.Assertions.MaxDuration))
+	}
+
+	var gqlResp Response
+	if err := json.Unmarshal(resp.Body, &gqlResp); err != nil {
+		errors = append(errors, fmt.Sprintf("failed to parse GraphQL response: %v", err))
+	} else {
+		if len(gqlResp.Errors) > 0 {
+			messages := make([]string, len(gqlResp.Errors))
+			for i, e := range gqlResp.Errors {
+				messages[i] = e.String()
+			}
+			result.Details["graphql_errors"] = messages
+			errors = append(errors, fmt.Sprintf("GraphQL errors: %s", strings.Join(messages, "; ")))
+		}
+
+		errors = append(errors, assertion.CheckBody(resp.Body, assertion.FormatJSON, job.Assertions)...)
+	}
+
+	if len(errors) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(errors, "; ")
+	} else {
+		result.Status = providers.StatusSucceeded
+	}
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("Status: %d (%s)", resp.StatusCode, resp.Duration.Round(time.Millisecond)))
+
+	return result, nil
+}
+
+// validate checks the job's query against the server's introspected schema,
+// catching references to removed or renamed fields before the query runs.
+func (p *Provider) validate(ctx context.Context, client *httpprovider.Client, spec JobSpec) error {
+	schema, err := fetchSchema(ctx, client, spec.Path)
+	if err != nil {
+		return fmt.Errorf("schema validation: %w", err)
+	}
+
+	if problems := validateQuery(schema, spec.Query); len(problems) > 0 {
+		return fmt.Errorf("query does not match schema: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+// buildRequest builds the HTTP request for a GraphQL operation. POST sends a
+// JSON body; GET encodes the operation in the query string.
+func buildRequest(spec JobSpec, maxBodySize int64) (httpprovider.Request, error) {
+	req := httpprovider.Request{
+		Method:      spec.Method,
+		Path:        spec.Path,
+		Headers:     spec.Headers,
+		MaxBodySize: maxBodySize,
+	}
+
+	if spec.Method == "GET" {
+		params := url.Values{}
+		params.Set("query", spec.Query)
+		if len(spec.Variables) > 0 {
+			vars, err := json.Marshal(spec.Variables)
+			if err != nil {
+				return req, fmt.Errorf("failed to marshal variables: %w", err)
+			}
+			params.Set("variables", string(vars))
+		}
+		if spec.OperationName != "" {
+			params.Set("operationName", spec.OperationName)
+		}
+
+		sep := "?"
+		if strings.Contains(req.Path, "?") {
+			sep = "&"
+		}
+		req.Path += sep + params.Encode()
+		return req, nil
+	}
+
+	body := map[string]any{"query": spec.Query}
+	if len(spec.Variables) > 0 {
+		body["variables"] = spec.Variables
+	}
+	if spec.OperationName != "" {
+		body["operationName"] = spec.OperationName
+	}
+	req.Body = body
+
+	return req, nil
+}
+
+// operationLabel describes the operation for progress messages.
+func operationLabel(spec JobSpec) string {
+	if spec.OperationName != "" {
+		return "(" + spec.OperationName + ")"
+	}
+	return ""
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	p.reportProgress(result.JobName, result.Status, message)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
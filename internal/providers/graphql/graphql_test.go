@@ -0,0 +1,214 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// introspectionFixture describes a schema equivalent to:
+//
+//	type Query { user(id: ID!): User }
+//	type User { id: ID! name: String role: Role }
+//	enum Role { ADMIN MEMBER }
+const introspectionFixture = `{"data":{"__schema":{
+  "queryType":{"name":"Query"},"mutationType":null,"subscriptionType":null,
+  "directives":[{"name":"include","locations":["FIELD"],"args":[]}],
+  "types":[
+    {"kind":"OBJECT","name":"Query","fields":[
+      {"name":"user","args":[{"name":"id","type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID"}},"defaultValue":null}],
+       "type":{"kind":"OBJECT","name":"User"}}],"interfaces":[]},
+    {"kind":"OBJECT","name":"User","fields":[
+      {"name":"id","args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"ID"}}},
+      {"name":"name","args":[],"type":{"kind":"SCALAR","name":"String"}},
+      {"name":"role","args":[],"type":{"kind":"ENUM","name":"Role"}}],"interfaces":[]},
+    {"kind":"ENUM","name":"Role","enumValues":[{"name":"ADMIN"},{"name":"MEMBER"}]},
+    {"kind":"SCALAR","name":"ID"},
+    {"kind":"SCALAR","name":"String"},
+    {"kind":"OBJECT","name":"__Schema","fields":[]}
+  ]}}}`
+
+func newGraphQLServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if r.Method == http.MethodGet {
+			req.Query = r.URL.Query().Get("query")
+			json.Unmarshal([]byte(r.URL.Query().Get("variables")), &req.Variables)
+		} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(req.Query, "__schema"):
+			w.Write([]byte(introspectionFixture))
+		case req.Variables["id"] == "404":
+			w.Write([]byte(`{"data":{"user":null},"errors":[{"message":"user not found","path":["user"]}]}`))
+		default:
+			w.Write([]byte(`{"data":{"user":{"id":"1","name":"Ada","role":"ADMIN"}}}`))
+		}
+	}))
+}
+
+func TestExecute(t *testing.T) {
+	server := newGraphQLServer(t)
+	defer server.Close()
+
+	env := config.Environment{Type: "graphql", URL: server.URL}
+	query := `query GetUser($id: ID!) { user(id: $id) { id name role } }`
+
+	tests := []struct {
+		name      string
+		job       config.Job
+		wantError string
+	}{
+		{
+			name: "data assertion passes",
+			job: config.Job{
+				Spec: map[string]any{"query": query, "variables": map[string]any{"id": "1"}},
+				Assertions: config.Assertions{
+					JSON: []config.JSONAssertion{
+						{Path: "$.data.user.name", Matcher: config.Matcher{Equals: "Ada"}},
+					},
+				},
+			},
+		},
+		{
+			name: "GET request",
+			job: config.Job{
+				Spec: map[string]any{"method": "GET", "query": query, "variables": map[string]any{"id": "1"}},
+				Assertions: config.Assertions{
+					JSON: []config.JSONAssertion{
+						{Path: "$.data.user.role", Matcher: config.Matcher{Equals: "ADMIN"}},
+					},
+				},
+			},
+		},
+		{
+			name: "errors array fails the job",
+			job: config.Job{
+				Spec: map[string]any{"query": query, "variables": map[string]any{"id": "404"}},
+			},
+			wantError: "GraphQL errors: user: user not found",
+		},
+		{
+			name: "schema validation passes",
+			job: config.Job{
+				Spec: map[string]any{"query": query, "variables": map[string]any{"id": "1"}, "validate_schema": true},
+			},
+		},
+		{
+			name: "schema validation catches removed field",
+			job: config.Job{
+				Spec: map[string]any{"query": `query { user(id: "1") { id email } }`, "validate_schema": true},
+			},
+			wantError: `Cannot query field "email" on type "User"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.job.Name = "graphql-check"
+			tt.job.Type = "graphql"
+
+			result, err := NewProvider().Execute(context.Background(), tt.job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if tt.wantError == "" {
+				if result.Status != providers.StatusSucceeded {
+					t.Errorf("Status = %s, want succeeded (error: %s)", result.Status, result.Error)
+				}
+				return
+			}
+
+			if result.Status != providers.StatusFailed {
+				t.Errorf("Status = %s, want failed", result.Status)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestLoadQueryFile(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(dir, "jobs", "queries"), 0755); err != nil {
+		t.Fatalf("failed to create queries dir: %v", err)
+	}
+
+	query := "query { user(id: 1) { name } }"
+	if err := os.WriteFile(filepath.Join(dir, "jobs", "queries", "user.graphql"), []byte(query), 0644); err != nil {
+		t.Fatalf("failed to write query file: %v", err)
+	}
+
+	envContent := `
+environments:
+  gql:
+    type: graphql
+    url: http://localhost:8080/graphql
+`
+	if err := os.WriteFile(filepath.Join(dir, "environments.yaml"), []byte(envContent), 0644); err != nil {
+		t.Fatalf("failed to write environments.yaml: %v", err)
+	}
+
+	jobsContent := `
+jobs:
+  - name: user-query
+    environment: gql
+    type: graphql
+    query_file: queries/user.graphql
+`
+	if err := os.WriteFile(filepath.Join(dir, "jobs", "graphql.yaml"), []byte(jobsContent), 0644); err != nil {
+		t.Fatalf("failed to write jobs file: %v", err)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	spec, err := decodeJobSpec(cfg.Jobs[0])
+	if err != nil {
+		t.Fatalf("decodeJobSpec() error = %v", err)
+	}
+	if spec.Query != query {
+		t.Errorf("Query = %q, want %q", spec.Query, query)
+	}
+
+	job := cfg.Jobs[0]
+	job.Settings = map[string]any{"query_file": "queries/missing.graphql"}
+	if errs := NewProvider().ValidateJob(job, cfg.Environments["gql"]); !strings.Contains(errs.Error(), "query_file: failed to read query_file") {
+		t.Errorf("ValidateJob() = %v, want unreadable query_file", errs)
+	}
+
+	job.Settings = map[string]any{"query": query, "query_file": "queries/user.graphql"}
+	if errs := NewProvider().ValidateJob(job, cfg.Environments["gql"]); !strings.Contains(errs.Error(), "query_file: cannot be used together with query") {
+		t.Errorf("ValidateJob() = %v, want query and query_file rejected", errs)
+	}
+
+	job.Settings = map[string]any{"query_file": "queries/user.graphql"}
+	job.Assertions.XPath = []config.XPathAssertion{{Path: "//name"}}
+	job.Assertions.CSS = []config.CSSAssertion{{Selector: "p"}}
+	errs := NewProvider().ValidateJob(job, cfg.Environments["gql"]).Error()
+	if !strings.Contains(errs, "assertions.xpath: cannot be used with graphql jobs") || !strings.Contains(errs, "assertions.css: cannot be used with graphql jobs") {
+		t.Errorf("ValidateJob() = %v, want xpath and css assertions rejected", errs)
+	}
+}
@@ -0,0 +1,325 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	httpprovider "github.com/user/jobprobe/internal/providers/http"
+)
+
+// introspectionQuery fetches everything needed to rebuild the schema.
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  fields(includeDeprecated: true) {
+    name
+    args { ...InputValue }
+    type { ...TypeRef }
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`
+
+// introspectionSchema mirrors the __schema introspection result.
+type introspectionSchema struct {
+	QueryType        *namedType               `json:"queryType"`
+	MutationType     *namedType               `json:"mutationType"`
+	SubscriptionType *namedType               `json:"subscriptionType"`
+	Types            []introspectionType      `json:"types"`
+	Directives       []introspectionDirective `json:"directives"`
+}
+
+type namedType struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Fields        []fieldDef   `json:"fields"`
+	InputFields   []inputValue `json:"inputFields"`
+	Interfaces    []typeRef    `json:"interfaces"`
+	EnumValues    []namedType  `json:"enumValues"`
+	PossibleTypes []typeRef    `json:"possibleTypes"`
+}
+
+type introspectionDirective struct {
+	Name      string       `json:"name"`
+	Locations []string     `json:"locations"`
+	Args      []inputValue `json:"args"`
+}
+
+type fieldDef struct {
+	Name string       `json:"name"`
+	Args []inputValue `json:"args"`
+	Type typeRef      `json:"type"`
+}
+
+type inputValue struct {
+	Name         string  `json:"name"`
+	Type         typeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+// String renders the type reference in SDL notation, e.g. [User!]!.
+func (t typeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType == nil {
+			return ""
+		}
+		return t.OfType.String() + "!"
+	case "LIST":
+		if t.OfType == nil {
+			return ""
+		}
+		return "[" + t.OfType.String() + "]"
+	default:
+		return t.Name
+	}
+}
+
+// builtinScalars and builtinDirectives are provided by the gqlparser prelude.
+var builtinScalars = map[string]bool{
+	"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true,
+}
+
+var builtinDirectives = map[string]bool{
+	"skip": true, "include": true, "deprecated": true, "specifiedBy": true,
+	"oneOf": true, "defer": true,
+}
+
+// fetchSchema runs an introspection query and returns the server's schema.
+func fetchSchema(ctx context.Context, client *httpprovider.Client, path string) (*ast.Schema, error) {
+	resp, err := client.Do(ctx, httpprovider.Request{
+		Method: http.MethodPost,
+		Path:   path,
+		Body:   map[string]any{"query": introspectionQuery},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("introspection request failed: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("introspection returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Schema *introspectionSchema `json:"__schema"`
+		} `json:"data"`
+		Errors []Error `json:"errors"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse introspection response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("introspection failed: %s", result.Errors[0].Message)
+	}
+
+	if result.Data.Schema == nil {
+		return nil, fmt.Errorf("introspection returned no schema (is introspection disabled?)")
+	}
+
+	return buildSchema(result.Data.Schema)
+}
+
+// buildSchema converts an introspection result into a parsed schema by way
+// of SDL, so the query can be checked with the standard validation rules.
+func buildSchema(s *introspectionSchema) (*ast.Schema, error) {
+	sdl := buildSDL(s)
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "introspection", Input: sdl})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load introspected schema: %v", err)
+	}
+	return schema, nil
+}
+
+// buildSDL renders an introspection result as schema definition language.
+func buildSDL(s *introspectionSchema) string {
+	var b strings.Builder
+
+	b.WriteString("schema {\n")
+	if s.QueryType != nil {
+		fmt.Fprintf(&b, "  query: %s\n", s.QueryType.Name)
+	}
+	if s.MutationType != nil {
+		fmt.Fprintf(&b, "  mutation: %s\n", s.MutationType.Name)
+	}
+	if s.SubscriptionType != nil {
+		fmt.Fprintf(&b, "  subscription: %s\n", s.SubscriptionType.Name)
+	}
+	b.WriteString("}\n\n")
+
+	for _, d := range s.Directives {
+		if builtinDirectives[d.Name] || len(d.Locations) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "directive @%s%s on %s\n\n", d.Name, formatArgs(d.Args), strings.Join(d.Locations, " | "))
+	}
+
+	types := append([]introspectionType(nil), s.Types...)
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+
+	for _, t := range types {
+		if strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+
+		switch t.Kind {
+		case "SCALAR":
+			if !builtinScalars[t.Name] {
+				fmt.Fprintf(&b, "scalar %s\n\n", t.Name)
+			}
+
+		case "OBJECT", "INTERFACE":
+			keyword := "type"
+			if t.Kind == "INTERFACE" {
+				keyword = "interface"
+			}
+			fmt.Fprintf(&b, "%s %s%s {\n", keyword, t.Name, formatImplements(t.Interfaces))
+			for _, f := range t.Fields {
+				fmt.Fprintf(&b, "  %s%s: %s\n", f.Name, formatArgs(f.Args), f.Type)
+			}
+			b.WriteString("}\n\n")
+
+		case "UNION":
+			members := make([]string, len(t.PossibleTypes))
+			for i, m := range t.PossibleTypes {
+				members[i] = m.Name
+			}
+			fmt.Fprintf(&b, "union %s = %s\n\n", t.Name, strings.Join(members, " | "))
+
+		case "ENUM":
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, v := range t.EnumValues {
+				fmt.Fprintf(&b, "  %s\n", v.Name)
+			}
+			b.WriteString("}\n\n")
+
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&b, "input %s {\n", t.Name)
+			for _, f := range t.InputFields {
+				fmt.Fprintf(&b, "  %s\n", formatInputValue(f))
+			}
+			b.WriteString("}\n\n")
+		}
+	}
+
+	return b.String()
+}
+
+// formatImplements renders an implements clause.
+func formatImplements(interfaces []typeRef) string {
+	if len(interfaces) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(interfaces))
+	for i, iface := range interfaces {
+		names[i] = iface.Name
+	}
+	return " implements " + strings.Join(names, " & ")
+}
+
+// formatArgs renders an argument definition list.
+func formatArgs(args []inputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = formatInputValue(a)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// formatInputValue renders a single argument or input field.
+func formatInputValue(v inputValue) string {
+	s := v.Name + ": " + v.Type.String()
+	if v.DefaultValue != nil {
+		s += " = " + *v.DefaultValue
+	}
+	return s
+}
+
+// validateQuery checks a query document against a schema and returns a
+// description of every problem found.
+func validateQuery(schema *ast.Schema, query string) []string {
+	_, errs := gqlparser.LoadQueryWithRules(schema, query, nil)
+
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Message)
+	}
+	return msgs
+}
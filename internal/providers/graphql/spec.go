@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a graphql job.
+type JobSpec struct {
+	providers.QuerySpec `yaml:",inline"`
+
+	// Method is POST (default) or GET.
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+
+	Variables     map[string]any `yaml:"variables"`
+	OperationName string         `yaml:"operation_name"`
+
+	// ValidateSchema checks the query against the introspected schema
+	// before running it.
+	ValidateSchema bool `yaml:"validate_schema"`
+}
+
+// decodeJobSpec decodes the job's settings and loads its query.
+func decodeJobSpec(job config.Job) (JobSpec, error) {
+	var spec JobSpec
+	if errs := config.DecodeJobSpec(job, &spec); len(errs) > 0 {
+		return spec, fmt.Errorf("invalid spec: %v", errs)
+	}
+
+	query, err := spec.Load(job.Dir)
+	if err != nil {
+		return spec, err
+	}
+	spec.Query = query
+
+	if spec.Method == "" {
+		spec.Method = "POST"
+	}
+	return spec, nil
+}
+
+// ValidateEnvironment validates a GraphQL environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.RequireURL(env)...)
+}
+
+// ValidateJob validates a GraphQL job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	var spec JobSpec
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, nil)...)
+	errs = append(errs, spec.QuerySpec.Validate(job, "graphql")...)
+
+	if spec.Method != "" && spec.Method != "POST" && spec.Method != "GET" {
+		errs = append(errs, config.ValidationError{
+			Field:   "method",
+			Message: fmt.Sprintf("invalid method '%s' for graphql jobs, must be POST or GET", spec.Method),
+		})
+	}
+
+	// GraphQL responses are always JSON, so XML and HTML assertions could
+	// never match.
+	if len(job.Assertions.XPath) > 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.xpath",
+			Message: "cannot be used with graphql jobs, whose responses are JSON",
+		})
+	}
+	if len(job.Assertions.CSS) > 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.css",
+			Message: "cannot be used with graphql jobs, whose responses are JSON",
+		})
+	}
+
+	errs = append(errs, config.ValidateBodyAssertions(config.Assertions{JSON: job.Assertions.JSON, Text: job.Assertions.Text}, "assertions")...)
+
+	return errs
+}
@@ -0,0 +1,33 @@
+// Package graphql provides a GraphQL endpoint checking provider.
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Response represents a GraphQL response payload.
+type Response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []Error         `json:"errors"`
+}
+
+// Error represents an entry in a GraphQL response's errors array.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// String formats the error with its path, if any.
+func (e Error) String() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+
+	parts := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		b, _ := json.Marshal(p)
+		parts[i] = strings.Trim(string(b), `"`)
+	}
+	return strings.Join(parts, ".") + ": " + e.Message
+}
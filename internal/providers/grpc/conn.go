@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// dial creates a client connection for the environment. The connection is
+// established lazily by the first call.
+func dial(env config.Environment) (*gogrpc.ClientConn, string, error) {
+	target, secure, err := parseTarget(env.URL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	creds := insecure.NewCredentials()
+	if secure {
+		tlsConfig, err := providers.NewTLSConfig(env.TLS)
+		if err != nil {
+			return nil, "", err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := gogrpc.NewClient(target, gogrpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create client: %w", err)
+	}
+	return conn, target, nil
+}
+
+// parseTarget splits a grpc:// or grpcs:// URL into host:port and whether
+// TLS is used. The port defaults to 80 or 443.
+func parseTarget(rawURL string) (string, bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	var secure bool
+	port := "80"
+	switch u.Scheme {
+	case "grpc":
+	case "grpcs":
+		secure = true
+		port = "443"
+	default:
+		return "", false, fmt.Errorf("invalid URL scheme %q, must be grpc or grpcs", u.Scheme)
+	}
+
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port), secure, nil
+}
+
+// outgoingMetadata builds the request metadata from the environment headers,
+// the job headers and the environment auth, in increasing precedence.
+func outgoingMetadata(env config.Environment, jobHeaders map[string]string) metadata.MD {
+	headers := make(map[string]string, len(env.Headers)+len(jobHeaders)+1)
+	for k, v := range env.Headers {
+		headers[k] = v
+	}
+	for k, v := range jobHeaders {
+		headers[k] = v
+	}
+
+	switch env.Auth.Type {
+	case "bearer":
+		headers["authorization"] = "Bearer " + env.Auth.Token
+	case "basic":
+		credentials := base64.StdEncoding.EncodeToString([]byte(env.Auth.Username + ":" + env.Auth.Password))
+		headers["authorization"] = "Basic " + credentials
+	case "api_key":
+		header := env.Auth.Header
+		if header == "" {
+			header = "x-api-key"
+		}
+		headers[header] = env.Auth.APIKey
+	}
+
+	return metadata.New(headers)
+}
@@ -0,0 +1,269 @@
+// Package grpc provides a gRPC provider that runs standard health checks or
+// calls unary methods discovered through server reflection.
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultTimeout is used when neither the job nor the defaults set one.
+	defaultTimeout = 30 * time.Second
+
+	// detailOutputLimit is the number of response bytes kept in result details.
+	detailOutputLimit = 4096
+)
+
+// codeNames maps status codes to their canonical names, as used in assertions.
+var codeNames = map[codes.Code]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// Provider implements the gRPC provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new gRPC provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "grpc"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Execute calls grpc.health.v1.Health/Check, or the job's method if set, and
+// checks the status code, serving status and response fields.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "grpc",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, target, err := dial(env)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+	defer conn.Close()
+	result.Details["target"] = target
+
+	ctx = metadata.NewOutgoingContext(ctx, outgoingMetadata(env, spec.Headers))
+
+	var callOpts []gogrpc.CallOption
+	if job.MaxBodySize > 0 {
+		callOpts = append(callOpts, gogrpc.MaxCallRecvMsgSize(int(job.MaxBodySize)))
+	}
+
+	marshal := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+	var resp proto.Message
+	var rpcErr error
+
+	if spec.Method == "" {
+		result.Details["method"] = healthpb.Health_Check_FullMethodName
+		p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Checking health of %s...", target))
+
+		health := new(healthpb.HealthCheckResponse)
+		rpcErr = conn.Invoke(ctx, healthpb.Health_Check_FullMethodName,
+			&healthpb.HealthCheckRequest{Service: spec.Service}, health, callOpts...)
+		resp = health
+	} else {
+		p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Resolving %s via reflection...", spec.Method))
+
+		method, err := resolveMethod(ctx, conn, spec.Method)
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("server reflection failed: %v", callError(ctx, err, timeout))), nil
+		}
+		result.Details["method"] = method.fullName
+
+		req, err := method.newRequest(spec.Body)
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("invalid request body: %v", err)), nil
+		}
+
+		p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Calling %s...", method.fullName))
+
+		out := method.newResponse()
+		rpcErr = conn.Invoke(ctx, method.fullName, req, out, callOpts...)
+		resp = out
+		marshal.Resolver = method.types
+	}
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	st := status.Convert(rpcErr)
+	code := codeName(st.Code())
+	result.Details["code"] = code
+	if st.Message() != "" {
+		result.Details["message"] = st.Message()
+	}
+
+	var failures []string
+
+	wantCode := "OK"
+	if assertions.Code != "" {
+		wantCode = strings.ToUpper(assertions.Code)
+	}
+	if code != wantCode {
+		failure := fmt.Sprintf("expected code %s, got %s", wantCode, code)
+		if st.Message() != "" {
+			failure += ": " + st.Message()
+		}
+		failures = append(failures, failure)
+	}
+
+	if rpcErr == nil {
+		body, err := marshal.Marshal(resp)
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("failed to encode response: %v", err)), nil
+		}
+		// protojson output is deliberately unstable; compact it for details.
+		var compacted bytes.Buffer
+		if json.Compact(&compacted, body) == nil {
+			body = compacted.Bytes()
+		}
+		result.Details["response"] = truncate(string(body), detailOutputLimit)
+		result.Details["response_bytes"] = len(body)
+
+		if health, ok := resp.(*healthpb.HealthCheckResponse); ok {
+			failures = append(failures, checkServingStatus(health, job.Assertions.Status)...)
+		}
+		failures = append(failures, assertion.CheckBody(body, assertion.FormatJSON, config.Assertions{JSON: job.Assertions.JSON})...)
+	}
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("Code: %s (%s)", code, result.Duration.Round(time.Millisecond)))
+
+	return result, nil
+}
+
+// checkServingStatus compares the health status with the expected one,
+// which defaults to SERVING.
+func checkServingStatus(resp *healthpb.HealthCheckResponse, expected string) []string {
+	want := "SERVING"
+	if expected != "" {
+		want = strings.ToUpper(expected)
+	}
+
+	if got := resp.GetStatus().String(); got != want {
+		return []string{fmt.Sprintf("expected serving status %s, got %s", want, got)}
+	}
+	return nil
+}
+
+// codeName returns the canonical name of a status code.
+func codeName(c codes.Code) string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return c.String()
+}
+
+// toCode converts a wire status code to a codes.Code.
+func toCode(c int32) codes.Code {
+	if c < 0 {
+		return codes.Unknown
+	}
+	return codes.Code(c)
+}
+
+// callError replaces a deadline error with a readable timeout message.
+func callError(ctx context.Context, err error, timeout time.Duration) error {
+	if ctx.Err() == context.DeadlineExceeded || status.Code(err) == codes.DeadlineExceeded {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "... (truncated)"
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,429 @@
+package grpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// startServer starts a server with the health and reflection services.
+// "orders" and the server as a whole are SERVING; "billing" is NOT_SERVING.
+func startServer(t *testing.T, opts ...gogrpc.ServerOption) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := gogrpc.NewServer(opts...)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("billing", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	return ln.Addr().String()
+}
+
+func TestExecute(t *testing.T) {
+	addr := startServer(t)
+
+	tests := []struct {
+		name       string
+		service    string
+		method     string
+		body       map[string]any
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "server serving",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "service serving",
+			service:    "orders",
+			assertions: config.Assertions{JSON: []config.JSONAssertion{{Path: "$.status", Matcher: config.Matcher{Equals: "SERVING"}}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "service not serving",
+			service:    "billing",
+			wantStatus: providers.StatusFailed,
+			wantError:  "expected serving status SERVING, got NOT_SERVING",
+		},
+		{
+			name:       "not serving expected",
+			service:    "billing",
+			assertions: config.Assertions{Status: "not_serving"},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "unknown service",
+			service:    "search",
+			wantStatus: providers.StatusFailed,
+			wantError:  "expected code OK, got NOT_FOUND: unknown service",
+		},
+		{
+			name:       "unknown service expected",
+			service:    "search",
+			assertions: config.Assertions{Settings: map[string]any{"code": "not_found"}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:   "reflected method",
+			method: "grpc.health.v1.Health/Check",
+			body:   map[string]any{"service": "billing"},
+			assertions: config.Assertions{JSON: []config.JSONAssertion{
+				{Path: "$.status", Matcher: config.Matcher{Equals: "NOT_SERVING"}},
+			}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:   "reflected method assertion fails",
+			method: "/grpc.health.v1.Health/Check",
+			body:   map[string]any{"service": "orders"},
+			assertions: config.Assertions{JSON: []config.JSONAssertion{
+				{Path: "$.status", Matcher: config.Matcher{Equals: "NOT_SERVING"}},
+			}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "$.status",
+		},
+		{
+			name:       "unknown method",
+			method:     "grpc.health.v1.Health/Ping",
+			wantStatus: providers.StatusFailed,
+			wantError:  "server reflection failed: method Ping not found in service grpc.health.v1.Health",
+		},
+		{
+			name:       "unknown reflected service",
+			method:     "acme.Orders/Get",
+			wantStatus: providers.StatusFailed,
+			wantError:  "server reflection failed:",
+		},
+		{
+			name:       "streaming method",
+			method:     "grpc.health.v1.Health/Watch",
+			wantStatus: providers.StatusFailed,
+			wantError:  "only unary methods are supported",
+		},
+		{
+			name:       "invalid body",
+			method:     "grpc.health.v1.Health/Check",
+			body:       map[string]any{"services": "orders"},
+			wantStatus: providers.StatusFailed,
+			wantError:  "invalid request body:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "health",
+				Type:       "grpc",
+				Spec:       map[string]any{"service": tt.service, "method": tt.method, "body": tt.body},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+			env := config.Environment{Type: "grpc", URL: "grpc://" + addr}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteMetadata(t *testing.T) {
+	authorize := func(ctx context.Context, req any, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if strings.Join(md.Get("authorization"), "") != "Bearer secret" || strings.Join(md.Get("x-tenant"), "") != "acme" {
+			return nil, status.Error(codes.Unauthenticated, "missing credentials")
+		}
+		return handler(ctx, req)
+	}
+	addr := startServer(t, gogrpc.UnaryInterceptor(authorize))
+
+	tests := []struct {
+		name       string
+		env        config.Environment
+		wantStatus providers.Status
+		wantCode   string
+	}{
+		{
+			name: "with credentials",
+			env: config.Environment{
+				Auth:    config.Auth{Type: "bearer", Token: "secret"},
+				Headers: map[string]string{"X-Tenant": "acme"},
+			},
+			wantStatus: providers.StatusSucceeded,
+			wantCode:   "OK",
+		},
+		{
+			name:       "without credentials",
+			env:        config.Environment{Headers: map[string]string{"X-Tenant": "acme"}},
+			wantStatus: providers.StatusFailed,
+			wantCode:   "UNAUTHENTICATED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := tt.env
+			env.Type = "grpc"
+			env.URL = "grpc://" + addr
+
+			result, err := NewProvider().Execute(context.Background(), config.Job{Name: "health", Timeout: 5 * time.Second}, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if result.Details["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s", result.Details["code"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestExecuteMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newCertificate(t, dir, "ca", nil, nil)
+	newCertificate(t, dir, "server", ca, caKey)
+	newCertificate(t, dir, "client", ca, caKey)
+
+	serverCert, err := tls.LoadX509KeyPair(filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem"))
+	if err != nil {
+		t.Fatalf("failed to load server certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	addr := startServer(t, gogrpc.Creds(creds))
+
+	tests := []struct {
+		name       string
+		tls        config.TLS
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name: "client certificate",
+			tls: config.TLS{
+				CAFile:   filepath.Join(dir, "ca.pem"),
+				CertFile: filepath.Join(dir, "client.pem"),
+				KeyFile:  filepath.Join(dir, "client-key.pem"),
+			},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "no client certificate",
+			tls:        config.TLS{CAFile: filepath.Join(dir, "ca.pem")},
+			wantStatus: providers.StatusFailed,
+			wantError:  "expected code OK, got UNAVAILABLE",
+		},
+		{
+			name:       "unknown CA",
+			tls:        config.TLS{},
+			wantStatus: providers.StatusFailed,
+			wantError:  "expected code OK, got UNAVAILABLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := config.Environment{Type: "grpc", URL: "grpcs://" + addr, TLS: tt.tls}
+
+			result, err := NewProvider().Execute(context.Background(), config.Job{Name: "health", Timeout: 5 * time.Second}, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		url        string
+		wantTarget string
+		wantSecure bool
+		wantErr    bool
+	}{
+		{url: "grpc://localhost:50051", wantTarget: "localhost:50051"},
+		{url: "grpcs://api.example.com", wantTarget: "api.example.com:443", wantSecure: true},
+		{url: "grpc://10.0.0.1", wantTarget: "10.0.0.1:80"},
+		{url: "https://api.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		target, secure, err := parseTarget(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTarget(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if target != tt.wantTarget || secure != tt.wantSecure {
+			t.Errorf("parseTarget(%q) = %q, %v, want %q, %v", tt.url, target, secure, tt.wantTarget, tt.wantSecure)
+		}
+	}
+}
+
+// newCertificate writes <name>.pem and <name>-key.pem to dir. The certificate
+// is a self-signed CA when parent is nil, otherwise a leaf for 127.0.0.1.
+func newCertificate(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	} else {
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return cert, key
+}
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid health check",
+			job:  config.Job{Settings: map[string]any{"service": "billing"}, Assertions: config.Assertions{Status: "serving"}},
+		},
+		{
+			name: "valid method",
+			job: config.Job{
+				Settings:   map[string]any{"method": "acme.Orders/Get", "body": map[string]any{"id": 1}},
+				Assertions: config.Assertions{Settings: map[string]any{"code": "not_found"}},
+			},
+		},
+		{
+			name: "invalid method",
+			job:  config.Job{Settings: map[string]any{"method": "GetOrder", "service": "orders"}},
+			want: []string{"method: invalid method 'GetOrder'", "service: cannot be used together with method"},
+		},
+		{
+			name: "body without method",
+			job: config.Job{
+				Spec:       map[string]any{"body": map[string]any{"id": 1}},
+				Assertions: config.Assertions{Settings: map[string]any{"code": "gone"}},
+			},
+			want: []string{"body: requires method", "assertions.code: invalid gRPC status code 'gone'"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "grpc", URL: "grpc://orders:50051"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	env := config.Environment{URL: "http://orders:50051", Auth: config.Auth{Type: "digest", Username: "u"}}
+	errs := NewProvider().ValidateEnvironment(env)
+	for _, want := range []string{"url: must start with grpc:// or grpcs://", "auth.type: grpc environments support bearer, basic or api_key auth"} {
+		if !strings.Contains(errs.Error(), want) {
+			t.Errorf("expected %q in %v", want, errs)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	gogrpc "google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// unaryMethod is a unary method resolved through server reflection.
+type unaryMethod struct {
+	fullName string
+	desc     protoreflect.MethodDescriptor
+	types    *dynamicpb.Types
+}
+
+// resolveMethod looks up a package.Service/Method through the server
+// reflection service and returns its descriptor.
+func resolveMethod(ctx context.Context, conn gogrpc.ClientConnInterface, name string) (*unaryMethod, error) {
+	serviceName, methodName, _ := strings.Cut(strings.TrimPrefix(name, "/"), "/")
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	files, err := fetchFiles(stream, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found", serviceName)
+	}
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", serviceName)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, fmt.Errorf("method %s is streaming, only unary methods are supported", methodName)
+	}
+
+	return &unaryMethod{
+		fullName: "/" + serviceName + "/" + methodName,
+		desc:     method,
+		types:    dynamicpb.NewTypes(files),
+	}, nil
+}
+
+// fetchFiles downloads the file defining symbol and all of its dependencies.
+// Dependencies compiled into this binary, such as the well-known types, are
+// not requested from the server.
+func fetchFiles(stream reflectionpb.ServerReflection_ServerReflectionInfoClient, symbol string) (*protoregistry.Files, error) {
+	pending, err := requestFiles(stream, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: symbol},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	queued := make(map[string]bool)
+	for _, fd := range pending {
+		queued[fd.GetName()] = true
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	for len(pending) > 0 {
+		fd := pending[0]
+		pending = pending[1:]
+		set.File = append(set.File, fd)
+
+		for _, dep := range fd.GetDependency() {
+			if queued[dep] {
+				continue
+			}
+			queued[dep] = true
+
+			if local, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+				pending = append(pending, protodesc.ToFileDescriptorProto(local))
+				continue
+			}
+
+			deps, err := requestFiles(stream, &reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range deps {
+				if d.GetName() == dep || !queued[d.GetName()] {
+					queued[d.GetName()] = true
+					pending = append(pending, d)
+				}
+			}
+		}
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptors from server: %w", err)
+	}
+	return files, nil
+}
+
+// requestFiles sends one reflection request and decodes the returned files.
+func requestFiles(stream reflectionpb.ServerReflection_ServerReflectionInfoClient, req *reflectionpb.ServerReflectionRequest) ([]*descriptorpb.FileDescriptorProto, error) {
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, status.Error(toCode(errResp.GetErrorCode()), errResp.GetErrorMessage())
+	}
+
+	var files []*descriptorpb.FileDescriptorProto
+	for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := new(descriptorpb.FileDescriptorProto)
+		if err := proto.Unmarshal(raw, fd); err != nil {
+			return nil, fmt.Errorf("invalid file descriptor: %w", err)
+		}
+		files = append(files, fd)
+	}
+	return files, nil
+}
+
+// newRequest builds the request message from a JSON-compatible body.
+func (m *unaryMethod) newRequest(body map[string]any) (*dynamicpb.Message, error) {
+	req := dynamicpb.NewMessage(m.desc.Input())
+	if len(body) == 0 {
+		return req, nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	if err := (protojson.UnmarshalOptions{Resolver: m.types}).Unmarshal(data, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// newResponse returns an empty response message.
+func (m *unaryMethod) newResponse() *dynamicpb.Message {
+	return dynamicpb.NewMessage(m.desc.Output())
+}
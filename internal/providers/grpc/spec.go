@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a grpc job.
+type JobSpec struct {
+	// Service is the gRPC health service name to check. Method, when set,
+	// replaces the health check.
+	Service string `yaml:"service"`
+
+	// Method is the unary method to call, as package.Service/Method.
+	Method string `yaml:"method"`
+
+	// Headers are sent as request metadata.
+	Headers map[string]string `yaml:"headers"`
+
+	// Body is the request message in its JSON form.
+	Body map[string]any `yaml:"body"`
+}
+
+// Assertions holds the gRPC-specific assertions of a job.
+type Assertions struct {
+	// Code is the expected status code name. Empty expects OK.
+	Code string `yaml:"code"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// validServingStatuses are the health check statuses that may be asserted.
+var validServingStatuses = map[string]bool{
+	"":                true,
+	"serving":         true,
+	"not_serving":     true,
+	"service_unknown": true,
+}
+
+// validCodes are the status code names that may be asserted.
+var validCodes = func() map[string]bool {
+	names := make(map[string]bool, len(codeNames))
+	for _, name := range codeNames {
+		names[name] = true
+	}
+	return names
+}()
+
+// ValidateEnvironment validates a gRPC environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	errs = append(errs, providers.RequireURL(env)...)
+	errs = append(errs, providers.ValidateURLScheme(env, "grpc://", "grpcs://")...)
+	return append(errs, providers.ValidateAuthType(env, "grpc", "bearer", "basic", "api_key")...)
+}
+
+// ValidateJob validates a gRPC job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.Method != "" {
+		service, method, ok := strings.Cut(strings.TrimPrefix(spec.Method, "/"), "/")
+		if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+			errs = append(errs, config.ValidationError{
+				Field:   "method",
+				Message: fmt.Sprintf("invalid method '%s', must be package.Service/Method", spec.Method),
+			})
+		}
+		if spec.Service != "" {
+			errs = append(errs, config.ValidationError{
+				Field:   "service",
+				Message: "cannot be used together with method",
+			})
+		}
+		if job.Assertions.Status != "" {
+			errs = append(errs, config.ValidationError{
+				Field:   "assertions.status",
+				Message: "only applies to health checks (no method)",
+			})
+		}
+	} else if len(spec.Body) > 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "body",
+			Message: "requires method",
+		})
+	}
+
+	if !validServingStatuses[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid serving status '%s', must be one of: serving, not_serving, service_unknown", job.Assertions.Status),
+		})
+	}
+
+	if assertions.Code != "" && !validCodes[strings.ToUpper(assertions.Code)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.code",
+			Message: fmt.Sprintf("invalid gRPC status code '%s'", assertions.Code),
+		})
+	}
+
+	return append(errs, config.ValidateBodyAssertions(config.Assertions{JSON: job.Assertions.JSON}, "assertions")...)
+}
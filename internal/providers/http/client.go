@@ -4,13 +4,16 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"time"
 
 	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
 )
 
 // Client is an HTTP client for health checks.
@@ -21,15 +24,19 @@ type Client struct {
 	httpClient *http.Client
 }
 
-// NewClient creates a new HTTP client.
-func NewClient(env config.Environment) *Client {
+// NewClient creates a new HTTP client. If httpClient is nil, a dedicated
+// client is created; otherwise it is shared, and should come from a
+// providers.ClientPool, which handles digest auth for the environment.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = providers.NewEnvironmentClient(env)
+	}
+
 	return &Client{
-		baseURL: env.URL,
-		auth:    env.Auth,
-		headers: env.Headers,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:    env.URL,
+		auth:       env.Auth,
+		headers:    env.Headers,
+		httpClient: httpClient,
 	}
 }
 
@@ -39,22 +46,102 @@ type Response struct {
 	Headers    http.Header
 	Body       []byte
 	Duration   time.Duration
+	Timing     Timing
+
+	// BodyBytes is the size of the response body, including a body that was
+	// discarded rather than buffered.
+	BodyBytes int64
+}
+
+// Timing represents connection-level timings of an HTTP request.
+type Timing struct {
+	ConnReused   bool
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+}
+
+// Details returns the timing as a map suitable for Result.Details.
+func (t Timing) Details() map[string]interface{} {
+	return map[string]interface{}{
+		"connection_reused": t.ConnReused,
+		"dns_ms":            t.DNS.Milliseconds(),
+		"connect_ms":        t.Connect.Milliseconds(),
+		"tls_handshake_ms":  t.TLSHandshake.Milliseconds(),
+		"first_byte_ms":     t.FirstByte.Milliseconds(),
+	}
+}
+
+// newTimingTrace returns a ClientTrace that records into t.
+func newTimingTrace(t *Timing, start time.Time) *httptrace.ClientTrace {
+	var dnsStart, connectStart, tlsStart time.Time
+
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			if !dnsStart.IsZero() {
+				t.DNS = time.Since(dnsStart)
+			}
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(string, string, error) {
+			if !connectStart.IsZero() {
+				t.Connect = time.Since(connectStart)
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			if !tlsStart.IsZero() {
+				t.TLSHandshake = time.Since(tlsStart)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.ConnReused = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			t.FirstByte = time.Since(start)
+		},
+	}
+}
+
+// Request represents an HTTP request to send.
+type Request struct {
+	Method  string
+	Path    string
+	Headers map[string]string
+	Body    map[string]any
+
+	// MaxBodySize limits how many response bytes are read. Zero means no limit.
+	MaxBodySize int64
+
+	// DiscardBody drains the response without buffering it.
+	DiscardBody bool
+}
+
+// BodyTooLargeError is returned when a response body exceeds the limit.
+type BodyTooLargeError struct {
+	Limit config.ByteSize
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds max_body_size of %s", e.Limit)
 }
 
 // Do executes an HTTP request.
-func (c *Client) Do(ctx context.Context, method, path string, headers map[string]string, body map[string]any) (*Response, error) {
-	url := c.baseURL + path
+func (c *Client) Do(ctx context.Context, r Request) (*Response, error) {
+	url := c.baseURL + r.Path
 
 	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
+	if r.Body != nil {
+		jsonData, err := json.Marshal(r.Body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal body: %w", err)
 		}
 		reqBody = bytes.NewReader(jsonData)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, r.Method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -63,17 +150,20 @@ func (c *Client) Do(ctx context.Context, method, path string, headers map[string
 		req.Header.Set(k, v)
 	}
 
-	for k, v := range headers {
+	for k, v := range r.Headers {
 		req.Header.Set(k, v)
 	}
 
-	c.applyAuth(req)
+	providers.ApplyAuth(req, c.auth)
 
-	if body != nil && req.Header.Get("Content-Type") == "" {
+	if r.Body != nil && req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	var timing Timing
 	start := time.Now()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), newTimingTrace(&timing, start)))
+
 	resp, err := c.httpClient.Do(req)
 	duration := time.Since(start)
 
@@ -82,9 +172,14 @@ func (c *Client) Do(ctx context.Context, method, path string, headers map[string
 	}
 	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, n, err := readBody(resp.Body, r.MaxBodySize, r.DiscardBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
+	}
+	// A body that was not read in full, as for HEAD or a discarded body
+	// over the limit, is sized by Content-Length when the server sent one.
+	if (n == 0 || (r.MaxBodySize > 0 && n > r.MaxBodySize)) && resp.ContentLength > 0 {
+		n = resp.ContentLength
 	}
 
 	return &Response{
@@ -92,21 +187,35 @@ func (c *Client) Do(ctx context.Context, method, path string, headers map[string
 		Headers:    resp.Header,
 		Body:       respBody,
 		Duration:   duration,
+		Timing:     timing,
+		BodyBytes:  n,
 	}, nil
 }
 
-// applyAuth applies authentication to the request.
-func (c *Client) applyAuth(req *http.Request) {
-	switch c.auth.Type {
-	case "bearer":
-		req.Header.Set("Authorization", "Bearer "+c.auth.Token)
-	case "basic":
-		req.SetBasicAuth(c.auth.Username, c.auth.Password)
-	case "api_key":
-		header := c.auth.Header
-		if header == "" {
-			header = "X-API-Key"
+// readBody reads at most limit bytes of body. If discard is set the body is
+// drained (up to limit) so the connection can be reused, but not buffered;
+// an oversized body is then not an error since nobody looks at it. It also
+// returns the number of bytes read.
+func readBody(body io.Reader, limit int64, discard bool) ([]byte, int64, error) {
+	if limit > 0 {
+		body = io.LimitReader(body, limit+1)
+	}
+
+	if discard {
+		n, err := io.Copy(io.Discard, body)
+		if err != nil {
+			return nil, n, fmt.Errorf("failed to read response body: %w", err)
 		}
-		req.Header.Set(header, c.auth.APIKey)
+		return nil, n, nil
 	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, int64(len(data)), fmt.Errorf("failed to read response body: %w", err)
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return nil, int64(len(data)), &BodyTooLargeError{Limit: config.ByteSize(limit)}
+	}
+
+	return data, int64(len(data)), nil
 }
@@ -0,0 +1,144 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+func TestClientPoolReusesConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "http", URL: server.URL}
+	pool := providers.NewClientPool()
+	defer pool.Close()
+
+	first, err := NewClient(env, pool.Client("api", env)).Do(context.Background(), Request{Method: "GET", Path: "/health"})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if first.Timing.ConnReused {
+		t.Error("first request reported a reused connection")
+	}
+
+	second, err := NewClient(env, pool.Client("api", env)).Do(context.Background(), Request{Method: "GET", Path: "/health"})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if !second.Timing.ConnReused {
+		t.Error("second request did not reuse the pooled connection")
+	}
+}
+
+func TestClientWithoutPoolDoesNotShare(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "http", URL: server.URL}
+
+	if _, err := NewClient(env, nil).Do(context.Background(), Request{Method: "GET", Path: "/"}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	resp, err := NewClient(env, nil).Do(context.Background(), Request{Method: "GET", Path: "/"})
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.Timing.ConnReused {
+		t.Error("unpooled clients unexpectedly shared a connection")
+	}
+}
+
+func TestClientMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, 2048))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.Environment{Type: "http", URL: server.URL}, nil)
+
+	t.Run("within limit", func(t *testing.T) {
+		resp, err := client.Do(context.Background(), Request{Method: "GET", Path: "/", MaxBodySize: 2048})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if len(resp.Body) != 2048 {
+			t.Errorf("len(Body) = %d, want 2048", len(resp.Body))
+		}
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		_, err := client.Do(context.Background(), Request{Method: "GET", Path: "/", MaxBodySize: 1024})
+		var tooLarge *BodyTooLargeError
+		if !errors.As(err, &tooLarge) {
+			t.Fatalf("Do() error = %v, want BodyTooLargeError", err)
+		}
+		if err.Error() != "response body exceeds max_body_size of 1KB" {
+			t.Errorf("error = %q", err.Error())
+		}
+	})
+
+	t.Run("discard body", func(t *testing.T) {
+		resp, err := client.Do(context.Background(), Request{Method: "GET", Path: "/", MaxBodySize: 1024, DiscardBody: true})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if resp.Body != nil {
+			t.Errorf("Body = %d bytes, want nil", len(resp.Body))
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+		}
+		if resp.BodyBytes != 2048 {
+			t.Errorf("BodyBytes = %d, want 2048", resp.BodyBytes)
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		resp, err := client.Do(context.Background(), Request{Method: "HEAD", Path: "/", DiscardBody: true})
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if resp.BodyBytes != 2048 {
+			t.Errorf("BodyBytes = %d, want 2048", resp.BodyBytes)
+		}
+	})
+}
+
+func TestDigestChallengeSharedAcrossJobs(t *testing.T) {
+	challenges := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			challenges++
+			w.Header().Set("WWW-Authenticate", `Digest realm="api", nonce="abc", qop="auth"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "http", URL: server.URL, Auth: config.Auth{Type: "digest", Username: "admin", Password: "secret"}}
+	pool := providers.NewClientPool()
+	defer pool.Close()
+
+	for i := 0; i < 3; i++ {
+		resp, err := NewClient(env, pool.Client("api", env)).Do(context.Background(), Request{Method: "GET", Path: "/health"})
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("job %d: Do() = %v, %v", i, resp, err)
+		}
+	}
+	if challenges != 1 {
+		t.Errorf("challenges = %d, want 1 for all jobs of the environment", challenges)
+	}
+}
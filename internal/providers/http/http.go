@@ -2,11 +2,11 @@ package http
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/user/jobprobe/internal/assertion"
 	"github.com/user/jobprobe/internal/config"
 	"github.com/user/jobprobe/internal/providers"
 )
@@ -14,6 +14,7 @@ import (
 // Provider implements the HTTP endpoint checking provider.
 type Provider struct {
 	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
 }
 
 // NewProvider creates a new HTTP provider.
@@ -31,6 +32,16 @@ func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
 	p.onProgress = cb
 }
 
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Capabilities describes what the HTTP provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{DryRun: true}
+}
+
 // Execute executes an HTTP health check and returns the result.
 func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
 	result := &providers.Result{
@@ -42,22 +53,32 @@ func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Envir
 		Details:     make(map[string]interface{}),
 	}
 
-	client := NewClient(env)
+	spec, errs := decodeJobSpec(job)
+	if len(errs) > 0 {
+		return fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
 
-	p.reportProgress(job.Name, providers.StatusRunning,
-		fmt.Sprintf("%s %s%s", job.Method, env.URL, job.Path))
+	client := NewClient(env, p.clients.Client(job.Environment, env))
 
-	resp, err := client.Do(ctx, job.Method, job.Path, job.Headers, job.Body)
+	p.reportProgress(job.Name, providers.StatusRunning,
+		fmt.Sprintf("%s %s%s", spec.Method, env.URL, spec.Path))
+
+	resp, err := client.Do(ctx, Request{
+		Method:      spec.Method,
+		Path:        spec.Path,
+		Headers:     spec.Headers,
+		Body:        spec.Body,
+		MaxBodySize: int64(job.MaxBodySize),
+		DiscardBody: spec.DiscardBody || spec.Method == "HEAD",
+	})
 	if err != nil {
-		result.Status = providers.StatusFailed
-		result.Error = err.Error()
-		result.FinishedAt = time.Now()
-		result.Duration = result.FinishedAt.Sub(result.StartedAt)
-		return result, nil
+		return fail(result, err.Error()), nil
 	}
 
 	result.Details["status_code"] = resp.StatusCode
 	result.Details["duration_ms"] = resp.Duration.Milliseconds()
+	result.Details["timing"] = resp.Timing.Details()
+	result.Details["body_bytes"] = resp.BodyBytes
 	result.FinishedAt = time.Now()
 	result.Duration = resp.Duration
 
@@ -73,10 +94,11 @@ func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Envir
 			resp.Duration, job.Assertions.MaxDuration))
 	}
 
-	if len(job.Assertions.JSON) > 0 {
-		jsonErrors := p.checkJSONAssertions(resp.Body, job.Assertions.JSON)
-		errors = append(errors, jsonErrors...)
+	format := spec.ResponseType
+	if format == "" {
+		format = assertion.DetectFormat(resp.Headers.Get("Content-Type"))
 	}
+	errors = append(errors, assertion.CheckBody(resp.Body, format, job.Assertions)...)
 
 	if len(errors) > 0 {
 		result.Status = providers.StatusFailed
@@ -91,86 +113,13 @@ func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Envir
 	return result, nil
 }
 
-// checkJSONAssertions checks JSON path assertions against a response body.
-func (p *Provider) checkJSONAssertions(body []byte, assertions []config.JSONAssertion) []string {
-	var errors []string
-
-	var data interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		return []string{fmt.Sprintf("failed to parse JSON response: %v", err)}
-	}
-
-	for _, assertion := range assertions {
-		value, err := getJSONPath(data, assertion.Path)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("JSON path %s: %v", assertion.Path, err))
-			continue
-		}
-
-		if !compareValues(value, assertion.Equals) {
-			errors = append(errors, fmt.Sprintf("JSON path %s: expected %v, got %v",
-				assertion.Path, assertion.Equals, value))
-		}
-	}
-
-	return errors
-}
-
-// getJSONPath extracts a value from JSON data using a simple path notation.
-// Supports paths like $.status, $.data.name, $.items[0].id
-func getJSONPath(data interface{}, path string) (interface{}, error) {
-	if !strings.HasPrefix(path, "$.") {
-		return nil, fmt.Errorf("path must start with $.")
-	}
-
-	path = strings.TrimPrefix(path, "$.")
-	parts := strings.Split(path, ".")
-
-	current := data
-	for _, part := range parts {
-		if part == "" {
-			continue
-		}
-
-		switch v := current.(type) {
-		case map[string]interface{}:
-			var ok bool
-			current, ok = v[part]
-			if !ok {
-				return nil, fmt.Errorf("key '%s' not found", part)
-			}
-		case []interface{}:
-			return nil, fmt.Errorf("array indexing not yet supported for '%s'", part)
-		default:
-			return nil, fmt.Errorf("cannot traverse '%s' in non-object type", part)
-		}
-	}
-
-	return current, nil
-}
-
-// compareValues compares two values for equality.
-func compareValues(actual, expected interface{}) bool {
-	switch exp := expected.(type) {
-	case string:
-		if act, ok := actual.(string); ok {
-			return act == exp
-		}
-	case bool:
-		if act, ok := actual.(bool); ok {
-			return act == exp
-		}
-	case int:
-		if act, ok := actual.(float64); ok {
-			return int(act) == exp
-		}
-	case float64:
-		if act, ok := actual.(float64); ok {
-			return act == exp
-		}
-	}
-
-	return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", expected)
+// fail marks the result failed with message.
+func fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
 }
 
 // reportProgress reports progress if a callback is set.
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// defaultPorts are the ports used when the environment URL has none.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Preflight resolves the endpoint's host and opens a TCP connection to it
+// without sending the request. It connects directly, ignoring any proxy.
+func (p *Provider) Preflight(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "http",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	target, err := url.Parse(env.URL)
+	if err != nil || target.Hostname() == "" {
+		return p.failPreflight(result, fmt.Sprintf("invalid url %q", env.URL)), nil
+	}
+	host := target.Hostname()
+	port := target.Port()
+	if port == "" {
+		port = defaultPorts[strings.ToLower(target.Scheme)]
+	}
+	result.Details["host"] = host
+	result.Details["port"] = port
+
+	// Resolving and connecting should take a fraction of what the job's
+	// timeout allows for a full response, so cap it at the request timeout.
+	timeout := providers.DefaultRequestTimeout
+	if job.Timeout > 0 {
+		timeout = min(job.Timeout, timeout)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Resolving %s", host))
+
+	start := time.Now()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return p.failPreflight(result, fmt.Sprintf("failed to resolve %s: %v", host, err)), nil
+	}
+	result.Details["addresses"] = addrs
+	result.Details["dns_ms"] = time.Since(start).Milliseconds()
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Connecting to %s", net.JoinHostPort(host, port)))
+
+	var dialer net.Dialer
+	var lastErr error
+	start = time.Now()
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(addr, port))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		result.Details["connected"] = conn.RemoteAddr().String()
+		result.Details["connect_ms"] = time.Since(start).Milliseconds()
+		conn.Close()
+
+		result.Status = providers.StatusPreflightPassed
+		result.FinishedAt = time.Now()
+		result.Duration = result.FinishedAt.Sub(result.StartedAt)
+		return result, nil
+	}
+
+	return p.failPreflight(result, fmt.Sprintf("failed to connect to %s: %v", net.JoinHostPort(host, port), lastErr)), nil
+}
+
+// failPreflight marks a preflight result as failed with the given message.
+func (p *Provider) failPreflight(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+func TestPreflight(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	// A port that was just released refuses connections.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedURL := "http://" + listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "reachable",
+			url:        server.URL,
+			wantStatus: providers.StatusPreflightPassed,
+		},
+		{
+			name:       "connection refused",
+			url:        closedURL,
+			wantStatus: providers.StatusFailed,
+			wantError:  "failed to connect to 127.0.0.1:",
+		},
+		{
+			name:       "unresolvable host",
+			url:        "http://jprobe-preflight.invalid",
+			wantStatus: providers.StatusFailed,
+			wantError:  "failed to resolve jprobe-preflight.invalid",
+		},
+		{
+			name:       "invalid url",
+			url:        "://",
+			wantStatus: providers.StatusFailed,
+			wantError:  "invalid url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{Name: tt.name, Type: "http", Spec: map[string]any{"method": "POST", "path": "/deploy"}, Timeout: 5 * time.Second}
+			result, err := NewProvider().Preflight(context.Background(), job, config.Environment{Type: "http", URL: tt.url})
+			if err != nil {
+				t.Fatalf("Preflight() error = %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+
+	if n := requests.Load(); n != 0 {
+		t.Errorf("preflight sent %d requests, want none", n)
+	}
+}
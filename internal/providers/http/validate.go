@@ -0,0 +1,109 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of an http job.
+type JobSpec struct {
+	Method  string            `yaml:"method"`
+	Path    string            `yaml:"path"`
+	Headers map[string]string `yaml:"headers"`
+	Body    map[string]any    `yaml:"body"`
+
+	// DiscardBody skips reading the response body beyond what is needed.
+	DiscardBody bool `yaml:"discard_body"`
+
+	// ResponseType selects how the body is parsed for assertions: json,
+	// xml, html or text. Empty uses the Content-Type header.
+	ResponseType string `yaml:"response_type"`
+}
+
+// decodeJobSpec decodes the job's settings.
+func decodeJobSpec(job config.Job) (JobSpec, config.ValidationErrors) {
+	var spec JobSpec
+	errs := config.DecodeJobSpec(job, &spec)
+	return spec, errs
+}
+
+// validMethods are the request methods an http job may use.
+var validMethods = map[string]bool{
+	"GET":     true,
+	"POST":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"PATCH":   true,
+	"HEAD":    true,
+	"OPTIONS": true,
+}
+
+// validResponseTypes are the ways a response body may be parsed for
+// assertions. Empty selects the type from the Content-Type header.
+var validResponseTypes = map[string]bool{
+	"":     true,
+	"json": true,
+	"xml":  true,
+	"html": true,
+	"text": true,
+}
+
+// ValidateEnvironment validates an HTTP environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.RequireURL(env)...)
+}
+
+// ValidateJob validates an HTTP job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, errs := decodeJobSpec(job)
+	errs = append(errs, config.DecodeAssertions(job, nil)...)
+
+	if spec.Method == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "method",
+			Message: "is required for http jobs",
+		})
+	}
+
+	if spec.Method != "" && !validMethods[spec.Method] {
+		errs = append(errs, config.ValidationError{
+			Field:   "method",
+			Message: fmt.Sprintf("invalid method '%s'", spec.Method),
+		})
+	}
+
+	if spec.Path == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "path",
+			Message: "is required for http jobs",
+		})
+	}
+
+	if job.MaxBodySize < 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "max_body_size",
+			Message: "must not be negative",
+		})
+	}
+
+	if spec.DiscardBody && job.Assertions.HasBodyAssertions() {
+		errs = append(errs, config.ValidationError{
+			Field:   "discard_body",
+			Message: "cannot be used with body assertions",
+		})
+	}
+
+	if !validResponseTypes[spec.ResponseType] {
+		errs = append(errs, config.ValidationError{
+			Field:   "response_type",
+			Message: fmt.Sprintf("invalid response type '%s', must be one of: json, xml, html, text", spec.ResponseType),
+		})
+	}
+
+	errs = append(errs, config.ValidateBodyAssertions(job.Assertions, "assertions")...)
+
+	return errs
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid",
+			job:  config.Job{Spec: map[string]any{"method": "GET", "path": "/health"}},
+		},
+		{
+			name: "missing method and path",
+			job:  config.Job{},
+			want: []string{"method: is required for http jobs", "path: is required for http jobs"},
+		},
+		{
+			name: "invalid method",
+			job:  config.Job{Spec: map[string]any{"method": "FETCH", "path": "/"}},
+			want: []string{"method: invalid method 'FETCH'"},
+		},
+		{
+			name: "discarded body with assertions",
+			job: config.Job{
+				Spec:       map[string]any{"method": "GET", "path": "/", "discard_body": true},
+				Assertions: config.Assertions{JSON: []config.JSONAssertion{{Path: "status"}}},
+			},
+			want: []string{"discard_body: cannot be used with body assertions", "assertions.json[0].path: must start with $."},
+		},
+		{
+			name: "invalid response type",
+			job:  config.Job{Spec: map[string]any{"method": "GET", "path": "/", "response_type": "yaml"}},
+			want: []string{"response_type: invalid response type 'yaml'"},
+		},
+		{
+			name: "top-level settings",
+			job: config.Job{
+				Settings:   map[string]any{"method": "GET", "path": "/", "timout": "5s"},
+				Assertions: config.Assertions{Settings: map[string]any{"exit_code": 0}},
+			},
+			want: []string{"timout: unknown field", "assertions.exit_code: unknown field"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{URL: "http://localhost"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package jenkins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+var queueItemPattern = regexp.MustCompile(`/queue/item/(\d+)/?$`)
+
+// Client is a Jenkins API client.
+type Client struct {
+	baseURL    string
+	username   string
+	token      string
+	headers    map[string]string
+	httpClient *http.Client
+	crumb      *Crumb
+}
+
+// NewClient creates a new Jenkins client. If httpClient is nil, a dedicated
+// client is created; otherwise its transport is shared. Each client keeps its
+// own cookie jar because Jenkins ties CSRF crumbs to the session.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = providers.NewHTTPClient(env.Transport)
+	}
+
+	jar, _ := cookiejar.New(nil)
+
+	token := env.Auth.Token
+	if token == "" {
+		token = env.Auth.Password
+	}
+
+	return &Client{
+		baseURL:  strings.TrimSuffix(env.URL, "/"),
+		username: env.Auth.Username,
+		token:    token,
+		headers:  env.Headers,
+		httpClient: &http.Client{
+			Transport: httpClient.Transport,
+			Timeout:   httpClient.Timeout,
+			Jar:       jar,
+		},
+	}
+}
+
+// JobURL returns the URL of a job given its full name, e.g. "team/deploy/nightly".
+func (c *Client) JobURL(fullName string) string {
+	var b strings.Builder
+	b.WriteString(c.baseURL)
+	for _, part := range strings.Split(strings.Trim(fullName, "/"), "/") {
+		b.WriteString("/job/")
+		b.WriteString(url.PathEscape(part))
+	}
+	return b.String()
+}
+
+// FetchCrumb retrieves a CSRF crumb. It is a no-op if CSRF protection is
+// disabled on the server.
+func (c *Client) FetchCrumb(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodGet, c.baseURL+"/crumbIssuer/api/json", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	var crumb Crumb
+	if err := json.NewDecoder(resp.Body).Decode(&crumb); err != nil {
+		return fmt.Errorf("failed to decode crumb: %w", err)
+	}
+
+	c.crumb = &crumb
+	return nil
+}
+
+// TriggerBuild queues a build of the job and returns the queue item ID.
+func (c *Client) TriggerBuild(ctx context.Context, fullName string, params map[string]string) (int, error) {
+	endpoint := c.JobURL(fullName) + "/build"
+	var body io.Reader
+	if len(params) > 0 {
+		endpoint = c.JobURL(fullName) + "/buildWithParameters"
+		form := url.Values{}
+		for k, v := range params {
+			form.Set(k, v)
+		}
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return 0, c.parseError(resp)
+	}
+
+	location := resp.Header.Get("Location")
+	match := queueItemPattern.FindStringSubmatch(location)
+	if match == nil {
+		return 0, fmt.Errorf("jenkins did not return a queue item location (got %q)", location)
+	}
+
+	id, _ := strconv.Atoi(match[1])
+	return id, nil
+}
+
+// StopBuild aborts a running build.
+func (c *Client) StopBuild(ctx context.Context, fullName string, number int) error {
+	return c.post(ctx, fmt.Sprintf("%s/%d/stop", c.JobURL(fullName), number))
+}
+
+// CancelQueueItem removes a build that has not started from the queue.
+func (c *Client) CancelQueueItem(ctx context.Context, id int) error {
+	return c.post(ctx, fmt.Sprintf("%s/queue/cancelItem?id=%d", c.baseURL, id))
+}
+
+// post sends an empty POST request. Jenkins answers some actions with a
+// redirect, so any status below 400 is success.
+func (c *Client) post(ctx context.Context, endpoint string) error {
+	req, err := c.newRequest(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return c.parseError(resp)
+	}
+	return nil
+}
+
+// GetQueueItem retrieves a queue item.
+func (c *Client) GetQueueItem(ctx context.Context, id int) (*QueueItem, error) {
+	var item QueueItem
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/queue/item/%d/api/json", c.baseURL, id), &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetBuild retrieves a build of the job.
+func (c *Client) GetBuild(ctx context.Context, fullName string, number int) (*Build, error) {
+	var build Build
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/%d/api/json", c.JobURL(fullName), number), &build); err != nil {
+		return nil, err
+	}
+	return &build, nil
+}
+
+// ConsoleTail returns the last n lines of a build's console log. The log is
+// streamed so that only n lines are held in memory.
+func (c *Client) ConsoleTail(ctx context.Context, fullName string, number, n int) ([]string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("%s/%d/consoleText", c.JobURL(fullName), number), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	return tailLines(resp.Body, n)
+}
+
+// getJSON performs a GET request and decodes the JSON response into v.
+func (c *Client) getJSON(ctx context.Context, endpoint string, v any) error {
+	req, err := c.newRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// newRequest creates a request with authentication, headers and the crumb.
+func (c *Client) newRequest(ctx context.Context, method, endpoint string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.token)
+	}
+
+	if c.crumb != nil && method != http.MethodGet {
+		req.Header.Set(c.crumb.CrumbRequestField, c.crumb.Crumb)
+	}
+
+	return req, nil
+}
+
+// parseError builds an error from a non-successful Jenkins response.
+func (c *Client) parseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("jenkins authentication failed (status 401), check username and API token")
+	case http.StatusForbidden:
+		return fmt.Errorf("jenkins denied the request (status 403), check permissions or CSRF crumb")
+	case http.StatusNotFound:
+		return fmt.Errorf("jenkins resource not found: %s", resp.Request.URL.Path)
+	}
+
+	return fmt.Errorf("jenkins request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// tailLines returns the last n lines read from r.
+func tailLines(r io.Reader, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	ring := make([]string, 0, n)
+	start := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(ring) < n {
+			ring = append(ring, scanner.Text())
+			continue
+		}
+		ring[start] = scanner.Text()
+		start = (start + 1) % n
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read console log: %w", err)
+	}
+
+	lines := make([]string, 0, len(ring))
+	lines = append(lines, ring[start:]...)
+	lines = append(lines, ring[:start]...)
+	return lines, nil
+}
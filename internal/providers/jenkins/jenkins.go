@@ -0,0 +1,282 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// defaultLogTailLines is the number of console lines captured on failure.
+const defaultLogTailLines = 50
+
+// Provider implements the Jenkins job execution provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+}
+
+// NewProvider creates a new Jenkins provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "jenkins"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Capabilities describes what the Jenkins provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Polling: true, Abort: true, Logs: true}
+}
+
+// Abort stops the build behind the result if it had not finished when
+// Execute returned, or cancels its queue item if it had not started.
+func (p *Provider) Abort(ctx context.Context, job config.Job, env config.Environment, result *providers.Result) (bool, error) {
+	number, started := result.Details["build_number"].(int)
+	queueID, queued := result.Details["queue_id"].(int)
+	if !result.RemoteRunning || (!started && !queued) {
+		return false, nil
+	}
+
+	spec, errs := decodeJobSpec(job)
+	if len(errs) > 0 {
+		return false, fmt.Errorf("invalid spec: %v", errs)
+	}
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+	if err := client.FetchCrumb(ctx); err != nil {
+		return false, fmt.Errorf("failed to fetch CSRF crumb: %w", err)
+	}
+
+	if started {
+		if err := client.StopBuild(ctx, spec.JobID, number); err != nil {
+			return false, fmt.Errorf("failed to stop build #%d: %w", number, err)
+		}
+		return true, nil
+	}
+	if err := client.CancelQueueItem(ctx, queueID); err != nil {
+		return false, fmt.Errorf("failed to cancel queue item #%d: %w", queueID, err)
+	}
+	return true, nil
+}
+
+// Execute triggers a Jenkins build, waits for it to finish and returns the result.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "jenkins",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, errs := decodeJobSpec(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	timeout := job.Timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+
+	if err := client.FetchCrumb(ctx); err != nil {
+		return p.fail(result, fmt.Sprintf("failed to fetch CSRF crumb: %v", err)), nil
+	}
+
+	p.reportProgress(job.Name, providers.StatusPending, "Triggering build...")
+
+	queueID, err := client.TriggerBuild(ctx, spec.JobID, spec.Options)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to trigger build: %v", err)), nil
+	}
+	result.Details["queue_id"] = queueID
+	result.RemoteRunning = true
+
+	pollInterval := job.PollInterval
+
+	number, err := p.waitForBuildNumber(ctx, client, queueID, job.Name, pollInterval)
+	if err != nil {
+		return p.fail(result, timeoutError(err, timeout).Error()), nil
+	}
+	result.Details["build_number"] = number
+	result.Status = providers.StatusRunning
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Build #%d started", number))
+
+	build, err := p.pollBuild(ctx, client, job.Name, spec.JobID, number, pollInterval)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("polling failed: %v", timeoutError(err, timeout))), nil
+	}
+
+	result.Details["build_url"] = build.URL
+	result.Details["build_result"] = string(build.Result)
+	result.RemoteRunning = false
+	result.Details["build_duration_ms"] = build.Duration
+	result.Status = mapResult(build.Result)
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if job.Assertions.Status != "" {
+		if strings.EqualFold(string(build.Result), job.Assertions.Status) {
+			result.Status = providers.StatusSucceeded
+		} else {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("expected result '%s', got '%s'",
+				strings.ToUpper(job.Assertions.Status), build.Result))
+		}
+	} else if result.Status != providers.StatusSucceeded {
+		failures = append(failures, fmt.Sprintf("build finished with result %s", build.Result))
+	}
+
+	buildDuration := time.Duration(build.Duration) * time.Millisecond
+	if job.Assertions.MaxDuration > 0 && buildDuration > job.Assertions.MaxDuration {
+		result.Status = providers.StatusFailed
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			buildDuration, job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	if !result.Passed() {
+		p.attachConsoleTail(ctx, client, spec, number, result)
+	}
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("Build #%d finished: %s", number, build.Result))
+
+	return result, nil
+}
+
+// waitForBuildNumber follows a queue item until Jenkins assigns it a build.
+func (p *Provider) waitForBuildNumber(ctx context.Context, client *Client, queueID int, jobName string, interval time.Duration) (int, error) {
+	for {
+		item, err := client.GetQueueItem(ctx, queueID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get queue item: %w", err)
+		}
+
+		if item.Cancelled {
+			return 0, fmt.Errorf("queue item #%d was cancelled", queueID)
+		}
+
+		if item.Executable != nil {
+			return item.Executable.Number, nil
+		}
+
+		p.reportProgress(jobName, providers.StatusPending, fmt.Sprintf("Queued: %s", item.Why))
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollBuild polls a build until it finishes.
+func (p *Provider) pollBuild(ctx context.Context, client *Client, jobName, jobID string, number int, interval time.Duration) (*Build, error) {
+	start := time.Now()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-ticker.C:
+			build, err := client.GetBuild(ctx, jobID, number)
+			if err != nil {
+				return nil, err
+			}
+
+			p.reportProgress(jobName, providers.StatusRunning,
+				fmt.Sprintf("Polling... (%s) building=%t", time.Since(start).Round(time.Second), build.Building))
+
+			if !build.Building && build.Result != "" {
+				return build, nil
+			}
+		}
+	}
+}
+
+// attachConsoleTail adds the end of the console log to the result details,
+// unless log_tail_lines is 0.
+func (p *Provider) attachConsoleTail(ctx context.Context, client *Client, spec JobSpec, number int, result *providers.Result) {
+	lines := spec.tailLines()
+	if lines == 0 {
+		return
+	}
+
+	tail, err := client.ConsoleTail(ctx, spec.JobID, number, lines)
+	if err != nil {
+		result.Details["console_tail_error"] = err.Error()
+		return
+	}
+	result.Details["console_tail"] = tail
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// mapResult maps a Jenkins build result to a provider status.
+func mapResult(result BuildResult) providers.Status {
+	switch result {
+	case BuildResultSuccess:
+		return providers.StatusSucceeded
+	case BuildResultUnstable, BuildResultFailure, BuildResultNotBuilt:
+		return providers.StatusFailed
+	case BuildResultAborted:
+		return providers.StatusAborted
+	default:
+		return providers.StatusRunning
+	}
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,320 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// fakeJenkins simulates the parts of the Jenkins API used by the provider.
+type fakeJenkins struct {
+	mu         sync.Mutex
+	result     BuildResult
+	duration   int64
+	queuePolls int
+	buildPolls int
+	params     map[string]string
+}
+
+func (f *fakeJenkins) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/crumbIssuer/api/json", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "session-1", Path: "/"})
+		fmt.Fprint(w, `{"crumb":"abc123","crumbRequestField":"Jenkins-Crumb"}`)
+	})
+
+	mux.HandleFunc("/job/team/job/nightly/buildWithParameters", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if user, pass, _ := r.BasicAuth(); user != "ci" || pass != "api-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		cookie, err := r.Cookie("JSESSIONID")
+		if r.Header.Get("Jenkins-Crumb") != "abc123" || err != nil || cookie.Value != "session-1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		r.ParseForm()
+		f.mu.Lock()
+		f.params = map[string]string{"TARGET": r.Form.Get("TARGET")}
+		f.mu.Unlock()
+
+		w.Header().Set("Location", "http://jenkins.internal/queue/item/7/")
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	mux.HandleFunc("/queue/item/7/api/json", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.queuePolls++
+		if f.queuePolls < 2 {
+			fmt.Fprint(w, `{"id":7,"why":"Waiting for next available executor"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":7,"executable":{"number":42,"url":"http://jenkins.internal/job/team/job/nightly/42/"}}`)
+	})
+
+	mux.HandleFunc("/job/team/job/nightly/42/api/json", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.buildPolls++
+		if f.buildPolls < 2 {
+			fmt.Fprint(w, `{"number":42,"building":true,"result":null}`)
+			return
+		}
+		fmt.Fprintf(w, `{"number":42,"building":false,"result":"%s","duration":%d,"url":"http://jenkins.internal/job/team/job/nightly/42/"}`,
+			f.result, f.duration)
+	})
+
+	mux.HandleFunc("/job/team/job/nightly/42/consoleText", func(w http.ResponseWriter, r *http.Request) {
+		for i := 1; i <= 100; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+		}
+	})
+
+	return mux
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     BuildResult
+		duration   int64
+		assertions config.Assertions
+		tailLines  *int
+		wantStatus providers.Status
+		wantError  string
+		wantTail   bool
+	}{
+		{
+			name:       "success",
+			result:     BuildResultSuccess,
+			duration:   1500,
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "failure captures console tail",
+			result:     BuildResultFailure,
+			wantStatus: providers.StatusFailed,
+			wantError:  "build finished with result FAILURE",
+			wantTail:   true,
+		},
+		{
+			name:       "unstable accepted by assertion",
+			result:     BuildResultUnstable,
+			assertions: config.Assertions{Status: "unstable"},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "console tail disabled",
+			result:     BuildResultFailure,
+			tailLines:  new(int),
+			wantStatus: providers.StatusFailed,
+			wantError:  "build finished with result FAILURE",
+		},
+		{
+			name:       "aborted",
+			result:     BuildResultAborted,
+			wantStatus: providers.StatusAborted,
+			wantError:  "build finished with result ABORTED",
+			wantTail:   true,
+		},
+		{
+			name:       "duration exceeded",
+			result:     BuildResultSuccess,
+			duration:   int64(5 * time.Minute / time.Millisecond),
+			assertions: config.Assertions{MaxDuration: time.Minute},
+			wantStatus: providers.StatusFailed,
+			wantError:  "duration 5m0s exceeded max 1m0s",
+			wantTail:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tailLines := 3
+			if tt.tailLines != nil {
+				tailLines = *tt.tailLines
+			}
+
+			fake := &fakeJenkins{result: tt.result, duration: tt.duration}
+			server := httptest.NewServer(fake.handler())
+			defer server.Close()
+
+			env := config.Environment{
+				Type: "jenkins",
+				URL:  server.URL,
+				Auth: config.Auth{Type: "basic", Username: "ci", Token: "api-token"},
+			}
+			job := config.Job{
+				Name:         "nightly",
+				Type:         "jenkins",
+				Timeout:      5 * time.Second,
+				PollInterval: 10 * time.Millisecond,
+				Assertions:   tt.assertions,
+				Spec: map[string]any{
+					"job_id":         "team/nightly",
+					"options":        map[string]any{"TARGET": "staging"},
+					"log_tail_lines": tailLines,
+				},
+			}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if result.Details["build_number"] != 42 {
+				t.Errorf("build_number = %v, want 42", result.Details["build_number"])
+			}
+			if fake.params["TARGET"] != "staging" {
+				t.Errorf("TARGET parameter = %q, want staging", fake.params["TARGET"])
+			}
+
+			tail, ok := result.Details["console_tail"].([]string)
+			if ok != tt.wantTail {
+				t.Fatalf("console_tail present = %v, want %v", ok, tt.wantTail)
+			}
+			if tt.wantTail && strings.Join(tail, ",") != "line 98,line 99,line 100" {
+				t.Errorf("console_tail = %v", tail)
+			}
+		})
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/build"):
+			w.Header().Set("Location", "/queue/item/1/")
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasPrefix(r.URL.Path, "/queue/item/1"):
+			fmt.Fprint(w, `{"id":1,"why":"Build is blocked"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	job := config.Job{
+		Name:         "blocked",
+		Type:         "jenkins",
+		Spec:         map[string]any{"job_id": "blocked"},
+		Timeout:      50 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	result, err := NewProvider().Execute(context.Background(), job, config.Environment{Type: "jenkins", URL: server.URL})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != providers.StatusFailed || result.Error != "timeout after 50ms" {
+		t.Errorf("Status = %s, Error = %q, want failed with timeout", result.Status, result.Error)
+	}
+}
+
+func TestJobURL(t *testing.T) {
+	client := NewClient(config.Environment{URL: "https://ci.example.com/"}, nil)
+
+	got := client.JobURL("platform/data pipelines/nightly")
+	want := "https://ci.example.com/job/platform/job/data%20pipelines/job/nightly"
+	if got != want {
+		t.Errorf("JobURL() = %q, want %q", got, want)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/crumbIssuer/api/json" {
+			fmt.Fprint(w, `{"crumb":"c1","crumbRequestField":"Jenkins-Crumb"}`)
+			return
+		}
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.RequestURI()+" crumb="+r.Header.Get("Jenkins-Crumb"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "jenkins", URL: server.URL}
+	job := config.Job{Name: "deploy", Type: "jenkins", Spec: map[string]any{"job_id": "deploy"}}
+
+	tests := []struct {
+		name        string
+		details     map[string]interface{}
+		running     bool
+		wantAborted bool
+		wantRequest string
+	}{
+		{
+			name:        "running build",
+			details:     map[string]interface{}{"queue_id": 7, "build_number": 12},
+			running:     true,
+			wantAborted: true,
+			wantRequest: "POST /job/deploy/12/stop crumb=c1",
+		},
+		{
+			name:        "queued build",
+			details:     map[string]interface{}{"queue_id": 7},
+			running:     true,
+			wantAborted: true,
+			wantRequest: "POST /queue/cancelItem?id=7 crumb=c1",
+		},
+		{
+			name:    "finished build",
+			details: map[string]interface{}{"queue_id": 7, "build_number": 12, "build_result": "FAILURE"},
+		},
+		{
+			name:    "never triggered",
+			details: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			requests = nil
+			mu.Unlock()
+
+			result := &providers.Result{Status: providers.StatusFailed, RemoteRunning: tt.running, Details: tt.details}
+			aborted, err := NewProvider().Abort(context.Background(), job, env, result)
+			if err != nil {
+				t.Fatalf("Abort() error = %v", err)
+			}
+			if aborted != tt.wantAborted {
+				t.Errorf("Abort() = %t, want %t", aborted, tt.wantAborted)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if tt.wantRequest == "" && len(requests) > 0 {
+				t.Errorf("unexpected requests %v", requests)
+			}
+			if tt.wantRequest != "" && (len(requests) != 1 || requests[0] != tt.wantRequest) {
+				t.Errorf("requests = %v, want %q", requests, tt.wantRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package jenkins
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a jenkins job.
+type JobSpec struct {
+	// JobID is the full job name, e.g. folder/job.
+	JobID string `yaml:"job_id"`
+
+	// Options are passed to the build as its parameters.
+	Options map[string]string `yaml:"options"`
+
+	// LogTailLines is how many console lines to capture when the build
+	// fails. Unset uses defaultLogTailLines; zero captures none.
+	LogTailLines *int `yaml:"log_tail_lines"`
+}
+
+// tailLines returns how many console lines to capture.
+func (s JobSpec) tailLines() int {
+	if s.LogTailLines != nil {
+		return *s.LogTailLines
+	}
+	return defaultLogTailLines
+}
+
+// decodeJobSpec decodes the job's settings.
+func decodeJobSpec(job config.Job) (JobSpec, config.ValidationErrors) {
+	var spec JobSpec
+	errs := config.DecodeJobSpec(job, &spec)
+	return spec, errs
+}
+
+// validResults are the build results that may be asserted.
+var validResults = map[string]bool{
+	"":          true,
+	"success":   true,
+	"unstable":  true,
+	"failure":   true,
+	"aborted":   true,
+	"not_built": true,
+}
+
+// ValidateEnvironment validates a Jenkins environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	errs = append(errs, providers.RequireURL(env)...)
+	return append(errs, providers.ValidateAuthType(env, "jenkins", "basic")...)
+}
+
+// ValidateJob validates a Jenkins job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, errs := decodeJobSpec(job)
+	errs = append(errs, config.DecodeAssertions(job, nil)...)
+
+	if spec.JobID == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "job_id",
+			Message: "is required for jenkins jobs (full job name, e.g. folder/job)",
+		})
+	}
+
+	if spec.LogTailLines != nil && *spec.LogTailLines < 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "log_tail_lines",
+			Message: "must not be negative",
+		})
+	}
+
+	if !validResults[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid jenkins result '%s', must be one of: success, unstable, failure, aborted, not_built", job.Assertions.Status),
+		})
+	}
+
+	return errs
+}
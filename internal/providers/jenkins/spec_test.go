@@ -0,0 +1,53 @@
+package jenkins
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid",
+			job:  config.Job{Settings: map[string]any{"job_id": "team/nightly", "log_tail_lines": 0}},
+		},
+		{
+			name: "missing job_id",
+			job:  config.Job{Assertions: config.Assertions{Status: "passed"}},
+			want: []string{"job_id: is required", "assertions.status: invalid jenkins result 'passed'"},
+		},
+		{
+			name: "negative log_tail_lines",
+			job:  config.Job{Spec: map[string]any{"job_id": "deploy", "log_tail_lines": -1}},
+			want: []string{"log_tail_lines: must not be negative"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{URL: "http://jenkins"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	env := config.Environment{URL: "http://jenkins", Auth: config.Auth{Type: "bearer", Token: "t"}}
+	if errs := NewProvider().ValidateEnvironment(env); !strings.Contains(errs.Error(), "auth.type: jenkins environments support basic auth") {
+		t.Errorf("ValidateEnvironment() = %v, want bearer auth rejected", errs)
+	}
+}
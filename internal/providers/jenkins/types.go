@@ -0,0 +1,47 @@
+// Package jenkins provides a Jenkins job execution provider.
+package jenkins
+
+// BuildResult represents a Jenkins build result.
+type BuildResult string
+
+const (
+	BuildResultSuccess  BuildResult = "SUCCESS"
+	BuildResultUnstable BuildResult = "UNSTABLE"
+	BuildResultFailure  BuildResult = "FAILURE"
+	BuildResultAborted  BuildResult = "ABORTED"
+	BuildResultNotBuilt BuildResult = "NOT_BUILT"
+)
+
+// Crumb represents a CSRF crumb from the Jenkins crumb issuer.
+type Crumb struct {
+	Crumb             string `json:"crumb"`
+	CrumbRequestField string `json:"crumbRequestField"`
+}
+
+// QueueItem represents an item in the Jenkins build queue.
+type QueueItem struct {
+	ID         int         `json:"id"`
+	Blocked    bool        `json:"blocked"`
+	Buildable  bool        `json:"buildable"`
+	Cancelled  bool        `json:"cancelled"`
+	Why        string      `json:"why"`
+	Executable *Executable `json:"executable"`
+}
+
+// Executable identifies the build started from a queue item.
+type Executable struct {
+	Number int    `json:"number"`
+	URL    string `json:"url"`
+}
+
+// Build represents a Jenkins build.
+type Build struct {
+	Number            int         `json:"number"`
+	URL               string      `json:"url"`
+	Building          bool        `json:"building"`
+	Result            BuildResult `json:"result"`
+	Duration          int64       `json:"duration"`
+	EstimatedDuration int64       `json:"estimatedDuration"`
+	Timestamp         int64       `json:"timestamp"`
+	DisplayName       string      `json:"displayName"`
+}
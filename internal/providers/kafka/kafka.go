@@ -0,0 +1,227 @@
+// Package kafka provides a provider that checks Kafka broker reachability,
+// topic existence and consumer group lag.
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// defaultTimeout is used when neither the job nor the defaults set one.
+const defaultTimeout = 30 * time.Second
+
+// Provider implements the Kafka provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new Kafka provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "kafka"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Execute fetches cluster metadata from the brokers and, when set, checks
+// that the job's topic exists and asserts on its consumer group's lag.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "kafka",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     map[string]interface{}{},
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	opts, err := clientOptions(env)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to create client: %v", err)), nil
+	}
+	defer client.Close()
+	admin := kadm.NewClient(client)
+
+	p.reportProgress(job.Name, providers.StatusRunning, "Fetching cluster metadata...")
+
+	metadata, err := admin.BrokerMetadata(ctx)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("brokers unreachable: %v", timeoutError(err, timeout))), nil
+	}
+	result.Details["brokers"] = len(metadata.Brokers)
+	if metadata.Cluster != "" {
+		result.Details["cluster"] = metadata.Cluster
+	}
+
+	if spec.Topic != "" {
+		result.Details["topic"] = spec.Topic
+		topics, err := admin.ListTopics(ctx, spec.Topic)
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("failed to describe topic: %v", timeoutError(err, timeout))), nil
+		}
+		if !topics.Has(spec.Topic) {
+			return p.fail(result, fmt.Sprintf("topic %s does not exist", spec.Topic)), nil
+		}
+		result.Details["partitions"] = len(topics[spec.Topic].Partitions)
+	}
+
+	var lag int64
+	if spec.Group != "" {
+		result.Details["group"] = spec.Group
+		p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Fetching lag for %s...", spec.Group))
+
+		lag, err = groupLag(ctx, admin, spec.Group, spec.Topic, result.Details)
+		if err != nil {
+			return p.fail(result, timeoutError(err, timeout).Error()), nil
+		}
+	}
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if assertions.Lag != nil {
+		if err := assertion.Evaluate(lag, nil, *assertions.Lag); err != nil {
+			failures = append(failures, fmt.Sprintf("lag: %v", err))
+		}
+	}
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("%d broker(s)", len(metadata.Brokers)))
+
+	return result, nil
+}
+
+// groupLag returns the total lag of a consumer group, limited to topic when
+// it is set, and records the group's state and members in details.
+func groupLag(ctx context.Context, admin *kadm.Client, group, topic string, details map[string]interface{}) (int64, error) {
+	lags, err := admin.Lag(ctx, group)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch group lag: %w", err)
+	}
+
+	described, ok := lags[group]
+	if !ok || described.State == "Dead" {
+		return 0, fmt.Errorf("consumer group %s does not exist", group)
+	}
+	if err := described.Error(); err != nil {
+		return 0, fmt.Errorf("failed to fetch group lag: %w", err)
+	}
+
+	details["group_state"] = described.State
+	details["members"] = len(described.Members)
+
+	if topic == "" {
+		lag := described.Lag.Total()
+		details["lag"] = lag
+		return lag, nil
+	}
+
+	byTopic := described.Lag.TotalByTopic()
+	if _, ok := byTopic[topic]; !ok {
+		return 0, fmt.Errorf("consumer group %s has no offsets for topic %s", group, topic)
+	}
+	lag := byTopic[topic].Lag
+	details["lag"] = lag
+	return lag, nil
+}
+
+// clientOptions builds client options for the environment's brokers. TLS is
+// used when enabled, and basic auth is sent as SASL/PLAIN.
+func clientOptions(env config.Environment) ([]kgo.Opt, error) {
+	spec, errs := decodeEnvSpec(env)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid environment spec: %v", errs)
+	}
+	if len(spec.Brokers) == 0 {
+		return nil, errors.New("no brokers configured")
+	}
+
+	opts := []kgo.Opt{kgo.SeedBrokers(spec.Brokers...)}
+
+	if env.TLS.Enabled {
+		tlsConfig, err := providers.NewTLSConfig(env.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	if env.Auth.Type == "basic" {
+		mechanism := plain.Auth{User: env.Auth.Username, Pass: env.Auth.Password}.AsMechanism()
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+
+	return opts, nil
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,156 @@
+package kafka
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// newCluster starts an in-process cluster with an orders topic holding ten
+// records in partition 0, and a billing group that has committed six.
+func newCluster(t *testing.T) []string {
+	t.Helper()
+
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, "orders"))
+	if err != nil {
+		t.Fatalf("failed to start cluster: %v", err)
+	}
+	t.Cleanup(cluster.Close)
+	brokers := cluster.ListenAddrs()
+
+	client, err := kgo.NewClient(kgo.SeedBrokers(brokers...), kgo.RecordPartitioner(kgo.ManualPartitioner()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		record := &kgo.Record{Topic: "orders", Partition: 0, Value: []byte("order")}
+		if err := client.ProduceSync(ctx, record).FirstErr(); err != nil {
+			t.Fatalf("failed to produce: %v", err)
+		}
+	}
+
+	offsets := make(kadm.Offsets)
+	offsets.Add(kadm.Offset{Topic: "orders", Partition: 0, At: 6, LeaderEpoch: -1})
+	responses, err := kadm.NewClient(client).CommitOffsets(ctx, "billing", offsets)
+	if err == nil {
+		err = responses.Error()
+	}
+	if err != nil {
+		t.Fatalf("failed to commit offsets: %v", err)
+	}
+
+	return brokers
+}
+
+func TestExecute(t *testing.T) {
+	brokers := newCluster(t)
+
+	tests := []struct {
+		name       string
+		topic      string
+		group      string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "brokers reachable",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "topic exists",
+			topic:      "orders",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "topic missing",
+			topic:      "refunds",
+			wantStatus: providers.StatusFailed,
+			wantError:  "topic refunds does not exist",
+		},
+		{
+			name:       "lag within limit",
+			topic:      "orders",
+			group:      "billing",
+			assertions: config.Assertions{Settings: map[string]any{"lag": map[string]any{"less_than": 5}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "lag too high",
+			group:      "billing",
+			assertions: config.Assertions{Settings: map[string]any{"lag": map[string]any{"equals": 0}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "lag: expected 0, got 4",
+		},
+		{
+			name:       "group missing",
+			group:      "shipping",
+			wantStatus: providers.StatusFailed,
+			wantError:  "consumer group shipping does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "orders",
+				Type:       "kafka",
+				Spec:       map[string]any{"topic": tt.topic, "group": tt.group},
+				Timeout:    10 * time.Second,
+				Assertions: tt.assertions,
+			}
+			env := config.Environment{Type: "kafka", Spec: map[string]any{"brokers": brokers}}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	p := NewProvider()
+
+	job := config.Job{Settings: map[string]any{"topic": "orders", "group": "billing"}, Assertions: config.Assertions{Settings: map[string]any{"lag": map[string]any{"less_than": 100}}}}
+	if errs := p.ValidateJob(job, config.Environment{}); len(errs) > 0 {
+		t.Errorf("ValidateJob() = %v, want no errors", errs)
+	}
+
+	job = config.Job{Assertions: config.Assertions{Settings: map[string]any{"lag": map[string]any{"equals": 0}}}}
+	if errs := p.ValidateJob(job, config.Environment{}); len(errs) != 1 || !strings.Contains(errs.Error(), "group: is required for lag assertions") {
+		t.Errorf("ValidateJob() = %v, want group error", errs)
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	p := NewProvider()
+
+	errs := p.ValidateEnvironment(config.Environment{Type: "kafka"})
+	if !strings.Contains(errs.Error(), "brokers: is required") {
+		t.Errorf("ValidateEnvironment() = %v, want brokers error", errs)
+	}
+
+	errs = p.ValidateEnvironment(config.Environment{Settings: map[string]any{"brokers": []any{"kafka-1:9092", "kafka-2"}}})
+	if len(errs) != 1 || !strings.Contains(errs.Error(), "brokers[1]: must be host:port") {
+		t.Errorf("ValidateEnvironment() = %v, want brokers[1] error", errs)
+	}
+}
@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// EnvSpec holds the settings of a kafka environment.
+type EnvSpec struct {
+	// Brokers are the host:port seed brokers.
+	Brokers []string `yaml:"brokers"`
+}
+
+// JobSpec holds the settings of a kafka job.
+type JobSpec struct {
+	// Topic must exist. With Group, lag is only counted for this topic.
+	Topic string `yaml:"topic"`
+
+	// Group is the consumer group whose lag is measured.
+	Group string `yaml:"group"`
+}
+
+// Assertions holds the Kafka-specific assertions of a job.
+type Assertions struct {
+	// Lag matches the consumer group's total lag.
+	Lag *config.Matcher `yaml:"lag"`
+}
+
+// decodeEnvSpec decodes the environment's settings.
+func decodeEnvSpec(env config.Environment) (EnvSpec, config.ValidationErrors) {
+	var spec EnvSpec
+	errs := config.DecodeEnvironmentSpec(env, &spec)
+	return spec, errs
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// ValidateEnvironment validates a Kafka environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	spec, errs := decodeEnvSpec(env)
+	errs = append(errs, providers.ValidateAuthType(env, "kafka", "basic")...)
+
+	if len(spec.Brokers) == 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "brokers",
+			Message: "is required for kafka environments",
+		})
+	}
+	for i, broker := range spec.Brokers {
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			errs = append(errs, config.ValidationError{
+				Field:   fmt.Sprintf("brokers[%d]", i),
+				Message: "must be host:port",
+			})
+		}
+	}
+
+	return errs
+}
+
+// ValidateJob validates a Kafka job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if assertions.Lag != nil {
+		if spec.Group == "" {
+			errs = append(errs, config.ValidationError{
+				Field:   "group",
+				Message: "is required for lag assertions",
+			})
+		}
+		errs = append(errs, config.ValidateMatcher(*assertions.Lag, "assertions.lag")...)
+	}
+
+	return errs
+}
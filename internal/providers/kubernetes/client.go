@@ -0,0 +1,55 @@
+// Package kubernetes provides a provider that runs Kubernetes Jobs, either
+// from an inline manifest or from an existing CronJob's template.
+package kubernetes
+
+import (
+	"fmt"
+
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// NewClientset creates a clientset for the environment and returns it along
+// with the namespace of the selected kubeconfig context. An empty kubeconfig
+// path uses the default loading rules ($KUBECONFIG, ~/.kube/config) and falls
+// back to the in-cluster service account configuration.
+func NewClientset(env config.Environment) (clientset.Interface, string, error) {
+	spec, errs := decodeEnvSpec(env)
+	if len(errs) > 0 {
+		return nil, "", fmt.Errorf("invalid environment spec: %v", errs)
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if spec.Kubeconfig != "" {
+		rules.ExplicitPath = spec.Kubeconfig
+	}
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: spec.Context}
+	if env.URL != "" {
+		overrides.ClusterInfo.Server = env.URL
+	}
+	if env.Auth.Type == "bearer" {
+		overrides.AuthInfo.Token = env.Auth.Token
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubernetes config: %w", err)
+	}
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to determine namespace: %w", err)
+	}
+
+	cs, err := clientset.NewForConfig(restConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return cs, namespace, nil
+}
@@ -0,0 +1,464 @@
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultLogTailLines is the number of log lines collected per container.
+	defaultLogTailLines = 50
+
+	// cleanupTimeout bounds Job deletion, which runs after the job context
+	// may already have expired.
+	cleanupTimeout = 30 * time.Second
+)
+
+// Cleanup policies.
+const (
+	CleanupAlways    = "always"
+	CleanupOnSuccess = "on_success"
+	CleanupNever     = "never"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// Provider implements the Kubernetes Job provider.
+type Provider struct {
+	onProgress   providers.ProgressCallback
+	newClientset func(env config.Environment) (clientset.Interface, string, error)
+}
+
+// NewProvider creates a new Kubernetes provider.
+func NewProvider() *Provider {
+	return &Provider{newClientset: NewClientset}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "kubernetes"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Capabilities describes what the Kubernetes provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Polling: true, Logs: true}
+}
+
+// Execute creates a Kubernetes Job, watches it to completion and returns the result.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "kubernetes",
+		Status:      providers.StatusPending,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	timeout := job.Timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	spec, errs := decodeJobSpec(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+	envSpec, errs := decodeEnvSpec(env)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid environment spec: %v", errs)), nil
+	}
+
+	cs, contextNamespace, err := p.newClientset(env)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	k8sJob, err := p.buildJob(ctx, cs, job.Name, spec, envSpec.Namespace, contextNamespace)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	p.reportProgress(job.Name, providers.StatusPending, "Creating job...")
+
+	created, err := cs.BatchV1().Jobs(k8sJob.Namespace).Create(ctx, k8sJob, metav1.CreateOptions{})
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to create job: %v", err)), nil
+	}
+	result.Details["job_name"] = created.Name
+	result.Details["namespace"] = created.Namespace
+	result.Status = providers.StatusRunning
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Job %s created", created.Name))
+
+	finished, err := p.waitForJob(ctx, cs, created.Namespace, created.Name, job.Name)
+	if err != nil {
+		p.fail(result, fmt.Sprintf("watch failed: %v", timeoutError(err, timeout)))
+		p.collectLogs(cs, spec, created, result)
+		p.cleanup(cs, spec, created, result)
+		return result, nil
+	}
+
+	condition := finishedCondition(finished)
+	result.Details["condition"] = string(condition.Type)
+	if condition.Reason != "" {
+		result.Details["reason"] = condition.Reason
+	}
+	if condition.Message != "" {
+		result.Details["message"] = condition.Message
+	}
+	result.Details["succeeded"] = finished.Status.Succeeded
+	result.Details["failed"] = finished.Status.Failed
+
+	result.Status = mapCondition(condition.Type)
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if job.Assertions.Status != "" {
+		if strings.EqualFold(string(condition.Type), job.Assertions.Status) {
+			result.Status = providers.StatusSucceeded
+		} else {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("expected condition '%s', got '%s'",
+				strings.ToLower(job.Assertions.Status), strings.ToLower(string(condition.Type))))
+		}
+	} else if result.Status != providers.StatusSucceeded {
+		failures = append(failures, jobFailureMessage(condition))
+	}
+
+	if job.Assertions.MaxDuration > 0 {
+		duration := jobDuration(finished, condition, result.Duration)
+		if duration > job.Assertions.MaxDuration {
+			result.Status = providers.StatusFailed
+			failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+				duration, job.Assertions.MaxDuration))
+		}
+	}
+
+	if len(failures) > 0 {
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.collectLogs(cs, spec, created, result)
+	p.cleanup(cs, spec, created, result)
+
+	p.reportProgress(job.Name, result.Status,
+		fmt.Sprintf("Job %s finished: %s", created.Name, condition.Type))
+
+	return result, nil
+}
+
+// buildJob creates the Job object from the manifest or the CronJob template.
+func (p *Provider) buildJob(ctx context.Context, cs clientset.Interface, jobName string, spec JobSpec, envNamespace, contextNamespace string) (*batchv1.Job, error) {
+	var (
+		k8sJob *batchv1.Job
+		err    error
+	)
+
+	if spec.FromCronJob != "" {
+		namespace := firstNonEmpty(spec.Namespace, envNamespace, contextNamespace, metav1.NamespaceDefault)
+		k8sJob, err = jobFromCronJob(ctx, cs, namespace, spec.FromCronJob)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		k8sJob, err = jobFromManifest(spec.Manifest)
+		if err != nil {
+			return nil, err
+		}
+		k8sJob.Namespace = firstNonEmpty(spec.Namespace, k8sJob.Namespace, envNamespace, contextNamespace, metav1.NamespaceDefault)
+
+		if k8sJob.Name == "" {
+			prefix := k8sJob.GenerateName
+			if prefix == "" {
+				prefix = sanitizeName(jobName) + "-"
+			}
+			k8sJob.Name = generateName(prefix)
+			k8sJob.GenerateName = ""
+		}
+	}
+
+	if k8sJob.Labels == nil {
+		k8sJob.Labels = make(map[string]string)
+	}
+	k8sJob.Labels[managedByLabel] = "jprobe"
+
+	return k8sJob, nil
+}
+
+// waitForJob watches a Job until it completes or fails. The watch is
+// re-established if the server closes it.
+func (p *Provider) waitForJob(ctx context.Context, cs clientset.Interface, namespace, name, jobName string) (*batchv1.Job, error) {
+	jobs := cs.BatchV1().Jobs(namespace)
+	selector := fields.OneTermEqualSelector("metadata.name", name).String()
+
+	for {
+		w, err := jobs.Watch(ctx, metav1.ListOptions{FieldSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch job: %w", err)
+		}
+
+		// Check the current state after the watch is established so that
+		// no transition between the two calls is missed.
+		current, err := jobs.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			w.Stop()
+			return nil, fmt.Errorf("failed to get job: %w", err)
+		}
+		if finishedCondition(current) != nil {
+			w.Stop()
+			return current, nil
+		}
+
+		finished, err := p.consumeEvents(ctx, w, name, jobName)
+		w.Stop()
+		if finished != nil || err != nil {
+			return finished, err
+		}
+	}
+}
+
+// consumeEvents reads watch events until the Job finishes. It returns nil,
+// nil if the watch channel was closed.
+func (p *Provider) consumeEvents(ctx context.Context, w watch.Interface, name, jobName string) (*batchv1.Job, error) {
+	start := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil, nil
+			}
+
+			switch event.Type {
+			case watch.Error:
+				return nil, apierrors.FromObject(event.Object)
+			case watch.Deleted:
+				return nil, fmt.Errorf("job %s was deleted", name)
+			}
+
+			k8sJob, ok := event.Object.(*batchv1.Job)
+			if !ok || k8sJob.Name != name {
+				continue
+			}
+
+			p.reportProgress(jobName, providers.StatusRunning,
+				fmt.Sprintf("Watching... (%s) active=%d succeeded=%d failed=%d",
+					time.Since(start).Round(time.Second), k8sJob.Status.Active, k8sJob.Status.Succeeded, k8sJob.Status.Failed))
+
+			if finishedCondition(k8sJob) != nil {
+				return k8sJob, nil
+			}
+		}
+	}
+}
+
+// collectLogs attaches the tail of each pod container's log to the result.
+func (p *Provider) collectLogs(cs clientset.Interface, spec JobSpec, k8sJob *batchv1.Job, result *providers.Result) {
+	lines := int64(spec.tailLines())
+	if !spec.CollectLogs || lines == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	pods, err := cs.CoreV1().Pods(k8sJob.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: batchv1.JobNameLabel + "=" + k8sJob.Name,
+	})
+	if err != nil {
+		result.Details["pod_logs_error"] = err.Error()
+		return
+	}
+
+	logs := make(map[string][]string)
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			key := pod.Name
+			if len(pod.Spec.Containers) > 1 {
+				key = pod.Name + "/" + container.Name
+			}
+
+			tail, err := podLogTail(ctx, cs, pod, container.Name, lines)
+			if err != nil {
+				logs[key] = []string{fmt.Sprintf("failed to get logs: %v", err)}
+				continue
+			}
+			logs[key] = tail
+		}
+	}
+
+	if len(logs) > 0 {
+		result.Details["pod_logs"] = logs
+	}
+}
+
+// podLogTail returns the last lines of a container's log.
+func podLogTail(ctx context.Context, cs clientset.Interface, pod corev1.Pod, container string, lines int64) ([]string, error) {
+	stream, err := cs.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &lines,
+	}).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var tail []string
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		tail = append(tail, scanner.Text())
+	}
+	return tail, scanner.Err()
+}
+
+// cleanup deletes the Job according to the cleanup policy.
+func (p *Provider) cleanup(cs clientset.Interface, spec JobSpec, k8sJob *batchv1.Job, result *providers.Result) {
+	switch spec.Cleanup {
+	case CleanupNever:
+		return
+	case CleanupAlways:
+	default:
+		if !result.Passed() {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cleanupTimeout)
+	defer cancel()
+
+	propagation := metav1.DeletePropagationBackground
+	err := cs.BatchV1().Jobs(k8sJob.Namespace).Delete(ctx, k8sJob.Name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		result.Details["cleanup_error"] = err.Error()
+		return
+	}
+	result.Details["deleted"] = true
+}
+
+// finishedCondition returns the Complete or Failed condition of a Job, or nil
+// if the Job is still running.
+func finishedCondition(k8sJob *batchv1.Job) *batchv1.JobCondition {
+	for i, c := range k8sJob.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return &k8sJob.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// mapCondition maps a terminal Job condition to a provider status.
+func mapCondition(condition batchv1.JobConditionType) providers.Status {
+	switch condition {
+	case batchv1.JobComplete:
+		return providers.StatusSucceeded
+	case batchv1.JobFailed:
+		return providers.StatusFailed
+	default:
+		return providers.StatusRunning
+	}
+}
+
+// jobFailureMessage describes a failed Job condition.
+func jobFailureMessage(condition *batchv1.JobCondition) string {
+	msg := "job failed"
+	if condition.Reason != "" {
+		msg += ": " + condition.Reason
+	}
+	if condition.Message != "" {
+		msg += " (" + condition.Message + ")"
+	}
+	return msg
+}
+
+// jobDuration returns the duration reported by Kubernetes, falling back to
+// the locally measured duration if the Job has no start time.
+func jobDuration(k8sJob *batchv1.Job, condition *batchv1.JobCondition, fallback time.Duration) time.Duration {
+	if k8sJob.Status.StartTime == nil {
+		return fallback
+	}
+
+	end := condition.LastTransitionTime
+	if k8sJob.Status.CompletionTime != nil {
+		end = *k8sJob.Status.CompletionTime
+	}
+	if end.IsZero() {
+		return fallback
+	}
+	return end.Sub(k8sJob.Status.StartTime.Time)
+}
+
+// sanitizeName converts a job name into a valid Kubernetes object name prefix.
+func sanitizeName(name string) string {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if name == "" {
+		return "jprobe"
+	}
+	return name
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
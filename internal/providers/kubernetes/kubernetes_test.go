@@ -0,0 +1,281 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// finishJob simulates the Job controller: it waits for the first Job to be
+// created in the namespace, adds a pod for it and sets the given condition.
+func finishJob(t *testing.T, cs *fake.Clientset, namespace string, condition batchv1.JobConditionType, reason string) {
+	t.Helper()
+
+	go func() {
+		ctx := context.Background()
+		for i := 0; i < 200; i++ {
+			jobs, _ := cs.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+			if len(jobs.Items) == 0 {
+				time.Sleep(5 * time.Millisecond)
+				continue
+			}
+
+			job := jobs.Items[0]
+			cs.CoreV1().Pods(namespace).Create(ctx, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      job.Name + "-abcde",
+					Namespace: namespace,
+					Labels:    map[string]string{batchv1.JobNameLabel: job.Name},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+			}, metav1.CreateOptions{})
+
+			started := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+			job.Status.StartTime = &started
+			job.Status.Conditions = []batchv1.JobCondition{{
+				Type:               condition,
+				Status:             corev1.ConditionTrue,
+				Reason:             reason,
+				LastTransitionTime: metav1.Now(),
+			}}
+			if condition == batchv1.JobComplete {
+				job.Status.Succeeded = 1
+			} else {
+				job.Status.Failed = 1
+			}
+			cs.BatchV1().Jobs(namespace).UpdateStatus(ctx, &job, metav1.UpdateOptions{})
+			return
+		}
+	}()
+}
+
+func newTestProvider(cs *fake.Clientset) *Provider {
+	return &Provider{
+		newClientset: func(config.Environment) (clientset.Interface, string, error) {
+			return cs, "default", nil
+		},
+	}
+}
+
+var testManifest = map[string]any{
+	"apiVersion": "batch/v1",
+	"kind":       "Job",
+	"spec": map[string]any{
+		"template": map[string]any{
+			"spec": map[string]any{
+				"restartPolicy": "Never",
+				"containers": []any{
+					map[string]any{"name": "main", "image": "busybox", "command": []any{"true"}},
+				},
+			},
+		},
+	},
+}
+
+func TestExecuteManifest(t *testing.T) {
+	tests := []struct {
+		name        string
+		condition   batchv1.JobConditionType
+		reason      string
+		cleanup     string
+		assertions  config.Assertions
+		wantStatus  providers.Status
+		wantError   string
+		wantDeleted bool
+	}{
+		{
+			name:        "complete",
+			condition:   batchv1.JobComplete,
+			wantStatus:  providers.StatusSucceeded,
+			wantDeleted: true,
+		},
+		{
+			name:       "failed",
+			condition:  batchv1.JobFailed,
+			reason:     "BackoffLimitExceeded",
+			wantStatus: providers.StatusFailed,
+			wantError:  "job failed: BackoffLimitExceeded",
+		},
+		{
+			name:        "failed with cleanup always",
+			condition:   batchv1.JobFailed,
+			cleanup:     CleanupAlways,
+			wantStatus:  providers.StatusFailed,
+			wantError:   "job failed",
+			wantDeleted: true,
+		},
+		{
+			name:       "complete with cleanup never",
+			condition:  batchv1.JobComplete,
+			cleanup:    CleanupNever,
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:        "failure expected",
+			condition:   batchv1.JobFailed,
+			assertions:  config.Assertions{Status: "failed"},
+			wantStatus:  providers.StatusSucceeded,
+			wantDeleted: true,
+		},
+		{
+			name:       "duration exceeded",
+			condition:  batchv1.JobComplete,
+			assertions: config.Assertions{MaxDuration: time.Minute},
+			wantStatus: providers.StatusFailed,
+			wantError:  "exceeded max 1m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs := fake.NewClientset()
+			finishJob(t, cs, "batch", tt.condition, tt.reason)
+
+			job := config.Job{
+				Name: "Data Export",
+				Type: "kubernetes",
+				Spec: map[string]any{
+					"namespace":    "batch",
+					"manifest":     testManifest,
+					"collect_logs": true,
+					"cleanup":      tt.cleanup,
+				},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+
+			result, err := newTestProvider(cs).Execute(context.Background(), job, config.Environment{Type: "kubernetes"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+
+			name, _ := result.Details["job_name"].(string)
+			if !strings.HasPrefix(name, "data-export-") {
+				t.Errorf("job_name = %q, want data-export- prefix", name)
+			}
+
+			logs, _ := result.Details["pod_logs"].(map[string][]string)
+			if len(logs[name+"-abcde"]) == 0 {
+				t.Errorf("pod_logs = %v, want logs for pod %s-abcde", logs, name)
+			}
+
+			_, getErr := cs.BatchV1().Jobs("batch").Get(context.Background(), name, metav1.GetOptions{})
+			if deleted := getErr != nil; deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestExecuteFromCronJob(t *testing.T) {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-report", Namespace: "default", UID: "cron-uid"},
+		Spec: batchv1.CronJobSpec{
+			Schedule: "0 2 * * *",
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "data"}},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "main", Image: "report:latest"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cs := fake.NewClientset(cronJob)
+	finishJob(t, cs, "default", batchv1.JobComplete, "")
+
+	job := config.Job{
+		Name:    "nightly",
+		Type:    "kubernetes",
+		Spec:    map[string]any{"from_cronjob": "nightly-report", "cleanup": CleanupNever},
+		Timeout: 5 * time.Second,
+	}
+
+	result, err := newTestProvider(cs).Execute(context.Background(), job, config.Environment{Type: "kubernetes"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != providers.StatusSucceeded {
+		t.Fatalf("Status = %s, want succeeded (error: %s)", result.Status, result.Error)
+	}
+
+	name, _ := result.Details["job_name"].(string)
+	created, err := cs.BatchV1().Jobs("default").Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get created job: %v", err)
+	}
+
+	if !strings.HasPrefix(created.Name, "nightly-report-manual-") {
+		t.Errorf("Name = %q, want nightly-report-manual- prefix", created.Name)
+	}
+	if created.Labels["team"] != "data" || created.Labels[managedByLabel] != "jprobe" {
+		t.Errorf("Labels = %v", created.Labels)
+	}
+	if created.Annotations["cronjob.kubernetes.io/instantiate"] != "manual" {
+		t.Errorf("Annotations = %v", created.Annotations)
+	}
+	if len(created.OwnerReferences) != 1 || created.OwnerReferences[0].Name != "nightly-report" {
+		t.Errorf("OwnerReferences = %v", created.OwnerReferences)
+	}
+	if _, ok := result.Details["pod_logs"]; ok {
+		t.Error("pod_logs collected without collect_logs")
+	}
+}
+
+func TestExecuteTimeout(t *testing.T) {
+	cs := fake.NewClientset()
+
+	job := config.Job{
+		Name:    "stuck",
+		Type:    "kubernetes",
+		Spec:    map[string]any{"manifest": testManifest},
+		Timeout: 50 * time.Millisecond,
+	}
+
+	result, err := newTestProvider(cs).Execute(context.Background(), job, config.Environment{Type: "kubernetes"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != providers.StatusFailed || result.Error != "watch failed: timeout after 50ms" {
+		t.Errorf("Status = %s, Error = %q, want failed with timeout", result.Status, result.Error)
+	}
+}
+
+func TestExecuteMissingCronJob(t *testing.T) {
+	job := config.Job{
+		Name:    "missing",
+		Type:    "kubernetes",
+		Spec:    map[string]any{"from_cronjob": "does-not-exist"},
+		Timeout: time.Second,
+	}
+
+	result, err := newTestProvider(fake.NewClientset()).Execute(context.Background(), job, config.Environment{Type: "kubernetes"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(result.Error, "failed to get cronjob default/does-not-exist") {
+		t.Errorf("Error = %q, want missing cronjob error", result.Error)
+	}
+}
@@ -0,0 +1,77 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	clientset "k8s.io/client-go/kubernetes"
+)
+
+const (
+	// managedByLabel marks Jobs created by jprobe.
+	managedByLabel = "app.kubernetes.io/managed-by"
+
+	// maxNameLength is the maximum length of a Job name.
+	maxNameLength = 63
+)
+
+// jobFromManifest decodes an inline Job manifest.
+func jobFromManifest(manifest map[string]any) (*batchv1.Job, error) {
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	var job batchv1.Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, fmt.Errorf("invalid job manifest: %w", err)
+	}
+
+	if job.Kind != "" && job.Kind != "Job" {
+		return nil, fmt.Errorf("manifest kind must be Job, got %s", job.Kind)
+	}
+
+	return &job, nil
+}
+
+// jobFromCronJob builds a Job from a CronJob's template, the same way
+// `kubectl create job --from=cronjob/<name>` does.
+func jobFromCronJob(ctx context.Context, cs clientset.Interface, namespace, name string) (*batchv1.Job, error) {
+	cronJob, err := cs.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cronjob %s/%s: %w", namespace, name, err)
+	}
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        generateName(name + "-manual-"),
+			Namespace:   namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}, nil
+}
+
+// generateName appends a random suffix to prefix, keeping the result within
+// the Job name length limit. The name is generated locally rather than through
+// metadata.generateName so that it is known before the Job is created.
+func generateName(prefix string) string {
+	const suffixLength = 5
+	if len(prefix) > maxNameLength-suffixLength {
+		prefix = prefix[:maxNameLength-suffixLength]
+	}
+	return prefix + rand.String(suffixLength)
+}
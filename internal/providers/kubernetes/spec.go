@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// EnvSpec holds the settings of a kubernetes environment.
+type EnvSpec struct {
+	// Kubeconfig is the path to a kubeconfig file. Empty uses the default
+	// loading rules.
+	Kubeconfig string `yaml:"kubeconfig"`
+
+	// Context selects a kubeconfig context. Empty uses the current context.
+	Context string `yaml:"context"`
+
+	// Namespace is the default namespace for jobs in this environment.
+	Namespace string `yaml:"namespace"`
+}
+
+// JobSpec holds the settings of a kubernetes job.
+type JobSpec struct {
+	// Namespace overrides the namespace of the manifest and environment.
+	Namespace string `yaml:"namespace"`
+
+	// Manifest is an inline batch/v1 Job manifest.
+	Manifest map[string]any `yaml:"manifest"`
+
+	// FromCronJob names a CronJob whose template is used to create the Job.
+	FromCronJob string `yaml:"from_cronjob"`
+
+	// CollectLogs attaches the tail of each pod's log to the result.
+	CollectLogs bool `yaml:"collect_logs"`
+
+	// Cleanup is the deletion policy: always, on_success (default) or never.
+	Cleanup string `yaml:"cleanup"`
+
+	// LogTailLines is how many log lines to collect per container. Unset
+	// uses defaultLogTailLines; zero collects none.
+	LogTailLines *int `yaml:"log_tail_lines"`
+}
+
+// tailLines returns how many log lines to collect per container.
+func (s JobSpec) tailLines() int {
+	if s.LogTailLines != nil {
+		return *s.LogTailLines
+	}
+	return defaultLogTailLines
+}
+
+// decodeEnvSpec decodes the environment's settings.
+func decodeEnvSpec(env config.Environment) (EnvSpec, config.ValidationErrors) {
+	var spec EnvSpec
+	errs := config.DecodeEnvironmentSpec(env, &spec)
+	return spec, errs
+}
+
+// decodeJobSpec decodes the job's settings.
+func decodeJobSpec(job config.Job) (JobSpec, config.ValidationErrors) {
+	var spec JobSpec
+	errs := config.DecodeJobSpec(job, &spec)
+	return spec, errs
+}
+
+// validCleanup are the accepted cleanup policies.
+var validCleanup = map[string]bool{
+	"":               true,
+	CleanupAlways:    true,
+	CleanupOnSuccess: true,
+	CleanupNever:     true,
+}
+
+// validConditions are the Job conditions that may be asserted.
+var validConditions = map[string]bool{
+	"":         true,
+	"complete": true,
+	"failed":   true,
+}
+
+// ValidateEnvironment validates a Kubernetes environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	_, errs := decodeEnvSpec(env)
+	return append(errs, providers.ValidateAuthType(env, "kubernetes", "bearer")...)
+}
+
+// ValidateJob validates a Kubernetes job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, errs := decodeJobSpec(job)
+	errs = append(errs, config.DecodeAssertions(job, nil)...)
+
+	if len(spec.Manifest) == 0 && spec.FromCronJob == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "manifest",
+			Message: "is required for kubernetes jobs (or set from_cronjob)",
+		})
+	}
+
+	if len(spec.Manifest) > 0 && spec.FromCronJob != "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "from_cronjob",
+			Message: "cannot be used together with manifest",
+		})
+	}
+
+	if kind, ok := spec.Manifest["kind"].(string); ok && kind != "Job" {
+		errs = append(errs, config.ValidationError{
+			Field:   "manifest.kind",
+			Message: fmt.Sprintf("invalid kind '%s', must be Job", kind),
+		})
+	}
+
+	if !validCleanup[spec.Cleanup] {
+		errs = append(errs, config.ValidationError{
+			Field:   "cleanup",
+			Message: fmt.Sprintf("invalid cleanup policy '%s', must be one of: always, on_success, never", spec.Cleanup),
+		})
+	}
+
+	if spec.LogTailLines != nil && *spec.LogTailLines < 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "log_tail_lines",
+			Message: "must not be negative",
+		})
+	}
+
+	if !validConditions[strings.ToLower(job.Assertions.Status)] {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.status",
+			Message: fmt.Sprintf("invalid job condition '%s', must be one of: complete, failed", job.Assertions.Status),
+		})
+	}
+
+	return errs
+}
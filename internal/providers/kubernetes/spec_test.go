@@ -0,0 +1,76 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid manifest",
+			job:  config.Job{Settings: map[string]any{"manifest": testManifest, "cleanup": "always", "collect_logs": true}},
+		},
+		{
+			name: "valid cronjob",
+			job:  config.Job{Spec: map[string]any{"from_cronjob": "nightly-report", "log_tail_lines": 0}},
+		},
+		{
+			name: "missing manifest",
+			job:  config.Job{Assertions: config.Assertions{Status: "succeeded"}},
+			want: []string{"manifest: is required", "assertions.status: invalid job condition 'succeeded'"},
+		},
+		{
+			name: "manifest and cronjob",
+			job: config.Job{Settings: map[string]any{
+				"manifest":     map[string]any{"kind": "CronJob"},
+				"from_cronjob": "nightly-report",
+			}},
+			want: []string{"from_cronjob: cannot be used together with manifest", "manifest.kind: invalid kind 'CronJob'"},
+		},
+		{
+			name: "invalid cleanup and log_tail_lines",
+			job:  config.Job{Settings: map[string]any{"from_cronjob": "nightly-report", "cleanup": "sometimes", "log_tail_lines": -1}},
+			want: []string{"cleanup: invalid cleanup policy 'sometimes'", "log_tail_lines: must not be negative"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "kubernetes"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	env := config.Environment{
+		Settings: map[string]any{"kubeconfig": "~/.kube/config", "context": "prod", "namespace": "batch"},
+		Auth:     config.Auth{Type: "bearer", Token: "t"},
+	}
+	if errs := NewProvider().ValidateEnvironment(env); len(errs) > 0 {
+		t.Errorf("ValidateEnvironment() = %v, want no errors", errs)
+	}
+
+	env = config.Environment{Settings: map[string]any{"kubecontext": "prod"}, Auth: config.Auth{Type: "basic", Username: "u"}}
+	errs := NewProvider().ValidateEnvironment(env)
+	for _, want := range []string{"kubecontext: unknown field", "auth.type: kubernetes environments support bearer auth"} {
+		if !strings.Contains(errs.Error(), want) {
+			t.Errorf("expected %q in %v", want, errs)
+		}
+	}
+}
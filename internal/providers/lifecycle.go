@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"context"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// The interfaces below are optional. The executor checks for each one and
+// uses it when a provider implements it, so providers only implement the
+// hooks they need.
+
+// ProgressReporter is implemented by providers that report progress while a
+// job runs. The executor sets the callback once, before the provider's first
+// job.
+type ProgressReporter interface {
+	SetProgressCallback(cb ProgressCallback)
+}
+
+// ClientPoolUser is implemented by providers that share HTTP connections
+// across jobs. The executor sets the pool once, before the provider's first
+// job.
+type ClientPoolUser interface {
+	SetClientPool(pool *ClientPool)
+}
+
+// Initializer is implemented by providers that prepare state for an
+// environment, such as a login session, before their first job in it. Init
+// is called once per environment with its name; an error fails every job in
+// it.
+type Initializer interface {
+	Init(ctx context.Context, name string, env config.Environment) error
+}
+
+// Closer is implemented by providers that hold state between jobs. Close is
+// called once when the run finishes.
+type Closer interface {
+	Close() error
+}
+
+// Aborter is implemented by providers that start executions on a remote
+// system. Abort is called after Execute returns a result with RemoteRunning
+// set, for example when the job timed out or the run was interrupted. It
+// stops the execution recorded in result and reports whether it did.
+type Aborter interface {
+	Abort(ctx context.Context, job config.Job, env config.Environment, result *Result) (bool, error)
+}
+
+// Preflighter is implemented by providers that can check a job for a dry
+// run without running it, for example that the remote job exists or the
+// endpoint accepts connections. Preflight returns StatusPreflightPassed when
+// the checks pass and StatusFailed with an error when they do not.
+type Preflighter interface {
+	Preflight(ctx context.Context, job config.Job, env config.Environment) (*Result, error)
+}
+
+// Capabilities describes what a provider supports beyond running a job.
+type Capabilities struct {
+	// Polling is set when the provider starts a remote execution and waits
+	// for it to finish, using the job's poll_interval.
+	Polling bool `json:"polling"`
+
+	// Abort is set when an interrupted execution is stopped remotely.
+	Abort bool `json:"abort"`
+
+	// Logs is set when execution output is captured in the result.
+	Logs bool `json:"logs"`
+
+	// DryRun is set when the provider can check a job without running it;
+	// see Preflighter.
+	DryRun bool `json:"dry_run"`
+}
+
+// CapabilityReporter is implemented by providers that describe their
+// capabilities.
+type CapabilityReporter interface {
+	Capabilities() Capabilities
+}
+
+// CapabilitiesOf returns the capabilities of a provider, or none if it does
+// not describe them.
+func CapabilitiesOf(p Provider) Capabilities {
+	if r, ok := p.(CapabilityReporter); ok {
+		return r.Capabilities()
+	}
+	return Capabilities{}
+}
@@ -0,0 +1,110 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// maxErrorBody is the number of bytes of a non-API error body kept in error
+// messages.
+const maxErrorBody = 4096
+
+// Client is a Prometheus HTTP API client.
+type Client struct {
+	baseURL    string
+	auth       config.Auth
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewClient creates a new Prometheus client. If httpClient is nil, a
+// dedicated client is created; otherwise it is shared.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = providers.NewHTTPClient(env.Transport)
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(env.URL, "/"),
+		auth:       env.Auth,
+		headers:    env.Headers,
+		httpClient: httpClient,
+	}
+}
+
+// Query runs an instant query evaluated at ts.
+func (c *Client) Query(ctx context.Context, query string, ts time.Time) (*Response, error) {
+	params := url.Values{
+		"query": {query},
+		"time":  {formatTime(ts)},
+	}
+	return c.do(ctx, "/api/v1/query", params)
+}
+
+// QueryRange runs a range query from start to end sampled every step.
+func (c *Client) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*Response, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	return c.do(ctx, "/api/v1/query_range", params)
+}
+
+// do posts a form-encoded query and decodes the response. API errors, such
+// as a PromQL syntax error, are returned as errors.
+func (c *Client) do(ctx context.Context, path string, params url.Values) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	providers.ApplyAuth(req, c.auth)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// The API answers bad queries and execution errors with a 4xx or 5xx
+	// status and a JSON error; anything else is a proxy or server error.
+	var apiResp Response
+	if err := json.Unmarshal(body, &apiResp); err != nil || apiResp.Status == "" {
+		if len(body) > maxErrorBody {
+			body = body[:maxErrorBody]
+		}
+		return nil, fmt.Errorf("prometheus request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if apiResp.Status != "success" {
+		return nil, fmt.Errorf("prometheus error [%s]: %s", apiResp.ErrorType, apiResp.Error)
+	}
+	return &apiResp, nil
+}
+
+// formatTime formats t as Unix seconds with millisecond precision.
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', 3, 64)
+}
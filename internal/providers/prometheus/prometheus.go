@@ -0,0 +1,310 @@
+package prometheus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultTimeout is used when neither the job nor the defaults set one.
+	defaultTimeout = 30 * time.Second
+
+	// rangePoints is the number of samples a range query aims for when the
+	// job does not set a step.
+	rangePoints = 60
+
+	// detailSeriesLimit is the number of series included in result details.
+	detailSeriesLimit = 10
+
+	// failureSeriesLimit is the number of failing series named in the error
+	// before the rest are counted.
+	failureSeriesLimit = 5
+)
+
+// value is a series reduced to a single value.
+type value struct {
+	labels string
+	value  float64
+}
+
+// Provider implements the Prometheus query provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+}
+
+// NewProvider creates a new Prometheus provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "prometheus"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Execute runs the job's query and asserts on the value of every returned
+// series and on the number of series.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "prometheus",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     map[string]interface{}{},
+	}
+
+	spec, assertions, err := decodeJob(job)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+	result.Details["query"] = spec.Query
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := NewClient(env, p.clients.Client(job.Environment, env))
+
+	p.reportProgress(job.Name, providers.StatusRunning, "Running query...")
+
+	var resp *Response
+	if spec.Range > 0 {
+		step := spec.Step
+		if step == 0 {
+			step = defaultStep(spec.Range)
+		}
+		result.Details["range"] = spec.Range.String()
+		result.Details["step"] = step.String()
+		resp, err = client.QueryRange(ctx, spec.Query, result.StartedAt.Add(-spec.Range), result.StartedAt, step)
+	} else {
+		resp, err = client.Query(ctx, spec.Query, result.StartedAt)
+	}
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("query failed: %v", timeoutError(err, timeout))), nil
+	}
+
+	result.Details["result_type"] = resp.Data.ResultType
+	if len(resp.Warnings) > 0 {
+		result.Details["warnings"] = resp.Warnings
+	}
+
+	values, err := reduce(resp.Data, spec.Aggregation)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+	result.Details["series"] = len(values)
+	result.Details["values"] = detailValues(values)
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if assertions.Series != nil {
+		if err := assertion.Evaluate(len(values), nil, *assertions.Series); err != nil {
+			failures = append(failures, fmt.Sprintf("series: %v", err))
+		}
+	} else if len(values) == 0 {
+		failures = append(failures, "query returned no series")
+	}
+
+	if assertions.Value != nil {
+		failures = append(failures, checkValues(values, *assertions.Value)...)
+	}
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("%d series", len(values)))
+
+	return result, nil
+}
+
+// reduce turns a query result into one value per series. Scalars become a
+// single unlabelled value and matrix series are aggregated.
+func reduce(data Data, aggregation string) ([]value, error) {
+	switch data.ResultType {
+	case ResultTypeScalar:
+		var sample Sample
+		if err := json.Unmarshal(data.Result, &sample); err != nil {
+			return nil, fmt.Errorf("failed to decode scalar: %w", err)
+		}
+		v, err := sample.Value()
+		if err != nil {
+			return nil, err
+		}
+		return []value{{labels: "scalar", value: v}}, nil
+
+	case ResultTypeVector, ResultTypeMatrix:
+		var series []Series
+		if err := json.Unmarshal(data.Result, &series); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", data.ResultType, err)
+		}
+
+		values := make([]value, 0, len(series))
+		for _, s := range series {
+			var v float64
+			var err error
+			if data.ResultType == ResultTypeVector {
+				v, err = s.Value.Value()
+			} else {
+				v, err = aggregate(s.Values, aggregation)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", s.Labels(), err)
+			}
+			values = append(values, value{labels: s.Labels(), value: v})
+		}
+		return values, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported result type %q", data.ResultType)
+	}
+}
+
+// aggregate reduces the samples of a matrix series to a single value.
+func aggregate(samples []Sample, aggregation string) (float64, error) {
+	if len(samples) == 0 {
+		return 0, errors.New("series has no samples")
+	}
+
+	values := make([]float64, len(samples))
+	for i, s := range samples {
+		v, err := s.Value()
+		if err != nil {
+			return 0, err
+		}
+		values[i] = v
+	}
+
+	switch aggregation {
+	case "", "last":
+		return values[len(values)-1], nil
+	case "min":
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Min(m, v)
+		}
+		return m, nil
+	case "max":
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Max(m, v)
+		}
+		return m, nil
+	case "sum", "avg":
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		if aggregation == "avg" {
+			return sum / float64(len(values)), nil
+		}
+		return sum, nil
+	default:
+		return 0, fmt.Errorf("unknown aggregation %q", aggregation)
+	}
+}
+
+// checkValues evaluates the matcher against every series and names the
+// first few that fail.
+func checkValues(values []value, m config.Matcher) []string {
+	var failures []string
+	failed := 0
+	for _, v := range values {
+		if err := assertion.Evaluate(v.value, nil, m); err != nil {
+			failed++
+			if failed <= failureSeriesLimit {
+				failures = append(failures, fmt.Sprintf("value %s: %v", v.labels, err))
+			}
+		}
+	}
+	if failed > failureSeriesLimit {
+		failures = append(failures, fmt.Sprintf("%d more series failed", failed-failureSeriesLimit))
+	}
+	return failures
+}
+
+// detailValues maps the first series' labels to their values. NaN and
+// infinities are kept as strings since JSON cannot encode them.
+func detailValues(values []value) map[string]interface{} {
+	details := make(map[string]interface{}, min(len(values), detailSeriesLimit))
+	for i, v := range values {
+		if i == detailSeriesLimit {
+			break
+		}
+		if math.IsNaN(v.value) || math.IsInf(v.value, 0) {
+			details[v.labels] = fmt.Sprint(v.value)
+		} else {
+			details[v.labels] = v.value
+		}
+	}
+	return details
+}
+
+// defaultStep spreads a range query over rangePoints samples, at least one
+// second apart.
+func defaultStep(r time.Duration) time.Duration {
+	step := (r / rangePoints).Truncate(time.Second)
+	if step < time.Second {
+		return time.Second
+	}
+	return step
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
@@ -0,0 +1,246 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// responses maps PromQL queries to canned API responses.
+var responses = map[string]string{
+	`up{job="api"}`: `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"__name__":"up","job":"api","instance":"a:9090"},"value":[1700000000,"1"]},
+		{"metric":{"__name__":"up","job":"api","instance":"b:9090"},"value":[1700000000,"1"]}]}}`,
+	`up{job="worker"}`: `{"status":"success","data":{"resultType":"vector","result":[
+		{"metric":{"__name__":"up","job":"worker","instance":"a:9090"},"value":[1700000000,"1"]},
+		{"metric":{"__name__":"up","job":"worker","instance":"b:9090"},"value":[1700000000,"0"]}]}}`,
+	`up{job="missing"}`:   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+	`scalar(error_ratio)`: `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"0.004"]}}`,
+	`queue_depth`: `{"status":"success","data":{"resultType":"matrix","result":[
+		{"metric":{"queue":"orders"},"values":[[1700000000,"10"],[1700000060,"40"],[1700000120,"25"]]}]},
+		"warnings":["partial response"]}`,
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+
+		query := r.Form.Get("query")
+		switch r.URL.Path {
+		case "/api/v1/query":
+			if r.Form.Get("time") == "" {
+				t.Errorf("instant query without time")
+			}
+		case "/api/v1/query_range":
+			if r.Form.Get("start") == "" || r.Form.Get("end") == "" || r.Form.Get("step") == "" {
+				t.Errorf("range query without start, end or step: %v", r.Form)
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		body, ok := responses[query]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			body = `{"status":"error","errorType":"bad_data","error":"invalid parameter \"query\": parse error"}`
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestExecute(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		query       string
+		rangeWindow time.Duration
+		aggregation string
+		assertions  config.Assertions
+		wantStatus  providers.Status
+		wantError   string
+	}{
+		{
+			name:       "all series up",
+			query:      `up{job="api"}`,
+			assertions: config.Assertions{Settings: map[string]any{"value": map[string]any{"equals": 1}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "one series down",
+			query:      `up{job="worker"}`,
+			assertions: config.Assertions{Settings: map[string]any{"value": map[string]any{"equals": 1}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  `value up{instance="b:9090", job="worker"}: expected 1, got 0`,
+		},
+		{
+			name:       "series count",
+			query:      `up{job="api"}`,
+			assertions: config.Assertions{Settings: map[string]any{"series": map[string]any{"greater_than": 2}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "series: expected greater than 2, got 2",
+		},
+		{
+			name:       "empty result",
+			query:      `up{job="missing"}`,
+			assertions: config.Assertions{Settings: map[string]any{"value": map[string]any{"equals": 1}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "query returned no series",
+		},
+		{
+			name:       "empty result expected",
+			query:      `up{job="missing"}`,
+			assertions: config.Assertions{Settings: map[string]any{"series": map[string]any{"equals": 0}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "scalar",
+			query:      `scalar(error_ratio)`,
+			assertions: config.Assertions{Settings: map[string]any{"value": map[string]any{"less_than": 0.01}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:        "range max",
+			query:       `queue_depth`,
+			rangeWindow: time.Hour,
+			aggregation: "max",
+			assertions:  config.Assertions{Settings: map[string]any{"value": map[string]any{"less_than": 30}}},
+			wantStatus:  providers.StatusFailed,
+			wantError:   "expected less than 30, got 40",
+		},
+		{
+			name:        "range last",
+			query:       `queue_depth`,
+			rangeWindow: time.Hour,
+			assertions:  config.Assertions{Settings: map[string]any{"value": map[string]any{"less_than": 30}}},
+			wantStatus:  providers.StatusSucceeded,
+		},
+		{
+			name:       "invalid query",
+			query:      `up{`,
+			wantStatus: providers.StatusFailed,
+			wantError:  "query failed: prometheus error [bad_data]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name: "metrics",
+				Type: "prometheus",
+				Spec: map[string]any{
+					"query":       tt.query,
+					"range":       tt.rangeWindow,
+					"aggregation": tt.aggregation,
+				},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+			env := config.Environment{Type: "prometheus", URL: server.URL}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	samples := []Sample{{0.0, "4"}, {60.0, "1"}, {120.0, "7"}}
+
+	tests := map[string]float64{
+		"":     7,
+		"last": 7,
+		"min":  1,
+		"max":  7,
+		"sum":  12,
+		"avg":  4,
+	}
+
+	for aggregation, want := range tests {
+		got, err := aggregate(samples, aggregation)
+		if err != nil || got != want {
+			t.Errorf("aggregate(%q) = %v, %v, want %v", aggregation, got, err, want)
+		}
+	}
+}
+
+func TestDefaultStep(t *testing.T) {
+	tests := map[time.Duration]time.Duration{
+		time.Hour:        time.Minute,
+		10 * time.Second: time.Second,
+		90 * time.Minute: 90 * time.Second,
+	}
+
+	for r, want := range tests {
+		if got := defaultStep(r); got != want {
+			t.Errorf("defaultStep(%s) = %s, want %s", r, got, want)
+		}
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid range query",
+			job: config.Job{
+				Settings:   map[string]any{"query": "queue_depth", "range": "1h", "step": "1m", "aggregation": "max"},
+				Assertions: config.Assertions{Settings: map[string]any{"value": map[string]any{"less_than": 30}}},
+			},
+		},
+		{
+			name: "step without range",
+			job:  config.Job{Settings: map[string]any{"step": "1m", "aggregation": "median"}},
+			want: []string{
+				"query: is required for prometheus jobs",
+				"step: requires range",
+				"aggregation: invalid aggregation 'median'",
+			},
+		},
+		{
+			name: "step longer than range",
+			job:  config.Job{Spec: map[string]any{"query": "up", "range": "5m", "step": "10m"}},
+			want: []string{"step: must be between 0 and range"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "prometheus", URL: "http://prometheus:9090"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
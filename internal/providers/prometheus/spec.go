@@ -0,0 +1,108 @@
+package prometheus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a prometheus job.
+type JobSpec struct {
+	providers.QuerySpec `yaml:",inline"`
+
+	// Range turns the query into a range query over the trailing window
+	// sampled every Step, and Aggregation reduces each series to a single
+	// value (last by default).
+	Range       time.Duration `yaml:"range"`
+	Step        time.Duration `yaml:"step"`
+	Aggregation string        `yaml:"aggregation"`
+}
+
+// Assertions holds the Prometheus-specific assertions of a job.
+type Assertions struct {
+	// Value must match the value of every series.
+	Value *config.Matcher `yaml:"value"`
+
+	// Series matches the number of series returned.
+	Series *config.Matcher `yaml:"series"`
+}
+
+// decodeJob decodes the job's settings and assertions and loads its query.
+func decodeJob(job config.Job) (JobSpec, Assertions, error) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	if len(errs) > 0 {
+		return spec, assertions, fmt.Errorf("invalid spec: %v", errs)
+	}
+
+	query, err := spec.Load(job.Dir)
+	if err != nil {
+		return spec, assertions, err
+	}
+	spec.Query = query
+	return spec, assertions, nil
+}
+
+// validAggregations lists the ways a range query reduces each series to a
+// single value.
+var validAggregations = map[string]bool{
+	"last": true,
+	"min":  true,
+	"max":  true,
+	"avg":  true,
+	"sum":  true,
+}
+
+// ValidateEnvironment validates a Prometheus environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.RequireURL(env)...)
+}
+
+// ValidateJob validates a Prometheus job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	errs = append(errs, spec.QuerySpec.Validate(job, "prometheus")...)
+
+	if spec.Range < 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "range",
+			Message: "must not be negative",
+		})
+	}
+
+	if spec.Step != 0 && spec.Range == 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "step",
+			Message: "requires range",
+		})
+	} else if spec.Step < 0 || spec.Step > spec.Range {
+		errs = append(errs, config.ValidationError{
+			Field:   "step",
+			Message: "must be between 0 and range",
+		})
+	}
+
+	if spec.Aggregation != "" && !validAggregations[spec.Aggregation] {
+		errs = append(errs, config.ValidationError{
+			Field:   "aggregation",
+			Message: fmt.Sprintf("invalid aggregation '%s', must be one of: last, min, max, avg, sum", spec.Aggregation),
+		})
+	}
+
+	if assertions.Value != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.Value, "assertions.value")...)
+	}
+	if assertions.Series != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.Series, "assertions.series")...)
+	}
+
+	return errs
+}
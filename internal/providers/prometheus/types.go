@@ -0,0 +1,75 @@
+// Package prometheus provides a provider that runs PromQL queries against a
+// Prometheus-compatible HTTP API and asserts on the returned values.
+package prometheus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Result types returned by the query API.
+const (
+	ResultTypeVector = "vector"
+	ResultTypeMatrix = "matrix"
+	ResultTypeScalar = "scalar"
+	ResultTypeString = "string"
+)
+
+// Response is the envelope of every query API response.
+type Response struct {
+	Status    string   `json:"status"`
+	Data      Data     `json:"data"`
+	ErrorType string   `json:"errorType"`
+	Error     string   `json:"error"`
+	Warnings  []string `json:"warnings"`
+}
+
+// Data holds a query result. Result is decoded according to ResultType.
+type Data struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// Sample is a [timestamp, "value"] pair.
+type Sample [2]any
+
+// Value parses the sample value. Prometheus encodes values as strings so
+// that NaN and infinities survive JSON.
+func (s Sample) Value() (float64, error) {
+	str, ok := s[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", s[1])
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// Series is a single labelled series of a vector or matrix result. Vector
+// results set Value; matrix results set Values.
+type Series struct {
+	Metric map[string]string `json:"metric"`
+	Value  Sample            `json:"value"`
+	Values []Sample          `json:"values"`
+}
+
+// Labels formats the series labels as {a="b", c="d"}, with __name__ first
+// like Prometheus does.
+func (s Series) Labels() string {
+	name := s.Metric["__name__"]
+
+	keys := make([]string, 0, len(s.Metric))
+	for k := range s.Metric {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%q", k, s.Metric[k])
+	}
+	return name + "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -18,21 +18,30 @@ const (
 	StatusFailed    Status = "failed"
 	StatusAborted   Status = "aborted"
 	StatusTimedOut  Status = "timed_out"
+
+	// StatusPreflightPassed means a dry run checked the job's target
+	// without running the job.
+	StatusPreflightPassed Status = "preflight_passed"
+
+	// StatusSkipped means a dry run could not check the job because its
+	// provider has no preflight.
+	StatusSkipped Status = "skipped"
 )
 
 // IsTerminal returns true if the status is a terminal state.
 func (s Status) IsTerminal() bool {
 	switch s {
-	case StatusSucceeded, StatusFailed, StatusAborted, StatusTimedOut:
+	case StatusSucceeded, StatusFailed, StatusAborted, StatusTimedOut, StatusPreflightPassed, StatusSkipped:
 		return true
 	default:
 		return false
 	}
 }
 
-// IsSuccess returns true if the status indicates success.
+// IsSuccess returns true if the status indicates success. A passed
+// preflight counts as success.
 func (s Status) IsSuccess() bool {
-	return s == StatusSucceeded
+	return s == StatusSucceeded || s == StatusPreflightPassed
 }
 
 // Result represents the result of a job execution.
@@ -46,6 +55,11 @@ type Result struct {
 	Duration    time.Duration          `json:"duration_ms"`
 	Error       string                 `json:"error,omitempty"`
 	Details     map[string]interface{} `json:"details,omitempty"`
+
+	// RemoteRunning is set while an execution the provider started on a
+	// remote system may still be running. The executor asks an Aborter to
+	// stop it when Execute returns with it set.
+	RemoteRunning bool `json:"-"`
 }
 
 // Passed returns true if the job execution passed.
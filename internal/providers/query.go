@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// QuerySpec holds a query written inline or kept in a file, as used by
+// graphql, sql and prometheus jobs. Providers embed it inline in their job
+// spec.
+type QuerySpec struct {
+	// Query is the query text.
+	Query string `yaml:"query"`
+
+	// QueryFile is a file holding the query, relative to the file that
+	// defines the job.
+	QueryFile string `yaml:"query_file"`
+}
+
+// Load returns the query, reading QueryFile relative to dir if it is set.
+func (q QuerySpec) Load(dir string) (string, error) {
+	if q.QueryFile == "" {
+		return q.Query, nil
+	}
+
+	path := q.QueryFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read query_file: %w", err)
+	}
+	return string(data), nil
+}
+
+// Validate checks that the job sets exactly one of query and query_file and
+// that the query can be read. kind names the job type in messages.
+func (q QuerySpec) Validate(job config.Job, kind string) config.ValidationErrors {
+	if q.Query != "" && q.QueryFile != "" {
+		return config.ValidationErrors{{
+			Field:   "query_file",
+			Message: "cannot be used together with query",
+		}}
+	}
+
+	query, err := q.Load(job.Dir)
+	if err != nil {
+		return config.ValidationErrors{{Field: "query_file", Message: err.Error()}}
+	}
+	if strings.TrimSpace(query) == "" {
+		return config.ValidationErrors{{
+			Field:   "query",
+			Message: fmt.Sprintf("is required for %s jobs (inline or via query_file)", kind),
+		}}
+	}
+	return nil
+}
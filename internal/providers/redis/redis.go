@@ -0,0 +1,247 @@
+// Package redis provides a provider that checks Redis liveness and the
+// existence, expiry and size of keys.
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// defaultTimeout is used when neither the job nor the defaults set one.
+const defaultTimeout = 10 * time.Second
+
+// Provider implements the Redis provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new Redis provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "redis"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Execute pings the server and, when the job names a key, checks that it
+// exists and asserts on its TTL and length.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "redis",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     map[string]interface{}{},
+	}
+
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
+
+	opts, err := clientOptions(env)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+	result.Details["addr"] = opts.Addr
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := goredis.NewClient(opts)
+	defer client.Close()
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Pinging %s...", opts.Addr))
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return p.fail(result, fmt.Sprintf("ping failed: %v", timeoutError(err, timeout))), nil
+	}
+
+	var failures []string
+
+	if spec.Key != "" {
+		result.Details["key"] = spec.Key
+		failures, err = checkKey(ctx, client, spec.Key, assertions, result.Details)
+		if err != nil {
+			return p.fail(result, timeoutError(err, timeout).Error()), nil
+		}
+	}
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, "PONG")
+
+	return result, nil
+}
+
+// checkKey runs the key assertions and records the key's type, TTL and
+// length in details. The key must exist unless exists is set to false.
+func checkKey(ctx context.Context, client *goredis.Client, key string, a Assertions, details map[string]interface{}) ([]string, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("TYPE failed: %w", err)
+	}
+
+	exists := keyType != "none"
+	details["exists"] = exists
+
+	wantExists := true
+	if a.Exists != nil {
+		wantExists = *a.Exists
+	}
+	if exists != wantExists {
+		if wantExists {
+			return []string{fmt.Sprintf("key %s does not exist", key)}, nil
+		}
+		return []string{fmt.Sprintf("key %s exists", key)}, nil
+	}
+	if !exists {
+		return nil, nil
+	}
+	details["key_type"] = keyType
+
+	var failures []string
+
+	ttl, err := client.TTL(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("TTL failed: %w", err)
+	}
+	// go-redis returns -1 unscaled for keys without an expiry.
+	seconds := int64(-1)
+	if ttl >= 0 {
+		seconds = int64(ttl / time.Second)
+	}
+	details["ttl"] = seconds
+	if a.TTL != nil {
+		if err := assertion.Evaluate(seconds, nil, *a.TTL); err != nil {
+			failures = append(failures, fmt.Sprintf("ttl: %v", err))
+		}
+	}
+
+	length, err := keyLength(ctx, client, key, keyType)
+	if err != nil {
+		return nil, err
+	}
+	details["length"] = length
+	if a.Length != nil {
+		if err := assertion.Evaluate(length, nil, *a.Length); err != nil {
+			failures = append(failures, fmt.Sprintf("length: %v", err))
+		}
+	}
+
+	return failures, nil
+}
+
+// keyLength returns the size of a key using the command for its type: the
+// number of elements of a collection or the byte length of a string.
+func keyLength(ctx context.Context, client *goredis.Client, key, keyType string) (int64, error) {
+	var cmd *goredis.IntCmd
+	switch keyType {
+	case "string":
+		cmd = client.StrLen(ctx, key)
+	case "list":
+		cmd = client.LLen(ctx, key)
+	case "set":
+		cmd = client.SCard(ctx, key)
+	case "zset":
+		cmd = client.ZCard(ctx, key)
+	case "hash":
+		cmd = client.HLen(ctx, key)
+	case "stream":
+		cmd = client.XLen(ctx, key)
+	default:
+		return 0, fmt.Errorf("unsupported key type %q", keyType)
+	}
+
+	n, err := cmd.Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read length of %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// clientOptions builds client options from a redis://, rediss:// or unix://
+// URL. Basic auth credentials override those in the URL, and rediss:// uses
+// the environment's TLS settings.
+func clientOptions(env config.Environment) (*goredis.Options, error) {
+	opts, err := goredis.ParseURL(env.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if env.Auth.Type == "basic" {
+		opts.Username = env.Auth.Username
+		opts.Password = env.Auth.Password
+	}
+
+	if opts.TLSConfig != nil {
+		tlsConfig, err := providers.NewTLSConfig(env.TLS)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = opts.TLSConfig.ServerName
+		}
+		opts.TLSConfig = tlsConfig
+	}
+
+	return opts, nil
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
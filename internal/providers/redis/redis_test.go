@@ -0,0 +1,170 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+func TestExecute(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.Lpush("jobs:pending", "a")
+	server.Lpush("jobs:pending", "b")
+	server.Lpush("jobs:pending", "c")
+	server.Set("lock:nightly", "worker-1")
+	server.SetTTL("lock:nightly", 90*time.Second)
+	server.HSet("stats", "processed", "10")
+
+	tests := []struct {
+		name       string
+		key        string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "ping",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "key exists",
+			key:        "stats",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "key missing",
+			key:        "jobs:failed",
+			wantStatus: providers.StatusFailed,
+			wantError:  "key jobs:failed does not exist",
+		},
+		{
+			name:       "key expected absent",
+			key:        "jobs:failed",
+			assertions: config.Assertions{Settings: map[string]any{"exists": false}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "list length",
+			key:        "jobs:pending",
+			assertions: config.Assertions{Settings: map[string]any{"length": map[string]any{"less_than": 5}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "list too long",
+			key:        "jobs:pending",
+			assertions: config.Assertions{Settings: map[string]any{"length": map[string]any{"less_than": 2}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "length: expected less than 2, got 3",
+		},
+		{
+			name:       "ttl",
+			key:        "lock:nightly",
+			assertions: config.Assertions{Settings: map[string]any{"ttl": map[string]any{"greater_than": 60}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "no expiry",
+			key:        "stats",
+			assertions: config.Assertions{Settings: map[string]any{"ttl": map[string]any{"greater_than": 0}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "ttl: expected greater than 0, got -1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "queue",
+				Type:       "redis",
+				Spec:       map[string]any{"key": tt.key},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+			env := config.Environment{Type: "redis", URL: "redis://" + server.Addr()}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteAuth(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireUserAuth("probe", "secret")
+
+	job := config.Job{Name: "ping", Type: "redis", Timeout: 5 * time.Second}
+
+	env := config.Environment{Type: "redis", URL: "redis://" + server.Addr()}
+	result, _ := NewProvider().Execute(context.Background(), job, env)
+	if result.Status != providers.StatusFailed {
+		t.Errorf("Status without credentials = %s, want failed", result.Status)
+	}
+
+	env.Auth = config.Auth{Type: "basic", Username: "probe", Password: "secret"}
+	result, _ = NewProvider().Execute(context.Background(), job, env)
+	if result.Status != providers.StatusSucceeded {
+		t.Errorf("Status = %s, want succeeded (error: %s)", result.Status, result.Error)
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "ping",
+			job:  config.Job{},
+		},
+		{
+			name: "key assertions",
+			job: config.Job{
+				Settings:   map[string]any{"key": "jobs:pending"},
+				Assertions: config.Assertions{Settings: map[string]any{"exists": true, "length": map[string]any{"less_than": 5}}},
+			},
+		},
+		{
+			name: "assertions without key",
+			job:  config.Job{Assertions: config.Assertions{Settings: map[string]any{"length": map[string]any{"equals": 0}}}},
+			want: []string{"assertions.length: requires key"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "redis", URL: "redis://cache:6379"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	env := config.Environment{URL: "http://cache:6379"}
+	if errs := NewProvider().ValidateEnvironment(env); !strings.Contains(errs.Error(), "url: must start with redis://") {
+		t.Errorf("ValidateEnvironment() = %v, want url scheme error", errs)
+	}
+}
@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a redis job.
+type JobSpec struct {
+	// Key is checked after the ping. Empty only pings the server.
+	Key string `yaml:"key"`
+}
+
+// Assertions holds the Redis-specific assertions of a job. They require a key.
+type Assertions struct {
+	// Exists is whether the key should exist. Unset expects it to.
+	Exists *bool `yaml:"exists"`
+
+	// Length matches the size of the key's value.
+	Length *config.Matcher `yaml:"length"`
+
+	// TTL matches the key's TTL in seconds, -1 if it has no expiry.
+	TTL *config.Matcher `yaml:"ttl"`
+}
+
+// decodeJob decodes the job's settings and assertions.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	return spec, assertions, errs
+}
+
+// ValidateEnvironment validates a Redis environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	errs = append(errs, providers.RequireURL(env)...)
+	errs = append(errs, providers.ValidateURLScheme(env, "redis://", "rediss://", "unix://")...)
+	return append(errs, providers.ValidateAuthType(env, "redis", "basic")...)
+}
+
+// ValidateJob validates a Redis job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.Key == "" {
+		keyAssertions := []struct {
+			name string
+			set  bool
+		}{
+			{"exists", assertions.Exists != nil},
+			{"length", assertions.Length != nil},
+			{"ttl", assertions.TTL != nil},
+		}
+		for _, a := range keyAssertions {
+			if a.set {
+				errs = append(errs, config.ValidationError{
+					Field:   "assertions." + a.name,
+					Message: "requires key",
+				})
+			}
+		}
+	}
+
+	if assertions.Length != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.Length, "assertions.length")...)
+	}
+	if assertions.TTL != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.TTL, "assertions.ttl")...)
+	}
+
+	return errs
+}
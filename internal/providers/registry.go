@@ -3,6 +3,8 @@ package providers
 import (
 	"fmt"
 	"sync"
+
+	"github.com/user/jobprobe/internal/config"
 )
 
 // Registry manages provider instances.
@@ -18,11 +20,16 @@ func NewRegistry() *Registry {
 	}
 }
 
-// Register registers a provider with the registry.
+// Register registers a provider with the registry. Providers that implement
+// config.TypeValidator also make their type valid in config.Validate.
 func (r *Registry) Register(provider Provider) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.providers[provider.Name()] = provider
+
+	if v, ok := provider.(config.TypeValidator); ok {
+		config.RegisterType(provider.Name(), v)
+	}
 }
 
 // Get returns a provider by name.
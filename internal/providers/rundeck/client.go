@@ -7,9 +7,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
 )
 
 // Client is a Rundeck API client.
@@ -17,24 +23,112 @@ type Client struct {
 	baseURL    string
 	apiVersion int
 	token      string
+	headers    map[string]string
 	httpClient *http.Client
+	limiter    *limiter
 }
 
-// NewClient creates a new Rundeck client.
-func NewClient(env config.Environment) *Client {
+const (
+	// DefaultAPIVersion is used until the server's version is detected, and
+	// when it is not.
+	DefaultAPIVersion = 41
+
+	// MinAPIVersion is the oldest API version the client supports. Server
+	// details are requested at it before the server's version is known.
+	MinAPIVersion = 14
+)
+
+// NewClient creates a new Rundeck client. If httpClient is nil, a dedicated
+// client is created; otherwise it is shared.
+func NewClient(env config.Environment, httpClient *http.Client) *Client {
 	apiVersion := env.APIVersion
 	if apiVersion == 0 {
-		apiVersion = 41
+		apiVersion = DefaultAPIVersion
+	}
+
+	if httpClient == nil {
+		httpClient = providers.NewHTTPClient(env.Transport)
 	}
 
 	return &Client{
 		baseURL:    env.URL,
 		apiVersion: apiVersion,
 		token:      env.Auth.Token,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		headers:    env.Headers,
+		httpClient: httpClient,
+	}
+}
+
+// Login starts a session through the web login form, for servers that are
+// used with a username and password rather than an API token. Later
+// requests send the session cookie.
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	session := *c.httpClient
+	session.Jar = jar
+	c.httpClient = &session
+
+	form := url.Values{"j_username": {username}, "j_password": {password}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/j_security_check", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	// Rundeck redirects to the home page on success and back to the login
+	// or error page on failure.
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("login request failed with status %d", resp.StatusCode)
+	}
+	if path := resp.Request.URL.Path; strings.HasPrefix(path, "/user/login") || strings.HasPrefix(path, "/user/error") {
+		return fmt.Errorf("login rejected for user '%s'", username)
 	}
+
+	return nil
+}
+
+// SystemInfo retrieves the server's version and the newest API version it
+// supports. It is requested at MinAPIVersion, which every server accepts.
+func (c *Client) SystemInfo(ctx context.Context) (*SystemInfo, error) {
+	url := fmt.Sprintf("%s/api/%d/system/info", c.baseURL, MinAPIVersion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result SystemInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result.System, nil
 }
 
 // RunJob triggers a Rundeck job execution.
@@ -61,7 +155,7 @@ func (c *Client) RunJob(ctx context.Context, jobID string, options map[string]st
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -90,7 +184,7 @@ func (c *Client) GetExecution(ctx context.Context, executionID int) (*ExecutionR
 
 	c.setHeaders(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -108,10 +202,194 @@ func (c *Client) GetExecution(ctx context.Context, executionID int) (*ExecutionR
 	return &result, nil
 }
 
-// setHeaders sets the required headers for Rundeck API requests.
+// ListRunningExecutions lists the job's executions that are still running,
+// newest first.
+func (c *Client) ListRunningExecutions(ctx context.Context, jobID string) ([]ExecutionResponse, error) {
+	return c.listExecutions(ctx, fmt.Sprintf("/job/%s/executions", jobID), url.Values{"status": {"running"}})
+}
+
+// ListExecutions lists up to max of the job's most recent executions, newest
+// first.
+func (c *Client) ListExecutions(ctx context.Context, jobID string, max int) ([]ExecutionResponse, error) {
+	return c.listExecutions(ctx, fmt.Sprintf("/job/%s/executions", jobID), url.Values{"max": {strconv.Itoa(max)}})
+}
+
+// listExecutions fetches an executions listing endpoint.
+func (c *Client) listExecutions(ctx context.Context, path string, query url.Values) ([]ExecutionResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/%d%s?%s", c.baseURL, c.apiVersion, path, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result ExecutionList
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Executions, nil
+}
+
+// ListProjectRunningExecutions lists the running executions of every job in
+// a project, or in all projects when project is "*".
+func (c *Client) ListProjectRunningExecutions(ctx context.Context, project string) ([]ExecutionResponse, error) {
+	return c.listExecutions(ctx, fmt.Sprintf("/project/%s/executions/running", project),
+		url.Values{"max": {strconv.Itoa(maxRunningExecutions)}})
+}
+
+// maxRunningExecutions is the page size used to list running executions.
+// Executions beyond it are fetched individually.
+const maxRunningExecutions = 500
+
+// GetJobInfo retrieves a job's metadata. It fails if the job does not exist.
+func (c *Client) GetJobInfo(ctx context.Context, jobID string) (*JobInfo, error) {
+	url := fmt.Sprintf("%s/api/%d/job/%s/info", c.baseURL, c.apiVersion, jobID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result JobInfo
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// GetJobDefinition retrieves a job's definition. The YAML export is used
+// because it is available in every supported API version.
+func (c *Client) GetJobDefinition(ctx context.Context, jobID string) (*JobDefinition, error) {
+	url := fmt.Sprintf("%s/api/%d/job/%s?format=yaml", c.baseURL, c.apiVersion, jobID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+	req.Header.Set("Accept", "application/yaml")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result []JobDefinition
+	if err := yaml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode job definition: %w", err)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("rundeck returned no definition for job %s", jobID)
+	}
+
+	return &result[0], nil
+}
+
+// GetExecutionOutput retrieves up to maxLines log entries of an execution,
+// starting at offset.
+func (c *Client) GetExecutionOutput(ctx context.Context, executionID int, offset string, maxLines int) (*ExecutionOutput, error) {
+	query := url.Values{"offset": {offset}, "maxlines": {strconv.Itoa(maxLines)}}
+	endpoint := fmt.Sprintf("%s/api/%d/execution/%d/output?%s", c.baseURL, c.apiVersion, executionID, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.parseError(resp)
+	}
+
+	var result ExecutionOutput
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// AbortExecution asks Rundeck to abort a running execution.
+func (c *Client) AbortExecution(ctx context.Context, executionID int) error {
+	url := fmt.Sprintf("%s/api/%d/execution/%d/abort", c.baseURL, c.apiVersion, executionID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.parseError(resp)
+	}
+
+	return nil
+}
+
+// do sends a request once the client's rate limit allows it.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if err := c.limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(req)
+}
+
+// setHeaders sets the environment's headers and the required headers for
+// Rundeck API requests. The token is left out for session logins.
 func (c *Client) setHeaders(req *http.Request) {
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Rundeck-Auth-Token", c.token)
+	if c.token != "" {
+		req.Header.Set("X-Rundeck-Auth-Token", c.token)
+	}
 }
 
 // parseError parses an error response from Rundeck.
@@ -0,0 +1,185 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// logPageLines is the number of log entries requested per page.
+	logPageLines = 1000
+
+	// maxLogEntries caps the entries read for log assertions.
+	maxLogEntries = 50000
+
+	// logRetries and logRetryDelay bound the wait for a finished
+	// execution's log to be fully written.
+	logRetries    = 10
+	logRetryDelay = 500 * time.Millisecond
+)
+
+// checkLog applies the job's log assertions to a succeeded execution, so an
+// execution that finished with the wrong output fails the job.
+func (p *Provider) checkLog(ctx context.Context, client *Client, job config.Job, log []LogAssertion, executionID int, result *providers.Result) {
+	if len(log) == 0 || result.Status != providers.StatusSucceeded {
+		return
+	}
+
+	p.reportProgress(job.Name, providers.StatusRunning, "Reading execution log...")
+
+	entries, truncated, err := readLog(ctx, client, executionID)
+	if err != nil {
+		result.Status = providers.StatusFailed
+		appendError(result, fmt.Sprintf("failed to read execution log: %v", err))
+		return
+	}
+	result.Details["log_entries"] = len(entries)
+	if truncated {
+		result.Details["log_truncated"] = true
+	}
+
+	for _, a := range log {
+		for _, msg := range checkLines(logLines(entries, a), a) {
+			result.Status = providers.StatusFailed
+			appendError(result, fmt.Sprintf("%s: %s", logScope(a), msg))
+		}
+	}
+}
+
+// readLog reads an execution's log entries page by page. It reports whether
+// the log was cut off at maxLogEntries.
+func readLog(ctx context.Context, client *Client, executionID int) ([]LogEntry, bool, error) {
+	var entries []LogEntry
+	offset := "0"
+	retries := 0
+
+	for {
+		out, err := client.GetExecutionOutput(ctx, executionID, offset, logPageLines)
+		if err != nil {
+			return nil, false, err
+		}
+		entries = append(entries, out.Entries...)
+
+		if len(entries) >= maxLogEntries {
+			return entries[:maxLogEntries], true, nil
+		}
+		if out.Completed {
+			return entries, false, nil
+		}
+
+		// The execution has finished but its log may still be flushing.
+		if len(out.Entries) == 0 {
+			retries++
+			if retries > logRetries {
+				return nil, false, fmt.Errorf("log still incomplete after %d attempts", logRetries)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			case <-time.After(logRetryDelay):
+			}
+		}
+		if out.Offset != "" {
+			offset = out.Offset.String()
+		}
+	}
+}
+
+// checkLines evaluates a log assertion against the lines of its step or
+// node.
+func checkLines(lines []string, a LogAssertion) []string {
+	var errors []string
+
+	if a.Matches != "" {
+		re, err := regexp.Compile(a.Matches)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern %q: %v", a.Matches, err))
+		} else if !anyMatch(re, lines) {
+			errors = append(errors, fmt.Sprintf("no line matches %q", a.Matches))
+		}
+	}
+
+	if a.NotMatches != "" {
+		re, err := regexp.Compile(a.NotMatches)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("invalid pattern %q: %v", a.NotMatches, err))
+		} else {
+			for i, line := range lines {
+				if re.MatchString(line) {
+					errors = append(errors, fmt.Sprintf("line %d matches %q: %s", i+1, a.NotMatches, line))
+					break
+				}
+			}
+		}
+	}
+
+	if a.Lines != nil {
+		if err := assertion.Evaluate(len(lines), nil, *a.Lines); err != nil {
+			errors = append(errors, fmt.Sprintf("line count: %v", err))
+		}
+	}
+
+	return errors
+}
+
+// anyMatch reports whether re matches any of lines.
+func anyMatch(re *regexp.Regexp, lines []string) bool {
+	for _, line := range lines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// logLines returns the lines of the log entries in the assertion's step and
+// node, or of all entries when neither is set.
+func logLines(entries []LogEntry, a LogAssertion) []string {
+	var lines []string
+	for _, entry := range entries {
+		if entry.Type != "" && entry.Type != "log" {
+			continue
+		}
+		if a.Node != "" && entry.Node != a.Node {
+			continue
+		}
+		if a.Step != "" && !inStep(entry.StepCtx, a.Step) {
+			continue
+		}
+		lines = append(lines, strings.Split(entry.Log, "\n")...)
+	}
+	return lines
+}
+
+// inStep reports whether a step context is the step or one of its
+// sub-steps. Node parameters in the context, as in 2@node=web1/1, are
+// ignored.
+func inStep(stepCtx, step string) bool {
+	parts := strings.Split(stepCtx, "/")
+	for i, part := range parts {
+		parts[i], _, _ = strings.Cut(part, "@")
+	}
+	ctx := strings.Join(parts, "/")
+	return ctx == step || strings.HasPrefix(ctx, step+"/")
+}
+
+// logScope describes the part of the log an assertion applies to.
+func logScope(a LogAssertion) string {
+	switch {
+	case a.Step != "" && a.Node != "":
+		return fmt.Sprintf("log (step %s on %s)", a.Step, a.Node)
+	case a.Step != "":
+		return fmt.Sprintf("log (step %s)", a.Step)
+	case a.Node != "":
+		return fmt.Sprintf("log (node %s)", a.Node)
+	default:
+		return "log"
+	}
+}
@@ -0,0 +1,52 @@
+package rundeck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestCheckLines(t *testing.T) {
+	lines := []string{"starting export", "1520 rows exported", "done"}
+	five := 5.0
+
+	tests := []struct {
+		name      string
+		assertion LogAssertion
+		want      []string
+	}{
+		{
+			name:      "matches",
+			assertion: LogAssertion{Matches: `[1-9][0-9]* rows exported`},
+		},
+		{
+			name:      "no match",
+			assertion: LogAssertion{Matches: `^uploaded`},
+			want:      []string{`no line matches "^uploaded"`},
+		},
+		{
+			name:      "not matches",
+			assertion: LogAssertion{NotMatches: `(?i)error`},
+		},
+		{
+			name:      "forbidden line",
+			assertion: LogAssertion{NotMatches: `rows exported`},
+			want:      []string{`line 2 matches "rows exported": 1520 rows exported`},
+		},
+		{
+			name:      "line count",
+			assertion: LogAssertion{Lines: &config.Matcher{GreaterThan: &five}},
+			want:      []string{"line count: expected greater than 5, got 3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkLines(lines, tt.assertion)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("checkLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
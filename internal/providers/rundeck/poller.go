@@ -0,0 +1,199 @@
+package rundeck
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// batchWindow is how long a listing of running executions answers
+	// status queries before it is fetched again.
+	batchWindow = 2 * time.Second
+
+	// minPoll is the first wait between polls once an execution is past
+	// its expected duration.
+	minPoll = time.Second
+
+	// maxEarlyWait caps the wait while an execution is well short of its
+	// job's average duration.
+	maxEarlyWait = 5 * time.Minute
+
+	// allProjectsVersion is the first API version that lists running
+	// executions across all projects.
+	allProjectsVersion = 35
+)
+
+// poller answers execution status queries for one environment. While
+// several executions in the same listing scope are polled, running
+// executions are read from one listing of the server's running executions,
+// shared by every job polling within batchWindow of each other; an
+// execution missing from the listing has finished and is fetched on its own.
+// A lone execution is always fetched on its own, since a listing would only
+// cost an extra request.
+type poller struct {
+	client *Client
+
+	mu        sync.Mutex
+	snapshots map[string]*snapshot
+	tracked   map[string]int
+}
+
+// snapshot is a listing of running executions.
+type snapshot struct {
+	taken      time.Time
+	executions map[int]ExecutionResponse
+}
+
+// newPoller creates a poller that queries through client.
+func newPoller(client *Client) *poller {
+	return &poller{
+		client:    client,
+		snapshots: make(map[string]*snapshot),
+		tracked:   make(map[string]int),
+	}
+}
+
+// scope returns the listing that covers executions in project.
+func (p *poller) scope(project string) string {
+	if p.client.apiVersion >= allProjectsVersion {
+		return "*"
+	}
+	return project
+}
+
+// track records that an execution in project is being polled, until the
+// returned function is called.
+func (p *poller) track(project string) (untrack func()) {
+	scope := p.scope(project)
+
+	p.mu.Lock()
+	p.tracked[scope]++
+	p.mu.Unlock()
+
+	return func() {
+		p.mu.Lock()
+		p.tracked[scope]--
+		p.mu.Unlock()
+	}
+}
+
+// status returns the current state of an execution in project. since is
+// when the caller last polled it, or started tracking it; only listings
+// taken after then are used, so each poll sees newer state than the last.
+func (p *poller) status(ctx context.Context, project string, executionID int, since time.Time) (*ExecutionResponse, error) {
+	scope := p.scope(project)
+
+	p.mu.Lock()
+	shared := p.tracked[scope] > 1
+	p.mu.Unlock()
+
+	if shared {
+		running, err := p.running(ctx, scope, since)
+		if err != nil {
+			return nil, err
+		}
+		if exec, ok := running[executionID]; ok {
+			return &exec, nil
+		}
+	}
+	return p.client.GetExecution(ctx, executionID)
+}
+
+// running returns the running executions in scope, listing them again if
+// the last listing is too old. Callers wait for a listing in progress and
+// share it.
+func (p *poller) running(ctx context.Context, scope string, since time.Time) (map[int]ExecutionResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s, ok := p.snapshots[scope]; ok && s.taken.After(since) && time.Since(s.taken) < batchWindow {
+		return s.executions, nil
+	}
+
+	taken := time.Now()
+	list, err := p.client.ListProjectRunningExecutions(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	executions := make(map[int]ExecutionResponse, len(list))
+	for _, exec := range list {
+		executions[exec.ID] = exec
+	}
+	p.snapshots[scope] = &snapshot{taken: taken, executions: executions}
+	return executions, nil
+}
+
+// backoff decides how long to wait between polls of one execution. While
+// the execution is well short of its job's average duration, it waits for
+// half the remaining expected time; after that it starts at minPoll and
+// doubles up to the poll interval.
+type backoff struct {
+	interval time.Duration
+	average  time.Duration
+	current  time.Duration
+}
+
+// next returns the wait before the next poll of an execution that has been
+// running for elapsed.
+func (b *backoff) next(elapsed time.Duration) time.Duration {
+	if remaining := b.average - elapsed; remaining > 2*b.interval {
+		return min(remaining/2, max(maxEarlyWait, b.interval))
+	}
+
+	if b.current == 0 {
+		b.current = min(minPoll, b.interval)
+	} else {
+		b.current = min(2*b.current, b.interval)
+	}
+	return b.current
+}
+
+// limiter spaces requests to a server at least interval apart. A nil
+// limiter does not limit.
+type limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newLimiter creates a limiter for the given rate. A rate of zero or less
+// does not limit.
+func newLimiter(perSecond float64) *limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &limiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait blocks until the next request may be sent.
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,186 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		average time.Duration
+		elapsed []time.Duration
+		want    []time.Duration
+	}{
+		{
+			name:    "no average",
+			elapsed: []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second, 15 * time.Second, 25 * time.Second},
+			want:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second},
+		},
+		{
+			name:    "long average",
+			average: 30 * time.Minute,
+			elapsed: []time.Duration{0, 20 * time.Minute, 29 * time.Minute, 29*time.Minute + 50*time.Second, 31 * time.Minute},
+			want:    []time.Duration{5 * time.Minute, 5 * time.Minute, 30 * time.Second, time.Second, 2 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &backoff{interval: 10 * time.Second, average: tt.average}
+			for i, elapsed := range tt.elapsed {
+				if got := b.next(elapsed); got != tt.want[i] {
+					t.Errorf("next(%s) = %s, want %s", elapsed, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPollerBatchesRunningExecutions(t *testing.T) {
+	tests := []struct {
+		apiVersion int
+		listing    string
+	}{
+		{apiVersion: 41, listing: "GET /api/41/project/*/executions/running"},
+		{apiVersion: 30, listing: "GET /api/30/project/ops/executions/running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("v%d", tt.apiVersion), func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				mu.Unlock()
+
+				if strings.HasSuffix(r.URL.Path, "/executions/running") {
+					fmt.Fprint(w, `{"executions":[{"id":1,"status":"running"},{"id":2,"status":"running"}]}`)
+					return
+				}
+				fmt.Fprint(w, `{"id":3,"status":"succeeded"}`)
+			}))
+			defer server.Close()
+
+			p := newPoller(NewClient(config.Environment{URL: server.URL, APIVersion: tt.apiVersion}, nil))
+			since := time.Now()
+
+			var wg sync.WaitGroup
+			statuses := make([]ExecutionStatus, 3)
+			for i := range statuses {
+				defer p.track("ops")()
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					exec, err := p.status(context.Background(), "ops", i+1, since)
+					if err != nil {
+						t.Errorf("status(%d) error = %v", i+1, err)
+						return
+					}
+					statuses[i] = exec.Status
+				}(i)
+			}
+			wg.Wait()
+
+			if fmt.Sprint(statuses) != "[running running succeeded]" {
+				t.Errorf("statuses = %v", statuses)
+			}
+			sort.Strings(requests)
+			want := []string{fmt.Sprintf("GET /api/%d/execution/3", tt.apiVersion), tt.listing}
+			if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+				t.Errorf("requests = %v, want %v", requests, want)
+			}
+		})
+	}
+}
+
+func TestPollExecutionSeesNewerListings(t *testing.T) {
+	listings := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/executions/running") {
+			fmt.Fprint(w, `{"id":1,"status":"succeeded"}`)
+			return
+		}
+		listings++
+		if listings == 1 {
+			fmt.Fprint(w, `{"executions":[{"id":1,"status":"running"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"executions":[]}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider()
+	poller := newPoller(NewClient(config.Environment{URL: server.URL}, nil))
+	job := config.Job{Name: "export", Timeout: 5 * time.Second, PollInterval: 10 * time.Millisecond}
+	b := &backoff{interval: job.PollInterval}
+
+	// Another execution polled alongside makes the listing worth using.
+	defer poller.track("ops")()
+
+	// Each poll must list again rather than reuse the listing of the one
+	// before, or the execution looks running until batchWindow ends.
+	start := time.Now()
+	exec, err := p.pollExecution(context.Background(), poller, "ops", 1, start, b, job, config.Defaults{})
+	if err != nil || exec.Status != ExecutionStatusSucceeded {
+		t.Fatalf("pollExecution() = %v, %v", exec, err)
+	}
+	if elapsed := time.Since(start); elapsed >= batchWindow {
+		t.Errorf("pollExecution() took %s, want less than %s", elapsed, batchWindow)
+	}
+	if listings != 2 {
+		t.Errorf("listings = %d, want 2", listings)
+	}
+}
+
+func TestPollerSkipsListingForOneExecution(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		fmt.Fprint(w, `{"id":1,"status":"running"}`)
+	}))
+	defer server.Close()
+
+	p := newPoller(NewClient(config.Environment{URL: server.URL}, nil))
+	defer p.track("ops")()
+
+	if _, err := p.status(context.Background(), "ops", 1, time.Now()); err != nil {
+		t.Fatalf("status() error = %v", err)
+	}
+	if want := []string{"GET /api/41/execution/1"}; strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(50)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("4 requests at 50/s took %s, want at least 60ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); err == nil {
+		t.Error("wait() on a cancelled context returned nil")
+	}
+
+	if newLimiter(0) != nil {
+		t.Error("newLimiter(0) should not limit")
+	}
+}
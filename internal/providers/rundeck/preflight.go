@@ -0,0 +1,151 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// Preflight checks that the job exists in the configured project and that
+// the configured options satisfy the job's option definitions, without
+// running the job.
+func (p *Provider) Preflight(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "rundeck",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, _, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return finishPreflight(result, []string{fmt.Sprintf("invalid spec: %v", errs)}), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, job.GetTimeout(config.Defaults{Timeout: 10 * time.Minute}))
+	defer cancel()
+
+	srv, err := p.server(job.Environment, env)
+	if err != nil {
+		return finishPreflight(result, []string{err.Error()}), nil
+	}
+	client := srv.client
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Checking job %s...", spec.JobID))
+
+	info, err := client.GetJobInfo(ctx, spec.JobID)
+	if err != nil {
+		return finishPreflight(result, []string{fmt.Sprintf("failed to look up job: %v", err)}), nil
+	}
+	result.Details["job_name"] = info.Name
+	result.Details["project"] = info.Project
+	if info.Group != "" {
+		result.Details["job_group"] = info.Group
+	}
+
+	var failures []string
+	if spec.Project != "" && info.Project != spec.Project {
+		failures = append(failures, fmt.Sprintf("job belongs to project '%s', not '%s'", info.Project, spec.Project))
+	}
+
+	definition, err := client.GetJobDefinition(ctx, spec.JobID)
+	if err != nil {
+		failures = append(failures, fmt.Sprintf("failed to get job definition: %v", err))
+		return finishPreflight(result, failures), nil
+	}
+	result.Details["options_checked"] = len(definition.Options)
+
+	failures = append(failures, checkOptions(definition.Options, spec.Options)...)
+	return finishPreflight(result, failures), nil
+}
+
+// finishPreflight completes a preflight result, failing it if there are
+// failures.
+func finishPreflight(result *providers.Result, failures []string) *providers.Result {
+	result.Status = providers.StatusPreflightPassed
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// checkOptions compares the options a job will be run with against the
+// options the Rundeck job declares, the way Rundeck checks them at run time.
+func checkOptions(declared []JobOption, given map[string]string) []string {
+	var failures []string
+
+	known := make(map[string]bool, len(declared))
+	for _, option := range declared {
+		known[option.Name] = true
+	}
+
+	names := make([]string, 0, len(given))
+	for name := range given {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !known[name] {
+			failures = append(failures, fmt.Sprintf("option '%s' is not defined by the job", name))
+		}
+	}
+
+	for _, option := range declared {
+		value, ok := given[option.Name]
+		if !ok {
+			if option.Required && option.Value == "" {
+				failures = append(failures, fmt.Sprintf("required option '%s' is missing", option.Name))
+			}
+			continue
+		}
+
+		values := []string{value}
+		if option.Multivalued {
+			delimiter := option.Delimiter
+			if delimiter == "" {
+				delimiter = ","
+			}
+			values = strings.Split(value, delimiter)
+		}
+
+		// Rundeck patterns must match the whole value.
+		var pattern *regexp.Regexp
+		if option.Regex != "" {
+			pattern, _ = regexp.Compile("^(?:" + option.Regex + ")$")
+		}
+
+		for _, v := range values {
+			if option.Enforced && len(option.Values) > 0 && !contains(option.Values, v) {
+				failures = append(failures, fmt.Sprintf("option '%s' value '%s' is not one of: %s",
+					option.Name, v, strings.Join(option.Values, ", ")))
+			}
+			if pattern != nil && !pattern.MatchString(v) {
+				failures = append(failures, fmt.Sprintf("option '%s' value '%s' does not match %s",
+					option.Name, v, option.Regex))
+			}
+		}
+	}
+
+	return failures
+}
+
+// contains reports whether values contains s.
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
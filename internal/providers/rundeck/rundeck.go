@@ -3,20 +3,111 @@ package rundeck
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/user/jobprobe/internal/config"
 	"github.com/user/jobprobe/internal/providers"
 )
 
+// recentExecutions is how many of the most recent executions are searched
+// for a finished one in last_execution mode.
+const recentExecutions = 20
+
 // Provider implements the Rundeck job execution provider.
 type Provider struct {
 	onProgress providers.ProgressCallback
+	clients    *providers.ClientPool
+
+	mu      sync.Mutex
+	servers map[string]*server
+}
+
+// server is the state shared by the jobs against one environment: a client
+// limited to the environment's request rate and an execution poller.
+type server struct {
+	client *Client
+	poller *poller
 }
 
 // NewProvider creates a new Rundeck provider.
 func NewProvider() *Provider {
-	return &Provider{}
+	return &Provider{servers: make(map[string]*server)}
+}
+
+// server returns the shared state for the named environment, creating it
+// on first use.
+func (p *Provider) server(name string, env config.Environment) (*server, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s, ok := p.servers[name]; ok {
+		return s, nil
+	}
+
+	spec, errs := decodeEnvSpec(env)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid environment spec: %v", errs)
+	}
+	client := NewClient(env, p.clients.Client(name, env))
+	client.limiter = newLimiter(spec.RequestsPerSecond)
+
+	s := &server{client: client, poller: newPoller(client)}
+	p.servers[name] = s
+	return s, nil
+}
+
+// Close drops the per-environment clients and pollers, so a provider
+// reused for another run logs in and negotiates its API version again.
+func (p *Provider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.servers = make(map[string]*server)
+	return nil
+}
+
+// Init prepares the environment's client before its first job: it logs in
+// when the environment uses basic auth, then checks the configured API
+// version against the server, or uses the newest the server supports when
+// none is configured.
+func (p *Provider) Init(ctx context.Context, name string, env config.Environment) error {
+	srv, err := p.server(name, env)
+	if err != nil {
+		return err
+	}
+	client := srv.client
+
+	if env.Auth.Type == "basic" {
+		if err := client.Login(ctx, env.Auth.Username, env.Auth.Password); err != nil {
+			return fmt.Errorf("failed to log in to rundeck: %w", err)
+		}
+	}
+
+	info, err := client.SystemInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get rundeck system info: %w", err)
+	}
+
+	version, err := negotiateVersion(env.APIVersion, info.Rundeck)
+	if err != nil {
+		return err
+	}
+	client.apiVersion = version
+	return nil
+}
+
+// negotiateVersion returns the API version to use with a server: the
+// configured one if the server supports it, or the server's newest.
+func negotiateVersion(configured int, server RundeckInfo) (int, error) {
+	if configured == 0 {
+		return server.APIVersion, nil
+	}
+	if configured > server.APIVersion {
+		return 0, fmt.Errorf("api_version %d is not supported by Rundeck %s, which supports up to %d",
+			configured, server.Version, server.APIVersion)
+	}
+	return configured, nil
 }
 
 // Name returns the provider name.
@@ -29,6 +120,35 @@ func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
 	p.onProgress = cb
 }
 
+// SetClientPool sets the pool used to share connections across jobs.
+func (p *Provider) SetClientPool(pool *providers.ClientPool) {
+	p.clients = pool
+}
+
+// Capabilities describes what the Rundeck provider supports.
+func (p *Provider) Capabilities() providers.Capabilities {
+	return providers.Capabilities{Polling: true, Abort: true, Logs: true, DryRun: true}
+}
+
+// Abort aborts the execution behind the result if it was started and had
+// not finished when Execute returned. Executions that were attached to
+// rather than started are left running.
+func (p *Provider) Abort(ctx context.Context, job config.Job, env config.Environment, result *providers.Result) (bool, error) {
+	id, ok := result.Details["execution_id"].(int)
+	if !ok || !result.RemoteRunning {
+		return false, nil
+	}
+
+	srv, err := p.server(job.Environment, env)
+	if err != nil {
+		return false, err
+	}
+	if err := srv.client.AbortExecution(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to abort execution #%d: %w", id, err)
+	}
+	return true, nil
+}
+
 // Execute executes a Rundeck job and returns the result.
 func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
 	result := &providers.Result{
@@ -40,96 +160,197 @@ func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Envir
 		Details:     make(map[string]interface{}),
 	}
 
-	client := NewClient(env)
-
-	p.reportProgress(job.Name, providers.StatusPending, "Triggering job...")
+	spec, assertions, errs := decodeJob(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs)), nil
+	}
 
-	runResp, err := client.RunJob(ctx, job.JobID, job.Options)
+	srv, err := p.server(job.Environment, env)
 	if err != nil {
-		result.Status = providers.StatusFailed
-		result.Error = fmt.Sprintf("failed to trigger job: %v", err)
-		result.FinishedAt = time.Now()
-		result.Duration = result.FinishedAt.Sub(result.StartedAt)
-		return result, nil
+		return p.fail(result, err.Error()), nil
 	}
+	client := srv.client
 
-	result.Details["execution_id"] = runResp.ID
-	result.Details["permalink"] = runResp.Permalink
-	result.Status = providers.StatusRunning
+	if spec.Mode == ModeLastExecution {
+		return p.checkLastExecution(ctx, client, job, spec, assertions, result), nil
+	}
+
+	var (
+		executionID int
+		started     time.Time
+		average     time.Duration
+	)
+	if spec.Mode == ModeAttach {
+		p.reportProgress(job.Name, providers.StatusPending, "Looking for a running execution...")
+
+		running, err := client.ListRunningExecutions(ctx, spec.JobID)
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("failed to list running executions: %v", err)), nil
+		}
+		if len(running) > 0 {
+			executionID = running[0].ID
+			started = running[0].DateStarted.Time()
+			average = time.Duration(running[0].Job.AverageDuration) * time.Millisecond
+			result.Details["execution_id"] = running[0].ID
+			result.Details["permalink"] = running[0].Permalink
+			result.Details["attached"] = true
+			p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Attached to running execution #%d", executionID))
+		}
+	}
 
-	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Execution #%d started", runResp.ID))
+	if executionID == 0 {
+		p.reportProgress(job.Name, providers.StatusPending, "Triggering job...")
+
+		runResp, err := client.RunJob(ctx, spec.JobID, spec.Options)
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("failed to trigger job: %v", err)), nil
+		}
+
+		executionID = runResp.ID
+		started = runResp.DateStarted.Time()
+		average = time.Duration(runResp.Job.AverageDuration) * time.Millisecond
+		result.Details["execution_id"] = runResp.ID
+		result.Details["permalink"] = runResp.Permalink
+		result.RemoteRunning = true
+		p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Execution #%d started", runResp.ID))
+	}
+	result.Status = providers.StatusRunning
 
 	defaults := config.Defaults{
 		Timeout:      10 * time.Minute,
 		PollInterval: 10 * time.Second,
 	}
 
-	execResult, err := p.pollExecution(ctx, client, runResp.ID, job, defaults)
+	if started.IsZero() {
+		started = time.Now()
+	}
+	b := &backoff{interval: job.GetPollInterval(defaults), average: average}
+
+	execResult, err := p.pollExecution(ctx, srv.poller, spec.Project, executionID, started, b, job, defaults)
 	if err != nil {
-		result.Status = providers.StatusFailed
-		result.Error = fmt.Sprintf("polling failed: %v", err)
-		result.FinishedAt = time.Now()
-		result.Duration = result.FinishedAt.Sub(result.StartedAt)
-		return result, nil
+		return p.fail(result, fmt.Sprintf("polling failed: %v", err)), nil
+	}
+
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	applyExecution(result, execResult, job, result.Duration)
+	p.checkLog(ctx, client, job, assertions.Log, executionID, result)
+
+	return result, nil
+}
+
+// checkLastExecution checks the job's most recent finished execution
+// instead of running the job.
+func (p *Provider) checkLastExecution(ctx context.Context, client *Client, job config.Job, spec JobSpec, assertions Assertions, result *providers.Result) *providers.Result {
+	p.reportProgress(job.Name, providers.StatusRunning, "Fetching last execution...")
+
+	executions, err := client.ListExecutions(ctx, spec.JobID, recentExecutions)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to list executions: %v", err))
+	}
+
+	var last *ExecutionResponse
+	for i := range executions {
+		if executions[i].Status.IsTerminal() {
+			last = &executions[i]
+			break
+		}
+	}
+	if last == nil {
+		return p.fail(result, fmt.Sprintf("no finished execution among the last %d", recentExecutions))
 	}
 
-	result.Status = mapStatus(execResult.Status)
-	result.Details["job_status"] = string(execResult.Status)
+	result.Details["execution_id"] = last.ID
+	result.Details["permalink"] = last.Permalink
+
+	started, ended := last.DateStarted.Time(), last.DateEnded.Time()
+	result.Details["execution_ended"] = ended
 	result.FinishedAt = time.Now()
 	result.Duration = result.FinishedAt.Sub(result.StartedAt)
 
-	if len(execResult.FailedNodes) > 0 {
-		result.Details["failed_nodes"] = execResult.FailedNodes
-		result.Error = fmt.Sprintf("failed on nodes: %v", execResult.FailedNodes)
+	applyExecution(result, last, job, ended.Sub(started))
+	p.checkLog(ctx, client, job, assertions.Log, last.ID, result)
+
+	age := time.Since(ended)
+	result.Details["age"] = age.Round(time.Second).String()
+	if spec.MaxAge > 0 && age > spec.MaxAge {
+		result.Status = providers.StatusFailed
+		appendError(result, fmt.Sprintf("last execution #%d finished %s ago, older than max age %s",
+			last.ID, age.Round(time.Second), spec.MaxAge))
 	}
 
-	if job.Assertions.Status != "" && string(execResult.Status) != job.Assertions.Status {
+	return result
+}
+
+// applyExecution sets the result's status from a finished execution and
+// applies the job's assertions, using duration for max_duration.
+func applyExecution(result *providers.Result, exec *ExecutionResponse, job config.Job, duration time.Duration) {
+	result.Status = mapStatus(exec.Status)
+	result.Details["job_status"] = string(exec.Status)
+	result.RemoteRunning = false
+
+	if len(exec.FailedNodes) > 0 {
+		result.Details["failed_nodes"] = exec.FailedNodes
+		result.Error = fmt.Sprintf("failed on nodes: %v", exec.FailedNodes)
+	}
+
+	if job.Assertions.Status != "" && string(exec.Status) != job.Assertions.Status {
 		result.Status = providers.StatusFailed
-		result.Error = fmt.Sprintf("expected status '%s', got '%s'", job.Assertions.Status, execResult.Status)
+		result.Error = fmt.Sprintf("expected status '%s', got '%s'", job.Assertions.Status, exec.Status)
 	}
 
-	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+	if job.Assertions.MaxDuration > 0 && duration > job.Assertions.MaxDuration {
 		result.Status = providers.StatusFailed
-		if result.Error != "" {
-			result.Error += "; "
-		}
-		result.Error += fmt.Sprintf("duration %s exceeded max %s", result.Duration, job.Assertions.MaxDuration)
+		appendError(result, fmt.Sprintf("duration %s exceeded max %s", duration, job.Assertions.MaxDuration))
 	}
+}
 
-	return result, nil
+// appendError adds a message to the result's error.
+func appendError(result *providers.Result, message string) {
+	if result.Error != "" {
+		result.Error += "; "
+	}
+	result.Error += message
 }
 
-// pollExecution polls the execution status until completion or timeout.
-func (p *Provider) pollExecution(ctx context.Context, client *Client, executionID int, job config.Job, defaults config.Defaults) (*ExecutionResponse, error) {
+// pollExecution polls the execution status until completion or timeout. It
+// polls once straight away, then waits as b decides; started is when the
+// execution started, used to compare its progress against the average.
+func (p *Provider) pollExecution(ctx context.Context, poller *poller, project string, executionID int, started time.Time, b *backoff, job config.Job, defaults config.Defaults) (*ExecutionResponse, error) {
 	timeout := job.GetTimeout(defaults)
-	pollInterval := job.GetPollInterval(defaults)
 
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+	defer poller.track(project)()
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	lastPoll := start
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 
-		case <-ticker.C:
-			if time.Now().After(deadline) {
+		case <-timer.C:
+			if !time.Now().Before(deadline) {
 				return nil, fmt.Errorf("timeout after %s", timeout)
 			}
 
-			exec, err := client.GetExecution(ctx, executionID)
+			exec, err := poller.status(ctx, project, executionID, lastPoll)
 			if err != nil {
 				return nil, err
 			}
+			lastPoll = time.Now()
 
-			elapsed := time.Since(time.Now().Add(-timeout + time.Until(deadline)))
 			p.reportProgress(job.Name, providers.StatusRunning,
-				fmt.Sprintf("Polling... (%s) status=%s", elapsed.Round(time.Second), exec.Status))
+				fmt.Sprintf("Polling... (%s) status=%s", time.Since(start).Round(time.Second), exec.Status))
 
 			if exec.Status.IsTerminal() {
 				return exec, nil
 			}
+
+			timer.Reset(min(b.next(time.Since(started)), time.Until(deadline)))
 		}
 	}
 }
@@ -152,6 +373,15 @@ func mapStatus(status ExecutionStatus) providers.Status {
 	}
 }
 
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
 // reportProgress reports progress if a callback is set.
 func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
 	if p.onProgress != nil {
@@ -0,0 +1,485 @@
+package rundeck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+func TestAbort(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" token="+r.Header.Get("X-Rundeck-Auth-Token"))
+		fmt.Fprint(w, `{"abort":{"status":"pending"},"execution":{"id":"42","status":"running"}}`)
+	}))
+	defer server.Close()
+
+	env := config.Environment{Type: "rundeck", URL: server.URL, Auth: config.Auth{Type: "bearer", Token: "rd-token"}}
+	job := config.Job{Name: "backup", Type: "rundeck"}
+
+	running := &providers.Result{Status: providers.StatusFailed, RemoteRunning: true, Details: map[string]interface{}{"execution_id": 42}}
+	aborted, err := NewProvider().Abort(context.Background(), job, env, running)
+	if err != nil || !aborted {
+		t.Fatalf("Abort() = %t, %v, want execution aborted", aborted, err)
+	}
+	if len(requests) != 1 || requests[0] != "POST /api/41/execution/42/abort token=rd-token" {
+		t.Errorf("requests = %v", requests)
+	}
+
+	finished := &providers.Result{Status: providers.StatusFailed, Details: map[string]interface{}{"execution_id": 42, "job_status": "failed"}}
+	if aborted, err := NewProvider().Abort(context.Background(), job, env, finished); aborted || err != nil {
+		t.Errorf("Abort() = %t, %v for a finished execution", aborted, err)
+	}
+}
+
+// jobDefinition is the YAML export of the job used by the preflight tests.
+const jobDefinition = `- id: abc-123
+  name: nightly-backup
+  group: ops
+  options:
+  - name: target
+    required: true
+    enforced: true
+    values: [mysql, postgres]
+  - name: retention
+    value: "7"
+    regex: '[0-9]+'
+  - name: tables
+    multivalued: true
+    delimiter: ','
+    enforced: true
+    values: [users, orders]
+`
+
+func TestPreflight(t *testing.T) {
+	var runs int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/41/job/abc-123/info":
+			fmt.Fprint(w, `{"id":"abc-123","name":"nightly-backup","group":"ops","project":"production"}`)
+		case r.URL.Path == "/api/41/job/abc-123" && r.URL.Query().Get("format") == "yaml":
+			fmt.Fprint(w, jobDefinition)
+		case strings.HasSuffix(r.URL.Path, "/run"):
+			runs++
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":true,"errorCode":"api.error.item.doesnotexist","message":"Job ID does not exist: missing"}`)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		spec       map[string]any
+		wantStatus providers.Status
+		wantErrors []string
+	}{
+		{
+			name:       "valid options",
+			spec:       map[string]any{"job_id": "abc-123", "project": "production", "options": map[string]any{"target": "mysql", "retention": "30", "tables": "users,orders"}},
+			wantStatus: providers.StatusPreflightPassed,
+		},
+		{
+			name:       "missing job",
+			spec:       map[string]any{"job_id": "missing", "project": "production"},
+			wantStatus: providers.StatusFailed,
+			wantErrors: []string{"failed to look up job: rundeck error [api.error.item.doesnotexist]: Job ID does not exist: missing"},
+		},
+		{
+			name:       "wrong project",
+			spec:       map[string]any{"job_id": "abc-123", "project": "staging", "options": map[string]any{"target": "mysql"}},
+			wantStatus: providers.StatusFailed,
+			wantErrors: []string{"job belongs to project 'production', not 'staging'"},
+		},
+		{
+			name:       "invalid options",
+			spec:       map[string]any{"job_id": "abc-123", "project": "production", "options": map[string]any{"retention": "week", "tables": "users,logs", "force": "yes"}},
+			wantStatus: providers.StatusFailed,
+			wantErrors: []string{
+				"option 'force' is not defined by the job",
+				"required option 'target' is missing",
+				"option 'retention' value 'week' does not match [0-9]+",
+				"option 'tables' value 'logs' is not one of: users, orders",
+			},
+		},
+	}
+
+	env := config.Environment{Type: "rundeck", URL: server.URL}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{Name: tt.name, Type: "rundeck", Spec: tt.spec}
+			result, err := NewProvider().Preflight(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Preflight() error = %v", err)
+			}
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			for _, want := range tt.wantErrors {
+				if !strings.Contains(result.Error, want) {
+					t.Errorf("Error = %q, want it to contain %q", result.Error, want)
+				}
+			}
+			if len(tt.wantErrors) == 0 && result.Error != "" {
+				t.Errorf("unexpected error %q", result.Error)
+			}
+		})
+	}
+
+	if runs != 0 {
+		t.Errorf("preflight ran the job %d times", runs)
+	}
+}
+
+func TestLastExecution(t *testing.T) {
+	ended := time.Now().Add(-2 * time.Hour)
+	executions := fmt.Sprintf(`{"paging":{"count":2,"total":2,"offset":0,"max":20},"executions":[
+		{"id":8,"status":"running","date-started":{"unixtime":%d}},
+		{"id":7,"status":"succeeded","date-started":{"unixtime":%d},"date-ended":{"unixtime":%d}}
+	]}`, time.Now().UnixMilli(), ended.Add(-5*time.Minute).UnixMilli(), ended.UnixMilli())
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		fmt.Fprint(w, executions)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		spec       map[string]any
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "recent",
+			spec:       map[string]any{"max_age": "3h"},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "too old",
+			spec:       map[string]any{"max_age": "1h"},
+			wantStatus: providers.StatusFailed,
+			wantError:  "last execution #7 finished 2h0m0s ago, older than max age 1h0m0s",
+		},
+		{
+			name:       "too slow",
+			assertions: config.Assertions{MaxDuration: time.Minute},
+			wantStatus: providers.StatusFailed,
+			wantError:  "duration 5m0s exceeded max 1m0s",
+		},
+	}
+
+	env := config.Environment{Type: "rundeck", URL: server.URL}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = nil
+			spec := map[string]any{"job_id": "abc-123", "project": "ops", "mode": "last_execution"}
+			for k, v := range tt.spec {
+				spec[k] = v
+			}
+			job := config.Job{Name: tt.name, Type: "rundeck", Spec: spec, Assertions: tt.assertions}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Status != tt.wantStatus || !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("result = %s %q, want %s %q", result.Status, result.Error, tt.wantStatus, tt.wantError)
+			}
+			if result.Details["execution_id"] != 7 {
+				t.Errorf("execution_id = %v, want the last finished execution", result.Details["execution_id"])
+			}
+			if len(requests) != 1 || requests[0] != "GET /api/41/job/abc-123/executions?max=20" {
+				t.Errorf("requests = %v", requests)
+			}
+		})
+	}
+}
+
+func TestAttach(t *testing.T) {
+	tests := []struct {
+		name         string
+		running      string
+		wantRequests []string
+		wantAttached bool
+	}{
+		{
+			name:    "running execution",
+			running: `{"executions":[{"id":12,"status":"running"}]}`,
+			wantRequests: []string{
+				"GET /api/41/job/abc-123/executions?status=running",
+				"GET /api/41/execution/12",
+			},
+			wantAttached: true,
+		},
+		{
+			name:    "nothing running",
+			running: `{"executions":[]}`,
+			wantRequests: []string{
+				"GET /api/41/job/abc-123/executions?status=running",
+				"POST /api/41/job/abc-123/run",
+				"GET /api/41/execution/13",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.RequestURI())
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/executions"):
+					fmt.Fprint(w, tt.running)
+				case strings.HasSuffix(r.URL.Path, "/run"):
+					fmt.Fprint(w, `{"id":13}`)
+				default:
+					fmt.Fprint(w, `{"id":12,"status":"succeeded"}`)
+				}
+			}))
+			defer server.Close()
+
+			job := config.Job{
+				Name:         tt.name,
+				Type:         "rundeck",
+				Spec:         map[string]any{"job_id": "abc-123", "project": "ops", "mode": "attach"},
+				PollInterval: 10 * time.Millisecond,
+			}
+			env := config.Environment{Type: "rundeck", URL: server.URL}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Status != providers.StatusSucceeded {
+				t.Errorf("Status = %s (%s)", result.Status, result.Error)
+			}
+			if strings.Join(requests, "\n") != strings.Join(tt.wantRequests, "\n") {
+				t.Errorf("requests = %v, want %v", requests, tt.wantRequests)
+			}
+			if attached, _ := result.Details["attached"].(bool); attached != tt.wantAttached {
+				t.Errorf("attached = %t, want %t", attached, tt.wantAttached)
+			}
+		})
+	}
+
+	attached := &providers.Result{Status: providers.StatusTimedOut, Details: map[string]interface{}{"execution_id": 12, "attached": true}}
+	if aborted, err := NewProvider().Abort(context.Background(), config.Job{}, config.Environment{}, attached); aborted || err != nil {
+		t.Errorf("Abort() = %t, %v for an attached execution", aborted, err)
+	}
+}
+
+func TestInit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/j_security_check":
+			if r.FormValue("j_username") != "admin" || r.FormValue("j_password") != "secret" {
+				http.Redirect(w, r, "/user/error", http.StatusFound)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: "session-1", Path: "/"})
+			http.Redirect(w, r, "/menu/home", http.StatusFound)
+			return
+		case "/menu/home", "/user/error":
+			return
+		}
+
+		cookie, _ := r.Cookie("JSESSIONID")
+		if r.Header.Get("X-Rundeck-Auth-Token") != "rd-token" && (cookie == nil || cookie.Value != "session-1") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":true,"errorCode":"api.error.unauthorized","message":"Not authorized"}`)
+			return
+		}
+		if r.Header.Get("X-Tenant") != "ops" {
+			t.Errorf("%s: X-Tenant header = %q", r.URL.Path, r.Header.Get("X-Tenant"))
+		}
+
+		switch r.URL.Path {
+		case "/api/14/system/info":
+			fmt.Fprint(w, `{"system":{"rundeck":{"version":"4.17.0","build":"4.17.0-20231016","apiversion":45}}}`)
+		case "/api/45/execution/42/abort", "/api/41/execution/42/abort":
+			fmt.Fprint(w, `{"abort":{"status":"pending"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		env         config.Environment
+		wantErr     string
+		wantVersion int
+	}{
+		{
+			name:        "session login",
+			env:         config.Environment{Auth: config.Auth{Type: "basic", Username: "admin", Password: "secret"}},
+			wantVersion: 45,
+		},
+		{
+			name:        "token with api version",
+			env:         config.Environment{APIVersion: 41, Auth: config.Auth{Token: "rd-token"}},
+			wantVersion: 41,
+		},
+		{
+			name:    "wrong password",
+			env:     config.Environment{Auth: config.Auth{Type: "basic", Username: "admin", Password: "guess"}},
+			wantErr: "failed to log in to rundeck: login rejected for user 'admin'",
+		},
+		{
+			name:    "unsupported api version",
+			env:     config.Environment{APIVersion: 50, Auth: config.Auth{Token: "rd-token"}},
+			wantErr: "api_version 50 is not supported by Rundeck 4.17.0, which supports up to 45",
+		},
+		{
+			name:    "no credentials",
+			env:     config.Environment{},
+			wantErr: "failed to get rundeck system info: rundeck error [api.error.unauthorized]: Not authorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := tt.env
+			env.Type = "rundeck"
+			env.URL = server.URL
+			env.Headers = map[string]string{"X-Tenant": "ops"}
+
+			p := NewProvider()
+			err := p.Init(context.Background(), "prod", env)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Init() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Init() error = %v", err)
+			}
+			srv, err := p.server("prod", env)
+			if err != nil {
+				t.Fatalf("server() error = %v", err)
+			}
+			if got := srv.client.apiVersion; got != tt.wantVersion {
+				t.Errorf("api version = %d, want %d", got, tt.wantVersion)
+			}
+
+			job := config.Job{Name: "backup", Type: "rundeck", Environment: "prod"}
+			running := &providers.Result{Status: providers.StatusFailed, RemoteRunning: true, Details: map[string]interface{}{"execution_id": 42}}
+			if aborted, err := p.Abort(context.Background(), job, env, running); !aborted || err != nil {
+				t.Errorf("Abort() after Init = %t, %v", aborted, err)
+			}
+		})
+	}
+}
+
+func TestLogAssertions(t *testing.T) {
+	pages := map[string]string{
+		"0": `{"id":"7","offset":"120","completed":false,"execCompleted":true,"entries":[
+			{"type":"log","log":"starting export","node":"db01","stepctx":"1"},
+			{"type":"stepbegin","log":"","node":"db01","stepctx":"2"},
+			{"type":"log","log":"0 rows exported","node":"db01","stepctx":"2@node=db01/1"}
+		]}`,
+		"120": `{"id":"7","offset":"180","completed":true,"execCompleted":true,"entries":[
+			{"type":"log","log":"WARN slow query\ndone","node":"db02","stepctx":"3"}
+		]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/41/job/abc-123/executions":
+			fmt.Fprint(w, `{"executions":[{"id":7,"status":"succeeded"}]}`)
+		case "/api/41/execution/7/output":
+			fmt.Fprint(w, pages[r.URL.Query().Get("offset")])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		log       []any
+		wantError string
+	}{
+		{
+			name: "passing",
+			log: []any{
+				map[string]any{"matches": "starting export"},
+				map[string]any{"node": "db02", "lines": map[string]any{"equals": 2}},
+				map[string]any{"step": "1", "not_matches": "rows exported"},
+			},
+		},
+		{
+			name:      "no rows exported",
+			log:       []any{map[string]any{"step": "2", "matches": "[1-9][0-9]* rows exported"}},
+			wantError: `log (step 2): no line matches "[1-9][0-9]* rows exported"`,
+		},
+		{
+			name:      "warning on node",
+			log:       []any{map[string]any{"node": "db02", "not_matches": "^WARN"}},
+			wantError: `log (node db02): line 1 matches "^WARN": WARN slow query`,
+		},
+	}
+
+	env := config.Environment{Type: "rundeck", URL: server.URL}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       tt.name,
+				Type:       "rundeck",
+				Spec:       map[string]any{"job_id": "abc-123", "project": "ops", "mode": "last_execution"},
+				Assertions: config.Assertions{Settings: map[string]any{"log": tt.log}},
+			}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			wantStatus := providers.StatusSucceeded
+			if tt.wantError != "" {
+				wantStatus = providers.StatusFailed
+			}
+			if result.Status != wantStatus || result.Error != tt.wantError {
+				t.Errorf("result = %s %q, want %s %q", result.Status, result.Error, wantStatus, tt.wantError)
+			}
+			if result.Details["log_entries"] != 4 {
+				t.Errorf("log_entries = %v, want 4", result.Details["log_entries"])
+			}
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	p := NewProvider()
+
+	invalid := config.Environment{Type: "rundeck", URL: "http://rundeck", Spec: map[string]any{"requests_per_sec": 5}}
+	if _, err := p.server("prod", invalid); err == nil || err.Error() != "invalid environment spec: spec.requests_per_sec: unknown field" {
+		t.Errorf("server() error = %v", err)
+	}
+
+	env := config.Environment{Type: "rundeck", URL: "http://rundeck"}
+	first, err := p.server("prod", env)
+	if err != nil {
+		t.Fatalf("server() error = %v", err)
+	}
+	if again, _ := p.server("prod", env); again != first {
+		t.Error("jobs in the same environment did not share a server")
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if after, _ := p.server("prod", env); after == first {
+		t.Error("Close() kept the environment's server")
+	}
+}
@@ -0,0 +1,648 @@
+// Package rundecktest provides an in-process Rundeck server for tests. It
+// implements the parts of the Rundeck API the rundeck provider uses: running
+// jobs, execution status, running execution listings, logs, aborts, job
+// definitions, system info and session login, with Rundeck's error
+// responses.
+package rundecktest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/user/jobprobe/internal/providers/rundeck"
+)
+
+// DefaultAPIVersion is the newest API version the server supports unless
+// Config sets another.
+const DefaultAPIVersion = 45
+
+// Config configures a Server. The zero value accepts any request without
+// credentials.
+type Config struct {
+	// Token is the API token requests must send. Empty accepts any token.
+	Token string
+
+	// Users maps usernames to passwords for session login. When set,
+	// requests without a valid token must carry a session cookie.
+	Users map[string]string
+
+	// APIVersion is the newest API version supported. Defaults to
+	// DefaultAPIVersion.
+	APIVersion int
+
+	// Version is the reported Rundeck version.
+	Version string
+}
+
+// Job is a simulated Rundeck job and how its executions behave.
+type Job struct {
+	ID      string              `json:"id"`
+	Name    string              `json:"name"`
+	Group   string              `json:"group"`
+	Project string              `json:"project"`
+	Options []rundeck.JobOption `json:"options"`
+
+	// Duration is how long each execution runs. Hold keeps executions
+	// running until Finish or an abort.
+	Duration time.Duration `json:"duration"`
+	Hold     bool          `json:"hold"`
+
+	// Result is the status executions end with: succeeded (the default),
+	// failed, timedout or failed-with-retry.
+	Result rundeck.ExecutionStatus `json:"result"`
+
+	// FailedNodes are reported on finished executions; Nodes lists the
+	// nodes the job runs on, reported as successful unless they failed.
+	FailedNodes []string `json:"failed_nodes"`
+	Nodes       []string `json:"nodes"`
+
+	// Log is the execution log, available once an execution finishes.
+	Log []rundeck.LogEntry `json:"log"`
+
+	// AverageDuration is reported as the job's average duration.
+	AverageDuration time.Duration `json:"average_duration"`
+}
+
+// Execution is the state of a simulated execution.
+type Execution struct {
+	ID      int                     `json:"id"`
+	JobID   string                  `json:"job_id"`
+	Options map[string]string       `json:"options,omitempty"`
+	Status  rundeck.ExecutionStatus `json:"status"`
+	Started time.Time               `json:"started"`
+	Ended   time.Time               `json:"ended"`
+}
+
+// apiError is a Rundeck error response to inject.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+// Server is a simulated Rundeck server.
+type Server struct {
+	// URL is the base URL of a server started with NewServer.
+	URL string
+
+	config Config
+	server *httptest.Server
+
+	mu         sync.Mutex
+	jobs       map[string]*Job
+	executions map[int]*Execution
+	nextID     int
+	sessions   map[string]bool
+	errors     map[string]apiError
+	requests   []string
+}
+
+// New creates a Server that is not listening; serve its Handler.
+func New(cfg Config) *Server {
+	if cfg.APIVersion == 0 {
+		cfg.APIVersion = DefaultAPIVersion
+	}
+	if cfg.Version == "" {
+		cfg.Version = "5.0.0"
+	}
+	return &Server{
+		config:     cfg,
+		jobs:       make(map[string]*Job),
+		executions: make(map[int]*Execution),
+		nextID:     1,
+		sessions:   make(map[string]bool),
+		errors:     make(map[string]apiError),
+	}
+}
+
+// NewServer creates and starts a Server on a local port. Close it when done.
+func NewServer(cfg Config) *Server {
+	s := New(cfg)
+	s.server = httptest.NewServer(s.Handler())
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down a server started with NewServer.
+func (s *Server) Close() {
+	if s.server != nil {
+		s.server.Close()
+	}
+}
+
+// AddJob adds or replaces a job.
+func (s *Server) AddJob(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = &job
+}
+
+// Run starts an execution of a job as if Rundeck had scheduled it, and
+// returns its ID.
+func (s *Server) Run(jobID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return 0, fmt.Errorf("job %s does not exist", jobID)
+	}
+	return s.start(job, nil).ID, nil
+}
+
+// Finish ends a running execution with the given status.
+func (s *Server) Finish(executionID int, status rundeck.ExecutionStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exec, ok := s.executions[executionID]
+	if !ok {
+		return fmt.Errorf("execution %d does not exist", executionID)
+	}
+	if s.refresh(exec).Status.IsTerminal() {
+		return fmt.Errorf("execution %d already finished", executionID)
+	}
+	exec.Status = status
+	exec.Ended = time.Now()
+	return nil
+}
+
+// Execution returns a copy of an execution's current state.
+func (s *Server) Execution(executionID int) (Execution, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exec, ok := s.executions[executionID]
+	if !ok {
+		return Execution{}, false
+	}
+	return *s.refresh(exec), true
+}
+
+// InjectError makes requests to an API path, such as /job/abc/run, fail
+// with a Rundeck error until ClearErrors is called.
+func (s *Server) InjectError(path string, status int, errorCode, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[path] = apiError{status: status, code: errorCode, message: message}
+}
+
+// ClearErrors removes injected errors.
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = make(map[string]apiError)
+}
+
+// Requests returns the requests received so far, as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// Handler returns the server's HTTP handler. Besides the Rundeck API it
+// serves control endpoints: POST /control/jobs adds the job in the body and
+// POST /control/executions/{id}/finish ends an execution with the status in
+// the body's "status" field.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /j_security_check", s.login)
+	mux.HandleFunc("GET /menu/home", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "home") })
+	mux.HandleFunc("GET /user/error", func(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "login failed") })
+
+	mux.HandleFunc("GET /api/{version}/system/info", s.api(s.systemInfo))
+	mux.HandleFunc("POST /api/{version}/job/{id}/run", s.api(s.runJob))
+	mux.HandleFunc("GET /api/{version}/job/{id}/info", s.api(s.jobInfo))
+	mux.HandleFunc("GET /api/{version}/job/{id}", s.api(s.jobDefinition))
+	mux.HandleFunc("GET /api/{version}/job/{id}/executions", s.api(s.jobExecutions))
+	mux.HandleFunc("GET /api/{version}/project/{project}/executions/running", s.api(s.runningExecutions))
+	mux.HandleFunc("GET /api/{version}/execution/{id}", s.api(s.execution))
+	mux.HandleFunc("GET /api/{version}/execution/{id}/output", s.api(s.output))
+	mux.HandleFunc("POST /api/{version}/execution/{id}/abort", s.api(s.abort))
+
+	mux.HandleFunc("POST /control/jobs", s.controlJob)
+	mux.HandleFunc("POST /control/executions/{id}/finish", s.controlFinish)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// api wraps an API handler with the API version check, authentication and
+// injected errors. The wrapped handler runs with the server locked.
+func (s *Server) api(handler func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		version, err := strconv.Atoi(r.PathValue("version"))
+		if err != nil || version < rundeck.MinAPIVersion || version > s.config.APIVersion {
+			s.writeError(w, http.StatusBadRequest, "api.error.api-version.unsupported",
+				fmt.Sprintf("Unsupported API Version \"%s\". API Request: %s. Reason: Current version: %d",
+					r.PathValue("version"), r.URL.Path, s.config.APIVersion))
+			return
+		}
+
+		if !s.authorized(r) {
+			s.writeError(w, http.StatusForbidden, "unauthorized",
+				fmt.Sprintf("(Token:****) is not authorized for: %s", r.URL.Path))
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		path := strings.TrimPrefix(r.URL.Path, "/api/"+r.PathValue("version"))
+		if e, ok := s.errors[path]; ok {
+			s.writeError(w, e.status, e.code, e.message)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// authorized reports whether a request carries the token or a session.
+func (s *Server) authorized(r *http.Request) bool {
+	if s.config.Token == "" && len(s.config.Users) == 0 {
+		return true
+	}
+	if token := r.Header.Get("X-Rundeck-Auth-Token"); token != "" && token == s.config.Token {
+		return true
+	}
+	if cookie, err := r.Cookie("JSESSIONID"); err == nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.sessions[cookie.Value]
+	}
+	return false
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	password, ok := s.config.Users[r.FormValue("j_username")]
+	if !ok || password != r.FormValue("j_password") {
+		http.Redirect(w, r, "/user/error", http.StatusFound)
+		return
+	}
+
+	session := newSessionID()
+	s.mu.Lock()
+	s.sessions[session] = true
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: "JSESSIONID", Value: session, Path: "/", HttpOnly: true})
+	http.Redirect(w, r, "/menu/home", http.StatusFound)
+}
+
+func (s *Server) systemInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, rundeck.SystemInfoResponse{System: rundeck.SystemInfo{Rundeck: rundeck.RundeckInfo{
+		Version:    s.config.Version,
+		Build:      s.config.Version + "-rundecktest",
+		APIVersion: s.config.APIVersion,
+	}}})
+}
+
+func (s *Server) runJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.job(w, r)
+	if !ok {
+		return
+	}
+
+	var req rundeck.RunJobRequest
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "api.error.invalid.request", fmt.Sprintf("Invalid request body: %v", err))
+			return
+		}
+	}
+
+	exec := s.start(job, req.Options)
+	resp := s.response(exec)
+	writeJSON(w, rundeck.RunJobResponse{
+		ID:          resp.ID,
+		Href:        resp.Href,
+		Permalink:   resp.Permalink,
+		Status:      string(resp.Status),
+		DateStarted: resp.DateStarted,
+		Job:         resp.Job,
+		ArgString:   resp.ArgString,
+		Project:     resp.Project,
+	})
+}
+
+func (s *Server) jobInfo(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.job(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, s.jobInfoOf(job))
+}
+
+func (s *Server) jobDefinition(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.job(w, r)
+	if !ok {
+		return
+	}
+
+	definition := []rundeck.JobDefinition{{ID: job.ID, Name: job.Name, Group: job.Group, Options: job.Options}}
+	if r.URL.Query().Get("format") == "yaml" {
+		w.Header().Set("Content-Type", "application/yaml")
+		yaml.NewEncoder(w).Encode(definition)
+		return
+	}
+	writeJSON(w, definition)
+}
+
+func (s *Server) jobExecutions(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.job(w, r)
+	if !ok {
+		return
+	}
+
+	status := rundeck.ExecutionStatus(r.URL.Query().Get("status"))
+	s.writeExecutions(w, r, func(exec *Execution) bool {
+		return exec.JobID == job.ID && (status == "" || exec.Status == status)
+	})
+}
+
+func (s *Server) runningExecutions(w http.ResponseWriter, r *http.Request) {
+	project := r.PathValue("project")
+	s.writeExecutions(w, r, func(exec *Execution) bool {
+		job := s.jobs[exec.JobID]
+		return exec.Status == rundeck.ExecutionStatusRunning && (project == "*" || job.Project == project)
+	})
+}
+
+func (s *Server) execution(w http.ResponseWriter, r *http.Request) {
+	exec, ok := s.lookupExecution(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, s.response(exec))
+}
+
+// output serves an execution's log. Offset is an index into the log, and
+// the log is complete once the execution has finished.
+func (s *Server) output(w http.ResponseWriter, r *http.Request) {
+	exec, ok := s.lookupExecution(w, r)
+	if !ok {
+		return
+	}
+
+	var entries []rundeck.LogEntry
+	finished := exec.Status.IsTerminal()
+	if finished {
+		entries = s.jobs[exec.JobID].Log
+	}
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	offset = min(max(offset, 0), len(entries))
+	end := len(entries)
+	if maxLines, err := strconv.Atoi(r.URL.Query().Get("maxlines")); err == nil && maxLines > 0 {
+		end = min(offset+maxLines, end)
+	}
+
+	writeJSON(w, rundeck.ExecutionOutput{
+		ID:            json.Number(strconv.Itoa(exec.ID)),
+		Offset:        json.Number(strconv.Itoa(end)),
+		Completed:     finished && end == len(entries),
+		ExecCompleted: finished,
+		TotalSize:     int64(len(entries)),
+		Entries:       append([]rundeck.LogEntry{}, entries[offset:end]...),
+	})
+}
+
+func (s *Server) abort(w http.ResponseWriter, r *http.Request) {
+	exec, ok := s.lookupExecution(w, r)
+	if !ok {
+		return
+	}
+
+	status := "failed"
+	if exec.Status == rundeck.ExecutionStatusRunning {
+		exec.Status = rundeck.ExecutionStatusAborted
+		exec.Ended = time.Now()
+		status = "aborted"
+	}
+	writeJSON(w, map[string]any{
+		"abort":     map[string]string{"status": status},
+		"execution": map[string]any{"id": strconv.Itoa(exec.ID), "status": string(exec.Status)},
+	})
+}
+
+func (s *Server) controlJob(w http.ResponseWriter, r *http.Request) {
+	var job Job
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil || job.ID == "" {
+		http.Error(w, "body must be a job with an id", http.StatusBadRequest)
+		return
+	}
+	s.AddJob(job)
+	writeJSON(w, job)
+}
+
+func (s *Server) controlFinish(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid execution id", http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Status rundeck.ExecutionStatus `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !body.Status.IsTerminal() {
+		http.Error(w, "body must set a terminal status", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.Finish(id, body.Status); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	exec, _ := s.Execution(id)
+	writeJSON(w, exec)
+}
+
+// job looks up the job in the request path, writing Rundeck's error if it
+// does not exist.
+func (s *Server) job(w http.ResponseWriter, r *http.Request) (*Job, bool) {
+	job, ok := s.jobs[r.PathValue("id")]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "api.error.item.doesnotexist",
+			fmt.Sprintf("Job ID does not exist: %s", r.PathValue("id")))
+	}
+	return job, ok
+}
+
+// lookupExecution looks up the execution in the request path, writing
+// Rundeck's error if it does not exist.
+func (s *Server) lookupExecution(w http.ResponseWriter, r *http.Request) (*Execution, bool) {
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	exec, ok := s.executions[id]
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "api.error.item.doesnotexist",
+			fmt.Sprintf("Execution ID does not exist: %s", r.PathValue("id")))
+		return nil, false
+	}
+	return s.refresh(exec), true
+}
+
+// start creates a running execution of job.
+func (s *Server) start(job *Job, options map[string]string) *Execution {
+	exec := &Execution{
+		ID:      s.nextID,
+		JobID:   job.ID,
+		Options: options,
+		Status:  rundeck.ExecutionStatusRunning,
+		Started: time.Now(),
+	}
+	s.nextID++
+	s.executions[exec.ID] = exec
+	return exec
+}
+
+// refresh finishes an execution whose job's duration has passed.
+func (s *Server) refresh(exec *Execution) *Execution {
+	job := s.jobs[exec.JobID]
+	if exec.Status != rundeck.ExecutionStatusRunning || job.Hold || time.Since(exec.Started) < job.Duration {
+		return exec
+	}
+
+	exec.Status = job.Result
+	if exec.Status == "" {
+		exec.Status = rundeck.ExecutionStatusSucceeded
+	}
+	exec.Ended = exec.Started.Add(job.Duration)
+	return exec
+}
+
+// writeExecutions writes the executions matching keep, newest first, as an
+// executions listing limited by the request's max parameter.
+func (s *Server) writeExecutions(w http.ResponseWriter, r *http.Request, keep func(*Execution) bool) {
+	var matched []*Execution
+	for _, exec := range s.executions {
+		if keep(s.refresh(exec)) {
+			matched = append(matched, exec)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i].ID > matched[j].ID })
+
+	total := len(matched)
+	if limit, err := strconv.Atoi(r.URL.Query().Get("max")); err == nil && limit > 0 && limit < total {
+		matched = matched[:limit]
+	}
+
+	list := rundeck.ExecutionList{
+		Paging:     rundeck.Paging{Count: len(matched), Total: total, Max: len(matched)},
+		Executions: make([]rundeck.ExecutionResponse, 0, len(matched)),
+	}
+	for _, exec := range matched {
+		list.Executions = append(list.Executions, s.response(exec))
+	}
+	writeJSON(w, list)
+}
+
+// response builds the API representation of an execution.
+func (s *Server) response(exec *Execution) rundeck.ExecutionResponse {
+	job := s.jobs[exec.JobID]
+	resp := rundeck.ExecutionResponse{
+		ID:          exec.ID,
+		Href:        fmt.Sprintf("%s/api/%d/execution/%d", s.URL, s.config.APIVersion, exec.ID),
+		Permalink:   fmt.Sprintf("%s/project/%s/execution/show/%d", s.URL, job.Project, exec.ID),
+		Status:      exec.Status,
+		DateStarted: dateInfo(exec.Started),
+		Job:         s.jobInfoOf(job),
+		Project:     job.Project,
+		ArgString:   argString(exec.Options),
+	}
+
+	if exec.Status.IsTerminal() {
+		resp.DateEnded = dateInfo(exec.Ended)
+		if exec.Status != rundeck.ExecutionStatusSucceeded {
+			resp.FailedNodes = job.FailedNodes
+		}
+		for _, node := range job.Nodes {
+			if exec.Status == rundeck.ExecutionStatusSucceeded || !contains(resp.FailedNodes, node) {
+				resp.SuccessfulNodes = append(resp.SuccessfulNodes, node)
+			}
+		}
+	}
+	return resp
+}
+
+// jobInfoOf builds the API representation of a job.
+func (s *Server) jobInfoOf(job *Job) rundeck.JobInfo {
+	return rundeck.JobInfo{
+		ID:              job.ID,
+		Name:            job.Name,
+		Group:           job.Group,
+		Project:         job.Project,
+		Href:            fmt.Sprintf("%s/api/%d/job/%s", s.URL, s.config.APIVersion, job.ID),
+		Permalink:       fmt.Sprintf("%s/project/%s/job/show/%s", s.URL, job.Project, job.ID),
+		AverageDuration: job.AverageDuration.Milliseconds(),
+	}
+}
+
+// writeError writes a Rundeck error response.
+func (s *Server) writeError(w http.ResponseWriter, status int, errorCode, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(rundeck.ErrorResponse{
+		Error:      true,
+		APIVersion: s.config.APIVersion,
+		ErrorCode:  errorCode,
+		Message:    message,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func dateInfo(t time.Time) rundeck.DateInfo {
+	return rundeck.DateInfo{UnixTime: t.UnixMilli(), Date: t.UTC()}
+}
+
+// argString formats options the way Rundeck shows them, as -name value.
+func argString(options map[string]string) string {
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		args = append(args, fmt.Sprintf("-%s %s", name, options[name]))
+	}
+	return strings.Join(args, " ")
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,371 @@
+package rundeck_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+	"github.com/user/jobprobe/internal/providers/fixture"
+	"github.com/user/jobprobe/internal/providers/rundeck"
+	"github.com/user/jobprobe/internal/providers/rundeck/rundecktest"
+)
+
+// newSimulator starts a simulated Rundeck with a job for each behaviour the
+// tests exercise.
+func newSimulator(t *testing.T) *rundecktest.Server {
+	t.Helper()
+
+	sim := rundecktest.NewServer(rundecktest.Config{
+		Token: "rd-token",
+		Users: map[string]string{"admin": "secret"},
+	})
+	t.Cleanup(sim.Close)
+
+	sim.AddJob(rundecktest.Job{
+		ID:       "export",
+		Name:     "export",
+		Project:  "ops",
+		Duration: 30 * time.Millisecond,
+		Nodes:    []string{"db01"},
+		Options:  []rundeck.JobOption{{Name: "table", Required: true}},
+		Log: []rundeck.LogEntry{
+			{Type: "log", Node: "db01", StepCtx: "1", Log: "starting export"},
+			{Type: "log", Node: "db01", StepCtx: "2", Log: "0 rows exported"},
+		},
+	})
+	sim.AddJob(rundecktest.Job{
+		ID:          "deploy",
+		Name:        "deploy",
+		Project:     "ops",
+		Duration:    30 * time.Millisecond,
+		Result:      rundeck.ExecutionStatusFailed,
+		Nodes:       []string{"web01", "web02"},
+		FailedNodes: []string{"web02"},
+	})
+	sim.AddJob(rundecktest.Job{ID: "stuck", Name: "stuck", Project: "ops", Hold: true})
+
+	return sim
+}
+
+// simulatorEnv returns an environment for the simulator. The rate limit is
+// raised so tests are not slowed down by it.
+func simulatorEnv(sim *rundecktest.Server, auth config.Auth) config.Environment {
+	return config.Environment{
+		Type: "rundeck",
+		URL:  sim.URL,
+		Auth: auth,
+		Spec: map[string]any{"requests_per_second": 1000},
+	}
+}
+
+func simulatorJob(name, jobID string, spec map[string]any) config.Job {
+	s := map[string]any{"job_id": jobID, "project": "ops"}
+	for k, v := range spec {
+		s[k] = v
+	}
+	return config.Job{
+		Name:         name,
+		Type:         "rundeck",
+		Environment:  "sim",
+		Spec:         s,
+		Timeout:      5 * time.Second,
+		PollInterval: 10 * time.Millisecond,
+	}
+}
+
+// run initializes a fresh provider for the environment and executes job.
+func run(t *testing.T, env config.Environment, job config.Job) (*rundeck.Provider, *providers.Result) {
+	t.Helper()
+
+	p := rundeck.NewProvider()
+	if err := p.Init(context.Background(), "sim", env); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	result, err := p.Execute(context.Background(), job, env)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	return p, result
+}
+
+func TestSimulatorRun(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Token: "rd-token"})
+
+	tests := []struct {
+		name       string
+		job        config.Job
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "succeeded",
+			job:        simulatorJob("export", "export", map[string]any{"options": map[string]any{"table": "users"}}),
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "failed nodes",
+			job:        simulatorJob("deploy", "deploy", nil),
+			wantStatus: providers.StatusFailed,
+			wantError:  "failed on nodes: [web02]",
+		},
+		{
+			name:       "unknown job",
+			job:        simulatorJob("missing", "missing", nil),
+			wantStatus: providers.StatusFailed,
+			wantError:  "failed to trigger job: rundeck error [api.error.item.doesnotexist]: Job ID does not exist: missing",
+		},
+		{
+			name: "wrong output",
+			job: func() config.Job {
+				job := simulatorJob("export", "export", nil)
+				job.Assertions.Settings = map[string]any{"log": []any{map[string]any{"step": "2", "matches": "[1-9][0-9]* rows exported"}}}
+				return job
+			}(),
+			wantStatus: providers.StatusFailed,
+			wantError:  `log (step 2): no line matches "[1-9][0-9]* rows exported"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, result := run(t, env, tt.job)
+			if result.Status != tt.wantStatus || result.Error != tt.wantError {
+				t.Errorf("result = %s %q, want %s %q", result.Status, result.Error, tt.wantStatus, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestSimulatorTimeoutAbort(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Type: "basic", Username: "admin", Password: "secret"})
+
+	job := simulatorJob("stuck", "stuck", nil)
+	job.Timeout = 100 * time.Millisecond
+
+	p, result := run(t, env, job)
+	if result.Status != providers.StatusFailed || !strings.Contains(result.Error, "timeout after 100ms") {
+		t.Fatalf("result = %s %q, want timeout", result.Status, result.Error)
+	}
+
+	id := result.Details["execution_id"].(int)
+	if aborted, err := p.Abort(context.Background(), job, env, result); !aborted || err != nil {
+		t.Fatalf("Abort() = %t, %v", aborted, err)
+	}
+	if exec, _ := sim.Execution(id); exec.Status != rundeck.ExecutionStatusAborted {
+		t.Errorf("execution status = %s, want aborted", exec.Status)
+	}
+}
+
+func TestSimulatorConcurrentPollsShareListing(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Token: "rd-token"})
+
+	p := rundeck.NewProvider()
+	if err := p.Init(context.Background(), "sim", env); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	var mu sync.Mutex
+	polls := 0
+	p.SetProgressCallback(func(jobName string, status providers.Status, message string) {
+		if strings.HasPrefix(message, "Polling...") {
+			mu.Lock()
+			polls++
+			mu.Unlock()
+		}
+	})
+
+	var wg sync.WaitGroup
+	results := make([]*providers.Result, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = p.Execute(context.Background(), simulatorJob("stuck", "stuck", nil), env)
+		}(i)
+	}
+
+	// Let both executions be polled together for a while, then end them.
+	time.Sleep(200 * time.Millisecond)
+	for id := 1; id <= 2; id++ {
+		if err := sim.Finish(id, rundeck.ExecutionStatusSucceeded); err != nil {
+			t.Fatalf("Finish(%d) error = %v", id, err)
+		}
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result == nil || result.Status != providers.StatusSucceeded {
+			t.Fatalf("results[%d] = %+v, want succeeded", i, result)
+		}
+	}
+
+	listings, fetches := 0, 0
+	for _, r := range sim.Requests() {
+		switch {
+		case strings.Contains(r, "/executions/running"):
+			listings++
+		case strings.Contains(r, "/execution/"):
+			fetches++
+		}
+	}
+	// An execution is fetched on its own while it is the only one polled
+	// and once it drops out of the listing. Every other poll is answered by
+	// a listing, and the two executions share them.
+	if listings == 0 || listings >= polls-fetches {
+		t.Errorf("listings = %d for %d polls (%d fetched on their own), want listings shared", listings, polls, fetches)
+	}
+}
+
+func TestSimulatorModes(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Token: "rd-token"})
+
+	id, err := sim.Run("stuck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		sim.Finish(id, rundeck.ExecutionStatusSucceeded)
+	}()
+
+	_, result := run(t, env, simulatorJob("stuck", "stuck", map[string]any{"mode": "attach"}))
+	if result.Status != providers.StatusSucceeded || result.Details["execution_id"] != id || result.Details["attached"] != true {
+		t.Errorf("attach result = %s %v", result.Status, result.Details)
+	}
+
+	_, result = run(t, env, simulatorJob("stuck", "stuck", map[string]any{"mode": "last_execution", "max_age": "1h"}))
+	if result.Status != providers.StatusSucceeded || result.Details["execution_id"] != id {
+		t.Errorf("last_execution result = %s %q %v", result.Status, result.Error, result.Details)
+	}
+
+	_, result = run(t, env, simulatorJob("deploy", "deploy", map[string]any{"mode": "last_execution"}))
+	if result.Status != providers.StatusFailed || result.Error != "no finished execution among the last 20" {
+		t.Errorf("last_execution without executions = %s %q", result.Status, result.Error)
+	}
+}
+
+func TestSimulatorPreflight(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Token: "rd-token"})
+
+	p := rundeck.NewProvider()
+	result, err := p.Preflight(context.Background(), simulatorJob("export", "export", nil), env)
+	if err != nil {
+		t.Fatalf("Preflight() error = %v", err)
+	}
+	if result.Status != providers.StatusFailed || result.Error != "required option 'table' is missing" {
+		t.Errorf("result = %s %q", result.Status, result.Error)
+	}
+
+	for _, req := range sim.Requests() {
+		if strings.HasSuffix(req, "/run") {
+			t.Errorf("preflight ran the job: %s", req)
+		}
+	}
+}
+
+func TestSimulatorPreflightSession(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Type: "basic", Username: "admin", Password: "secret"})
+
+	p := rundeck.NewProvider()
+	if err := p.Init(context.Background(), "sim", env); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	job := simulatorJob("export", "export", map[string]any{"options": map[string]any{"table": "users"}})
+	result, err := p.Preflight(context.Background(), job, env)
+	if err != nil {
+		t.Fatalf("Preflight() error = %v", err)
+	}
+	if result.Status != providers.StatusPreflightPassed || result.Details["job_name"] != "export" {
+		t.Errorf("result = %s %q %v", result.Status, result.Error, result.Details)
+	}
+}
+
+func TestSimulatorErrors(t *testing.T) {
+	sim := newSimulator(t)
+
+	t.Run("unsupported api version", func(t *testing.T) {
+		env := simulatorEnv(sim, config.Auth{Token: "rd-token"})
+		env.APIVersion = 50
+		err := rundeck.NewProvider().Init(context.Background(), "sim", env)
+		if err == nil || err.Error() != "api_version 50 is not supported by Rundeck 5.0.0, which supports up to 45" {
+			t.Errorf("Init() error = %v", err)
+		}
+	})
+
+	t.Run("bad token", func(t *testing.T) {
+		env := simulatorEnv(sim, config.Auth{Token: "wrong"})
+		err := rundeck.NewProvider().Init(context.Background(), "sim", env)
+		if err == nil || !strings.Contains(err.Error(), "rundeck error [unauthorized]") {
+			t.Errorf("Init() error = %v", err)
+		}
+	})
+
+	t.Run("injected error", func(t *testing.T) {
+		sim.InjectError("/job/export/run", http.StatusInternalServerError, "api.error.unknown", "database unavailable")
+		defer sim.ClearErrors()
+
+		_, result := run(t, simulatorEnv(sim, config.Auth{Token: "rd-token"}), simulatorJob("export", "export", nil))
+		if result.Error != "failed to trigger job: rundeck error [api.error.unknown]: database unavailable" {
+			t.Errorf("result = %s %q", result.Status, result.Error)
+		}
+	})
+}
+
+func TestSimulatorRecordReplay(t *testing.T) {
+	sim := newSimulator(t)
+	env := simulatorEnv(sim, config.Auth{Type: "basic", Username: "admin", Password: "secret"})
+	job := simulatorJob("export", "export", map[string]any{"options": map[string]any{"table": "users"}})
+	job.Assertions.Settings = map[string]any{"log": []any{map[string]any{"step": "2", "matches": "[1-9][0-9]* rows exported"}}}
+
+	runWith := func(wrap providers.TransportWrapper) *providers.Result {
+		pool := providers.NewClientPool()
+		defer pool.Close()
+		pool.SetTransportWrapper(wrap)
+
+		p := rundeck.NewProvider()
+		p.SetClientPool(pool)
+		if err := p.Init(context.Background(), "sim", env); err != nil {
+			t.Fatalf("Init() error = %v", err)
+		}
+		result, err := p.Execute(context.Background(), job, env)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return result
+	}
+
+	dir := t.TempDir()
+	recorder, err := fixture.NewRecorder(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := runWith(recorder.Wrap)
+	if err := recorder.Err(); err != nil {
+		t.Fatal(err)
+	}
+	sim.Close()
+
+	replayer, err := fixture.NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayed := runWith(replayer.Wrap)
+	if err := replayer.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `log (step 2): no line matches "[1-9][0-9]* rows exported"`
+	if recorded.Error != want || replayed.Error != want || replayed.Details["execution_id"] != recorded.Details["execution_id"] {
+		t.Errorf("recorded %q %v, replayed %q %v", recorded.Error, recorded.Details, replayed.Error, replayed.Details)
+	}
+}
@@ -0,0 +1,224 @@
+package rundeck
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Job modes select how a Rundeck job is checked.
+const (
+	// ModeRun triggers a new execution and waits for it. It is the default.
+	ModeRun = "run"
+
+	// ModeLastExecution checks the job's most recent finished execution
+	// without triggering one.
+	ModeLastExecution = "last_execution"
+
+	// ModeAttach follows an execution that is already running, and triggers
+	// a new one only if none is.
+	ModeAttach = "attach"
+)
+
+// JobSpec holds the Rundeck settings of a job. Settings may be given at the
+// top level of the job or in its spec sub-document, which takes precedence.
+type JobSpec struct {
+	// JobID is the UUID of the Rundeck job to run.
+	JobID string `yaml:"job_id"`
+
+	// Project is the Rundeck project the job belongs to.
+	Project string `yaml:"project"`
+
+	// Options are passed to the job as its option values.
+	Options map[string]string `yaml:"options"`
+
+	// Mode is one of run, last_execution or attach. Defaults to run.
+	Mode string `yaml:"mode"`
+
+	// MaxAge is how long ago the last execution may have finished, in
+	// last_execution mode. Zero means any age.
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// Assertions holds the Rundeck-specific assertions of a job.
+type Assertions struct {
+	// Log is checked against the execution log once the execution succeeds.
+	Log []LogAssertion `yaml:"log"`
+}
+
+// LogAssertion checks the lines of an execution log, or only those of one
+// step or node. Matches must match at least one line, NotMatches must match
+// none, and Lines matches the number of lines.
+type LogAssertion struct {
+	Step       string          `yaml:"step"`
+	Node       string          `yaml:"node"`
+	Matches    string          `yaml:"matches"`
+	NotMatches string          `yaml:"not_matches"`
+	Lines      *config.Matcher `yaml:"lines"`
+}
+
+// EnvSpec holds the Rundeck settings of an environment.
+type EnvSpec struct {
+	// RequestsPerSecond caps the requests sent to the server, shared by all
+	// jobs in the environment. Defaults to DefaultRequestsPerSecond.
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+}
+
+// DefaultRequestsPerSecond is the request rate used when an environment
+// does not set one.
+const DefaultRequestsPerSecond = 10
+
+// decodeEnvSpec decodes the environment's settings and fills in defaults.
+func decodeEnvSpec(env config.Environment) (EnvSpec, config.ValidationErrors) {
+	var spec EnvSpec
+	errs := config.DecodeEnvironmentSpec(env, &spec)
+
+	if spec.RequestsPerSecond == 0 {
+		spec.RequestsPerSecond = DefaultRequestsPerSecond
+	}
+	return spec, errs
+}
+
+// decodeJob decodes the job's settings and assertions and fills in
+// defaults.
+func decodeJob(job config.Job) (JobSpec, Assertions, config.ValidationErrors) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+
+	if spec.Mode == "" {
+		spec.Mode = ModeRun
+	}
+	return spec, assertions, errs
+}
+
+// ValidateEnvironment validates a Rundeck environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	spec, errs := decodeEnvSpec(env)
+
+	if env.URL == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "url",
+			Message: "is required",
+		})
+	}
+	if env.APIVersion != 0 && env.APIVersion < MinAPIVersion {
+		errs = append(errs, config.ValidationError{
+			Field:   "api_version",
+			Message: fmt.Sprintf("must be at least %d", MinAPIVersion),
+		})
+	}
+	switch env.Auth.Type {
+	case "basic":
+		if env.Auth.Password == "" {
+			errs = append(errs, config.ValidationError{
+				Field:   "auth.password",
+				Message: "is required for rundeck session login",
+			})
+		}
+	case "api_key", "digest":
+		errs = append(errs, config.ValidationError{
+			Field:   "auth.type",
+			Message: "rundeck supports bearer (token) or basic (session login) auth",
+		})
+	}
+	if spec.RequestsPerSecond < 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "spec.requests_per_second",
+			Message: "must not be negative",
+		})
+	}
+
+	return errs
+}
+
+// ValidateJob validates a Rundeck job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, assertions, errs := decodeJob(job)
+
+	if spec.JobID == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "spec.job_id",
+			Message: "is required for rundeck jobs",
+		})
+	}
+	if spec.Project == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "spec.project",
+			Message: "is required for rundeck jobs",
+		})
+	}
+
+	errs = append(errs, validateLogAssertions(assertions.Log)...)
+
+	switch spec.Mode {
+	case ModeRun, ModeAttach, ModeLastExecution:
+	default:
+		errs = append(errs, config.ValidationError{
+			Field:   "spec.mode",
+			Message: fmt.Sprintf("must be one of: %s, %s, %s", ModeRun, ModeLastExecution, ModeAttach),
+		})
+	}
+
+	if spec.MaxAge < 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "spec.max_age",
+			Message: "must not be negative",
+		})
+	} else if spec.MaxAge > 0 && spec.Mode != ModeLastExecution {
+		errs = append(errs, config.ValidationError{
+			Field:   "spec.max_age",
+			Message: "is only used with mode last_execution",
+		})
+	}
+
+	return errs
+}
+
+// stepPattern matches a step context such as 2 or 2/1.
+var stepPattern = regexp.MustCompile(`^[0-9]+(/[0-9]+)*$`)
+
+// validateLogAssertions validates the job's execution log assertions.
+func validateLogAssertions(assertions []LogAssertion) config.ValidationErrors {
+	var errs config.ValidationErrors
+
+	for i, a := range assertions {
+		field := fmt.Sprintf("assertions.log[%d]", i)
+
+		if a.Matches == "" && a.NotMatches == "" && a.Lines == nil {
+			errs = append(errs, config.ValidationError{
+				Field:   field,
+				Message: "must set matches, not_matches or lines",
+			})
+		}
+
+		if a.Step != "" && !stepPattern.MatchString(a.Step) {
+			errs = append(errs, config.ValidationError{
+				Field:   field + ".step",
+				Message: fmt.Sprintf("invalid step '%s', must be a step number such as 2 or 2/1", a.Step),
+			})
+		}
+
+		patterns := []struct{ name, pattern string }{{"matches", a.Matches}, {"not_matches", a.NotMatches}}
+		for _, p := range patterns {
+			if p.pattern == "" {
+				continue
+			}
+			if _, err := regexp.Compile(p.pattern); err != nil {
+				errs = append(errs, config.ValidationError{
+					Field:   field + "." + p.name,
+					Message: fmt.Sprintf("invalid pattern: %v", err),
+				})
+			}
+		}
+
+		if a.Lines != nil {
+			errs = append(errs, config.ValidateMatcher(*a.Lines, field+".lines")...)
+		}
+	}
+
+	return errs
+}
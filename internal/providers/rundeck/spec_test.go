@@ -0,0 +1,118 @@
+package rundeck
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "spec",
+			job:  config.Job{Spec: map[string]any{"job_id": "abc-123", "project": "ops"}},
+		},
+		{
+			name: "top-level fields",
+			job:  config.Job{Settings: map[string]any{"job_id": "abc-123", "project": "ops"}},
+		},
+		{
+			name: "missing job_id and project",
+			job:  config.Job{},
+			want: []string{"spec.job_id: is required for rundeck jobs", "spec.project: is required for rundeck jobs"},
+		},
+		{
+			name: "unknown spec field",
+			job:  config.Job{Spec: map[string]any{"job_id": "abc-123", "project": "ops", "jobid": "x"}},
+			want: []string{"spec.jobid: unknown field"},
+		},
+		{
+			name: "invalid log assertions",
+			job: config.Job{
+				Spec: map[string]any{"job_id": "abc-123", "project": "ops"},
+				Assertions: config.Assertions{Settings: map[string]any{"log": []any{
+					map[string]any{"step": "first", "matches": "("},
+					map[string]any{"node": "db01"},
+				}}},
+			},
+			want: []string{
+				"assertions.log[0].step: invalid step 'first'",
+				"assertions.log[0].matches: invalid pattern",
+				"assertions.log[1]: must set matches, not_matches or lines",
+			},
+		},
+		{
+			name: "last execution with max age",
+			job:  config.Job{Spec: map[string]any{"job_id": "abc-123", "project": "ops", "mode": "last_execution", "max_age": "26h"}},
+		},
+		{
+			name: "unknown mode",
+			job:  config.Job{Spec: map[string]any{"job_id": "abc-123", "project": "ops", "mode": "follow"}},
+			want: []string{"spec.mode: must be one of: run, last_execution, attach"},
+		},
+		{
+			name: "max age without last execution",
+			job:  config.Job{Spec: map[string]any{"job_id": "abc-123", "project": "ops", "mode": "attach", "max_age": "1h"}},
+			want: []string{"spec.max_age: is only used with mode last_execution"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{URL: "http://rundeck"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeJob(t *testing.T) {
+	job := config.Job{
+		Settings: map[string]any{"job_id": "top-level", "project": "ops", "options": map[string]any{"env": "prod"}},
+		Spec:     map[string]any{"job_id": "from-spec"},
+	}
+
+	spec, _, errs := decodeJob(job)
+	if len(errs) > 0 {
+		t.Fatalf("decodeJob() errors = %v", errs)
+	}
+	if spec.JobID != "from-spec" || spec.Project != "ops" || spec.Options["env"] != "prod" {
+		t.Errorf("decodeJob() = %+v, want spec job_id with top-level project and options", spec)
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	p := NewProvider()
+
+	env := config.Environment{URL: "http://rundeck", Spec: map[string]any{"requests_per_second": 2.5}}
+	if errs := p.ValidateEnvironment(env); len(errs) != 0 {
+		t.Errorf("ValidateEnvironment() = %v", errs)
+	}
+
+	env.Spec = map[string]any{"requests_per_second": -1, "rate": 5}
+	env.APIVersion = 11
+	env.Auth = config.Auth{Type: "basic", Username: "admin"}
+	errs := p.ValidateEnvironment(env)
+	for _, want := range []string{
+		"spec.rate: unknown field",
+		"spec.requests_per_second: must not be negative",
+		"api_version: must be at least 14",
+		"auth.password: is required for rundeck session login",
+	} {
+		if !strings.Contains(errs.Error(), want) {
+			t.Errorf("expected %q in %v", want, errs)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 // Package rundeck provides a Rundeck job execution provider.
 package rundeck
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ExecutionStatus represents Rundeck execution statuses.
 type ExecutionStatus string
@@ -52,6 +55,15 @@ type DateInfo struct {
 	Date     time.Time `json:"date"`
 }
 
+// Time returns the date, falling back to the Unix time in milliseconds when
+// the date is missing.
+func (d DateInfo) Time() time.Time {
+	if d.Date.IsZero() && d.UnixTime > 0 {
+		return time.UnixMilli(d.UnixTime)
+	}
+	return d.Date
+}
+
 // JobInfo represents job information in Rundeck responses.
 type JobInfo struct {
 	ID              string `json:"id"`
@@ -86,3 +98,79 @@ type ErrorResponse struct {
 	ErrorCode  string `json:"errorCode"`
 	Message    string `json:"message"`
 }
+
+// JobDefinition is the part of an exported Rundeck job definition used to
+// check options before a run.
+type JobDefinition struct {
+	ID      string      `yaml:"id"`
+	Name    string      `yaml:"name"`
+	Group   string      `yaml:"group"`
+	Options []JobOption `yaml:"options"`
+}
+
+// JobOption is an option declared by a Rundeck job.
+type JobOption struct {
+	Name        string   `yaml:"name"`
+	Required    bool     `yaml:"required"`
+	Enforced    bool     `yaml:"enforced"`
+	Values      []string `yaml:"values"`
+	Value       string   `yaml:"value"`
+	Regex       string   `yaml:"regex"`
+	Multivalued bool     `yaml:"multivalued"`
+	Delimiter   string   `yaml:"delimiter"`
+}
+
+// ExecutionList represents a page of executions from the executions listing
+// endpoints.
+type ExecutionList struct {
+	Paging     Paging              `json:"paging"`
+	Executions []ExecutionResponse `json:"executions"`
+}
+
+// Paging describes a page of a Rundeck listing.
+type Paging struct {
+	Count  int `json:"count"`
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Max    int `json:"max"`
+}
+
+// SystemInfoResponse represents the response from the system info endpoint.
+type SystemInfoResponse struct {
+	System SystemInfo `json:"system"`
+}
+
+// SystemInfo describes a Rundeck server.
+type SystemInfo struct {
+	Rundeck RundeckInfo `json:"rundeck"`
+}
+
+// RundeckInfo holds a Rundeck server's version and the newest API version
+// it supports.
+type RundeckInfo struct {
+	Version    string `json:"version"`
+	Build      string `json:"build"`
+	APIVersion int    `json:"apiversion"`
+}
+
+// ExecutionOutput represents a page of an execution's log from the output
+// endpoint. Offset is where the next page starts.
+type ExecutionOutput struct {
+	ID            json.Number `json:"id"`
+	Offset        json.Number `json:"offset"`
+	Completed     bool        `json:"completed"`
+	ExecCompleted bool        `json:"execCompleted"`
+	TotalSize     int64       `json:"totalSize"`
+	Entries       []LogEntry  `json:"entries"`
+}
+
+// LogEntry is one entry of an execution log. StepCtx identifies the step,
+// such as 2 or 2/1 for a step of a referenced job.
+type LogEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Type    string `json:"type"`
+	Log     string `json:"log"`
+	Node    string `json:"node"`
+	StepCtx string `json:"stepctx"`
+}
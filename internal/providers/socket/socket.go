@@ -0,0 +1,190 @@
+// Package socket provides TCP and UDP probe providers that check a port is
+// reachable and, optionally, that it answers with the expected data.
+package socket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultTimeout is used when neither the job nor the defaults set one.
+	defaultTimeout = 10 * time.Second
+
+	// readIdleTimeout ends a read once data has arrived and the peer has
+	// been quiet for this long.
+	readIdleTimeout = 500 * time.Millisecond
+
+	// detailOutputLimit is the number of response bytes kept in result details.
+	detailOutputLimit = 4096
+)
+
+// exchangeFunc reads the response once connected. done reports whether the
+// data read so far satisfies the assertions, so reading can stop early.
+type exchangeFunc func(conn net.Conn, deadline time.Time, limit int64, done func([]byte) bool) ([]byte, error)
+
+// probe holds the state shared by the TCP and UDP providers.
+type probe struct {
+	network    string
+	onProgress providers.ProgressCallback
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *probe) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// run dials the job's address, sends the payload and checks the response.
+// exchange performs the protocol-specific part once connected.
+func (p *probe) run(ctx context.Context, job config.Job, exchange exchangeFunc) *providers.Result {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        p.network,
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	spec, errs := decodeJobSpec(job)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid spec: %v", errs))
+	}
+	result.Details["address"] = spec.Address
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Connecting to %s...", spec.Address))
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, p.network, spec.Address)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to connect: %v", timeoutError(err, timeout)))
+	}
+	defer conn.Close()
+
+	result.Details["connect_ms"] = time.Since(result.StartedAt).Milliseconds()
+	result.Details["remote_addr"] = conn.RemoteAddr().String()
+
+	deadline, _ := ctx.Deadline()
+
+	if spec.Send != "" {
+		conn.SetWriteDeadline(deadline)
+		if _, err := conn.Write([]byte(spec.Send)); err != nil {
+			return p.fail(result, fmt.Sprintf("failed to send: %v", timeoutError(err, timeout)))
+		}
+	}
+
+	var failures []string
+
+	if exchange != nil {
+		limit := int64(job.MaxBodySize)
+		if limit <= 0 {
+			limit = int64(10 * config.Megabyte)
+		}
+
+		matched := func(data []byte) bool {
+			return len(checkResponse(data, job.Assertions.Text)) == 0
+		}
+
+		response, err := exchange(conn, deadline, limit, matched)
+		if len(response) > 0 {
+			result.Details["response"] = truncate(string(response), detailOutputLimit)
+			result.Details["response_bytes"] = len(response)
+		}
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("failed to read response: %v", timeoutError(err, timeout)))
+		}
+
+		failures = append(failures, checkResponse(response, job.Assertions.Text)...)
+	}
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("%s %s checked", strings.ToUpper(p.network), spec.Address))
+
+	return result
+}
+
+// checkResponse evaluates text assertions against the response.
+func checkResponse(data []byte, matchers []config.Matcher) []string {
+	var failures []string
+	for _, m := range matchers {
+		if err := assertion.Evaluate(string(data), nil, m); err != nil {
+			failures = append(failures, fmt.Sprintf("response: %v", err))
+		}
+	}
+	return failures
+}
+
+// isTimeout returns true if err is a network timeout.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) || isTimeout(err) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "... (truncated)"
+}
+
+// isEOF returns true if err marks the end of the stream.
+func isEOF(err error) bool {
+	return errors.Is(err, io.EOF)
+}
+
+// fail marks the result as failed with the given message.
+func (p *probe) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *probe) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewTCPProvider())
+	providers.Register(NewUDPProvider())
+}
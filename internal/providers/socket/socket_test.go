@@ -0,0 +1,240 @@
+package socket
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// startTCPServer starts a listener that sends a banner and answers PING with PONG.
+func startTCPServer(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				conn.Write([]byte("220 mail.example.com ESMTP ready\r\n"))
+
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if scanner.Text() == "PING" {
+						conn.Write([]byte("+PONG\r\n"))
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestTCPExecute(t *testing.T) {
+	addr := startTCPServer(t)
+
+	tests := []struct {
+		name       string
+		send       string
+		text       []config.Matcher
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "connect only",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "banner",
+			text:       []config.Matcher{{Matches: `^220 .*ESMTP`}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "send and expect",
+			send:       "PING\r\n",
+			text:       []config.Matcher{{Contains: "+PONG"}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "unexpected response",
+			text:       []config.Matcher{{Contains: "SSH-2.0"}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "response: expected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "smtp",
+				Type:       "tcp",
+				Spec:       map[string]any{"address": addr, "send": tt.send},
+				Timeout:    5 * time.Second,
+				Assertions: config.Assertions{Text: tt.text},
+			}
+
+			result, err := NewTCPProvider().Execute(context.Background(), job, config.Environment{Type: "tcp"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+			if result.Details["remote_addr"] != addr {
+				t.Errorf("remote_addr = %v, want %s", result.Details["remote_addr"], addr)
+			}
+		})
+	}
+}
+
+func TestTCPExecuteConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	job := config.Job{Name: "closed", Type: "tcp", Spec: map[string]any{"address": addr}, Timeout: time.Second}
+
+	result, err := NewTCPProvider().Execute(context.Background(), job, config.Environment{Type: "tcp"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Status != providers.StatusFailed || !strings.HasPrefix(result.Error, "failed to connect:") {
+		t.Errorf("Status = %s, Error = %q, want connect failure", result.Status, result.Error)
+	}
+}
+
+func TestUDPExecute(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if string(buf[:n]) == "status" {
+				conn.WriteTo([]byte("ok uptime=42"), addr)
+			}
+		}
+	}()
+
+	tests := []struct {
+		name       string
+		send       string
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "reply",
+			send:       "status",
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "no reply",
+			send:       "unknown",
+			wantStatus: providers.StatusFailed,
+			wantError:  "failed to read response: timeout after 200ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "statsd",
+				Type:       "udp",
+				Spec:       map[string]any{"address": conn.LocalAddr().String(), "send": tt.send},
+				Timeout:    200 * time.Millisecond,
+				Assertions: config.Assertions{Text: []config.Matcher{{Matches: `^ok `}}},
+			}
+
+			result, err := NewUDPProvider().Execute(context.Background(), job, config.Environment{Type: "udp"})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if result.Error != tt.wantError {
+				t.Errorf("Error = %q, want %q", result.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider config.TypeValidator
+		job      config.Job
+		want     []string
+	}{
+		{
+			name:     "valid tcp",
+			provider: NewTCPProvider(),
+			job:      config.Job{Settings: map[string]any{"address": "mail:25"}},
+		},
+		{
+			name:     "tcp without port",
+			provider: NewTCPProvider(),
+			job:      config.Job{Settings: map[string]any{"address": "mail"}},
+			want:     []string{"address: is required for tcp jobs (host:port)"},
+		},
+		{
+			name:     "udp without send",
+			provider: NewUDPProvider(),
+			job:      config.Job{Spec: map[string]any{"address": "statsd:8125"}},
+			want:     []string{"send: is required for udp jobs"},
+		},
+		{
+			name:     "invalid text pattern",
+			provider: NewTCPProvider(),
+			job: config.Job{
+				Settings:   map[string]any{"address": "mail:25"},
+				Assertions: config.Assertions{Text: []config.Matcher{{Matches: "("}}},
+			},
+			want: []string{"assertions.text[0].matches: invalid pattern"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.provider.ValidateJob(tt.job, config.Environment{})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package socket
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// JobSpec holds the settings of a tcp or udp job.
+type JobSpec struct {
+	// Address is the host:port to connect to.
+	Address string `yaml:"address"`
+
+	// Send is written to the connection once it is established.
+	Send string `yaml:"send"`
+}
+
+// decodeJobSpec decodes the job's settings.
+func decodeJobSpec(job config.Job) (JobSpec, config.ValidationErrors) {
+	var spec JobSpec
+	errs := config.DecodeJobSpec(job, &spec)
+	return spec, errs
+}
+
+// ValidateEnvironment validates a tcp or udp environment.
+func (p *probe) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	errs := config.DecodeEnvironmentSpec(env, nil)
+	return append(errs, providers.ValidateAuthType(env, p.network)...)
+}
+
+// ValidateJob validates a tcp or udp job.
+func (p *probe) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	spec, errs := decodeJobSpec(job)
+	errs = append(errs, config.DecodeAssertions(job, nil)...)
+
+	if _, _, err := net.SplitHostPort(spec.Address); err != nil {
+		errs = append(errs, config.ValidationError{
+			Field:   "address",
+			Message: fmt.Sprintf("is required for %s jobs (host:port)", p.network),
+		})
+	}
+
+	if p.network == "udp" && spec.Send == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "send",
+			Message: "is required for udp jobs",
+		})
+	}
+
+	return append(errs, config.ValidateBodyAssertions(config.Assertions{Text: job.Assertions.Text}, "assertions")...)
+}
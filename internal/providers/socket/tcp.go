@@ -0,0 +1,69 @@
+package socket
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// TCPProvider checks that a TCP port accepts connections and optionally
+// verifies a banner or the response to a payload.
+type TCPProvider struct {
+	probe
+}
+
+// NewTCPProvider creates a new TCP provider.
+func NewTCPProvider() *TCPProvider {
+	return &TCPProvider{probe{network: "tcp"}}
+}
+
+// Name returns the provider name.
+func (p *TCPProvider) Name() string {
+	return "tcp"
+}
+
+// Execute connects to the address and checks the response, if any text
+// assertions are configured.
+func (p *TCPProvider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	var exchange exchangeFunc
+	if len(job.Assertions.Text) > 0 {
+		exchange = readStream
+	}
+	return p.run(ctx, job, exchange), nil
+}
+
+// readStream reads from a stream until done reports a match, the peer closes
+// the connection, the peer goes quiet after sending data, or the limit is
+// reached.
+func readStream(conn net.Conn, deadline time.Time, limit int64, done func([]byte) bool) ([]byte, error) {
+	var data []byte
+	buf := make([]byte, 4096)
+
+	conn.SetReadDeadline(deadline)
+	for int64(len(data)) < limit {
+		n, err := conn.Read(buf)
+		data = append(data, buf[:n]...)
+
+		if n > 0 && done(data) {
+			return data, nil
+		}
+
+		if err != nil {
+			if isEOF(err) || (isTimeout(err) && len(data) > 0 && time.Now().Before(deadline)) {
+				return data, nil
+			}
+			return data, err
+		}
+
+		idle := time.Now().Add(readIdleTimeout)
+		if idle.After(deadline) {
+			idle = deadline
+		}
+		conn.SetReadDeadline(idle)
+	}
+
+	return data[:limit], nil
+}
@@ -0,0 +1,50 @@
+package socket
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// maxDatagramSize is the largest UDP payload that can be received.
+const maxDatagramSize = 65535
+
+// UDPProvider sends a datagram and checks the reply.
+type UDPProvider struct {
+	probe
+}
+
+// NewUDPProvider creates a new UDP provider.
+func NewUDPProvider() *UDPProvider {
+	return &UDPProvider{probe{network: "udp"}}
+}
+
+// Name returns the provider name.
+func (p *UDPProvider) Name() string {
+	return "udp"
+}
+
+// Execute sends the payload to the address and waits for a reply. UDP has
+// no handshake, so a reply is always required to prove the service is up.
+func (p *UDPProvider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	return p.run(ctx, job, readDatagram), nil
+}
+
+// readDatagram reads a single datagram.
+func readDatagram(conn net.Conn, deadline time.Time, limit int64, _ func([]byte) bool) ([]byte, error) {
+	size := int64(maxDatagramSize)
+	if limit < size {
+		size = limit
+	}
+
+	buf := make([]byte, size)
+	conn.SetReadDeadline(deadline)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
@@ -0,0 +1,110 @@
+package sql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// EnvSpec holds the settings of a sql environment.
+type EnvSpec struct {
+	// Driver is postgres, mysql or sqlite.
+	Driver string `yaml:"driver"`
+
+	// DSN is the driver-specific data source name.
+	DSN string `yaml:"dsn"`
+}
+
+// JobSpec holds the settings of a sql job.
+type JobSpec struct {
+	providers.QuerySpec `yaml:",inline"`
+}
+
+// Assertions holds the SQL-specific assertions of a job.
+type Assertions struct {
+	// Rows matches the number of rows returned.
+	Rows *config.Matcher `yaml:"rows"`
+
+	// Freshness checks the age of a timestamp in the first row.
+	Freshness *Freshness `yaml:"freshness"`
+}
+
+// Freshness checks that a timestamp column of the first row is recent.
+type Freshness struct {
+	// Column is the timestamp column. Empty uses the first column.
+	Column string `yaml:"column"`
+
+	// MaxAge is the oldest the timestamp may be.
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// decodeEnvSpec decodes the environment's settings.
+func decodeEnvSpec(env config.Environment) (EnvSpec, config.ValidationErrors) {
+	var spec EnvSpec
+	errs := config.DecodeEnvironmentSpec(env, &spec)
+	return spec, errs
+}
+
+// decodeJob decodes the job's settings and assertions and loads its query.
+func decodeJob(job config.Job) (JobSpec, Assertions, error) {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	if len(errs) > 0 {
+		return spec, assertions, fmt.Errorf("invalid spec: %v", errs)
+	}
+
+	query, err := spec.Load(job.Dir)
+	if err != nil {
+		return spec, assertions, err
+	}
+	spec.Query = query
+	return spec, assertions, nil
+}
+
+// ValidateEnvironment validates a SQL environment.
+func (p *Provider) ValidateEnvironment(env config.Environment) config.ValidationErrors {
+	spec, errs := decodeEnvSpec(env)
+	errs = append(errs, providers.ValidateAuthType(env, "sql")...)
+
+	if _, ok := driverNames[spec.Driver]; !ok {
+		errs = append(errs, config.ValidationError{
+			Field:   "driver",
+			Message: fmt.Sprintf("invalid driver '%s', must be one of: postgres, mysql, sqlite", spec.Driver),
+		})
+	}
+
+	if spec.DSN == "" {
+		errs = append(errs, config.ValidationError{
+			Field:   "dsn",
+			Message: "is required for sql environments",
+		})
+	}
+
+	return errs
+}
+
+// ValidateJob validates a SQL job.
+func (p *Provider) ValidateJob(job config.Job, env config.Environment) config.ValidationErrors {
+	var spec JobSpec
+	var assertions Assertions
+	errs := config.DecodeJobSpec(job, &spec)
+	errs = append(errs, config.DecodeAssertions(job, &assertions)...)
+	errs = append(errs, spec.QuerySpec.Validate(job, "sql")...)
+
+	if assertions.Rows != nil {
+		errs = append(errs, config.ValidateMatcher(*assertions.Rows, "assertions.rows")...)
+	}
+
+	if f := assertions.Freshness; f != nil && f.MaxAge <= 0 {
+		errs = append(errs, config.ValidationError{
+			Field:   "assertions.freshness.max_age",
+			Message: "must be greater than 0",
+		})
+	}
+
+	return append(errs, config.ValidateBodyAssertions(config.Assertions{JSON: job.Assertions.JSON}, "assertions")...)
+}
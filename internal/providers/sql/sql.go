@@ -0,0 +1,301 @@
+// Package sql provides a provider that runs read-only queries against
+// PostgreSQL, MySQL and SQLite databases and checks the result.
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/user/jobprobe/internal/assertion"
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+const (
+	// defaultTimeout is used when neither the job nor the defaults set one.
+	defaultTimeout = 30 * time.Second
+
+	// maxRows is the number of rows kept for JSON assertions. Further rows
+	// are only counted.
+	maxRows = 1000
+
+	// detailRowLimit is the number of rows included in result details.
+	detailRowLimit = 10
+)
+
+// driverNames maps environment drivers to database/sql driver names.
+var driverNames = map[string]string{
+	"postgres": "pgx",
+	"mysql":    "mysql",
+	"sqlite":   "sqlite",
+}
+
+// resultSet holds the rows returned by a query.
+type resultSet struct {
+	Columns []string
+	Rows    []map[string]any
+	Count   int
+}
+
+// document returns the JSON document that JSON assertions are evaluated
+// against. row is the first row, so scalar queries read as $.row.<column>.
+func (rs *resultSet) document() map[string]any {
+	var row map[string]any
+	if len(rs.Rows) > 0 {
+		row = rs.Rows[0]
+	}
+	return map[string]any{
+		"columns":   rs.Columns,
+		"row":       row,
+		"rows":      rs.Rows,
+		"row_count": rs.Count,
+	}
+}
+
+// Provider implements the SQL query provider.
+type Provider struct {
+	onProgress providers.ProgressCallback
+}
+
+// NewProvider creates a new SQL provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "sql"
+}
+
+// SetProgressCallback sets the progress callback.
+func (p *Provider) SetProgressCallback(cb providers.ProgressCallback) {
+	p.onProgress = cb
+}
+
+// Execute runs the job's query in a read-only transaction and checks the row
+// count, values and timestamp freshness.
+func (p *Provider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        "sql",
+		Status:      providers.StatusRunning,
+		StartedAt:   time.Now(),
+		Details:     make(map[string]interface{}),
+	}
+
+	envSpec, errs := decodeEnvSpec(env)
+	if len(errs) > 0 {
+		return p.fail(result, fmt.Sprintf("invalid environment spec: %v", errs)), nil
+	}
+	result.Details["driver"] = envSpec.Driver
+
+	spec, assertions, err := decodeJob(job)
+	if err != nil {
+		return p.fail(result, err.Error()), nil
+	}
+
+	driverName, ok := driverNames[envSpec.Driver]
+	if !ok {
+		return p.fail(result, fmt.Sprintf("unsupported driver %q", envSpec.Driver)), nil
+	}
+
+	timeout := job.GetTimeout(config.Defaults{Timeout: defaultTimeout})
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	db, err := dbsql.Open(driverName, envSpec.DSN)
+	if err != nil {
+		return p.fail(result, fmt.Sprintf("failed to open database: %v", err)), nil
+	}
+	defer db.Close()
+
+	p.reportProgress(job.Name, providers.StatusRunning, fmt.Sprintf("Querying %s database...", envSpec.Driver))
+
+	rs, err := runQuery(ctx, db, envSpec.Driver, spec.Query)
+	if err != nil {
+		return p.fail(result, timeoutError(err, timeout).Error()), nil
+	}
+
+	result.Details["columns"] = rs.Columns
+	result.Details["row_count"] = rs.Count
+	if len(rs.Rows) > detailRowLimit {
+		result.Details["rows"] = rs.Rows[:detailRowLimit]
+	} else {
+		result.Details["rows"] = rs.Rows
+	}
+
+	result.Status = providers.StatusSucceeded
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+
+	var failures []string
+
+	if assertions.Rows != nil {
+		if err := assertion.Evaluate(rs.Count, nil, *assertions.Rows); err != nil {
+			failures = append(failures, fmt.Sprintf("rows: %v", err))
+		}
+	}
+
+	if f := assertions.Freshness; f != nil {
+		age, err := checkFreshness(rs, *f, result.StartedAt)
+		if age != nil {
+			result.Details["age"] = age.Round(time.Second).String()
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("freshness: %v", err))
+		}
+	}
+
+	if len(job.Assertions.JSON) > 0 {
+		body, err := json.Marshal(rs.document())
+		if err != nil {
+			return p.fail(result, fmt.Sprintf("failed to encode rows: %v", err)), nil
+		}
+		failures = append(failures, assertion.CheckBody(body, assertion.FormatJSON, config.Assertions{JSON: job.Assertions.JSON})...)
+	}
+
+	if job.Assertions.MaxDuration > 0 && result.Duration > job.Assertions.MaxDuration {
+		failures = append(failures, fmt.Sprintf("duration %s exceeded max %s",
+			result.Duration.Round(time.Millisecond), job.Assertions.MaxDuration))
+	}
+
+	if len(failures) > 0 {
+		result.Status = providers.StatusFailed
+		result.Error = strings.Join(failures, "; ")
+	}
+
+	p.reportProgress(job.Name, result.Status, fmt.Sprintf("%d row(s)", rs.Count))
+
+	return result, nil
+}
+
+// runQuery runs query in a read-only transaction that is always rolled back.
+// SQLite ignores the transaction's read-only flag, so the connection is put
+// in query_only mode instead.
+func runQuery(ctx context.Context, db *dbsql.DB, driver, query string) (*resultSet, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close()
+
+	if driver == "sqlite" {
+		if _, err := conn.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+			return nil, fmt.Errorf("failed to enable read-only mode: %w", err)
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, &dbsql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start read-only transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	rs := &resultSet{Columns: columns, Rows: []map[string]any{}}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	for rows.Next() {
+		rs.Count++
+		if len(rs.Rows) >= maxRows {
+			continue
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = normalize(values[i])
+		}
+		rs.Rows = append(rs.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	return rs, nil
+}
+
+// checkFreshness returns the age of the freshness column in the first row
+// and an error if it is older than the maximum age.
+func checkFreshness(rs *resultSet, f Freshness, now time.Time) (*time.Duration, error) {
+	if len(rs.Rows) == 0 {
+		return nil, errors.New("query returned no rows")
+	}
+
+	column := f.Column
+	if column == "" {
+		column = rs.Columns[0]
+	}
+	value, ok := rs.Rows[0][column]
+	if !ok {
+		return nil, fmt.Errorf("column %s not found", column)
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%s is NULL", column)
+	}
+
+	ts, ok := parseTimestamp(value)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a timestamp: %v", column, value)
+	}
+
+	age := now.Sub(ts)
+	if age > f.MaxAge {
+		return &age, fmt.Errorf("%s is %s old, max %s", column, age.Round(time.Second), f.MaxAge)
+	}
+	return &age, nil
+}
+
+// timeoutError replaces a deadline error with a readable timeout message.
+func timeoutError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timeout after %s", timeout)
+	}
+	return err
+}
+
+// fail marks the result as failed with the given message.
+func (p *Provider) fail(result *providers.Result, message string) *providers.Result {
+	result.Status = providers.StatusFailed
+	result.Error = message
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt)
+	return result
+}
+
+// reportProgress reports progress if a callback is set.
+func (p *Provider) reportProgress(jobName string, status providers.Status, message string) {
+	if p.onProgress != nil {
+		p.onProgress(jobName, status, message)
+	}
+}
+
+func init() {
+	providers.Register(NewProvider())
+}
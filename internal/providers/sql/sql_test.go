@@ -0,0 +1,228 @@
+package sql
+
+import (
+	"context"
+	dbsql "database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// newDatabase creates a SQLite database with an orders table whose newest
+// row was updated two hours ago.
+func newDatabase(t *testing.T) string {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "orders.db")
+	db, err := dbsql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now().UTC()
+	statements := []string{
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT, total REAL, updated_at DATETIME)",
+		"INSERT INTO orders VALUES (1, 'shipped', 12.5, '" + now.Add(-30*time.Hour).Format("2006-01-02 15:04:05") + "')",
+		"INSERT INTO orders VALUES (2, 'shipped', 20, '" + now.Add(-2*time.Hour).Format("2006-01-02 15:04:05") + "')",
+		"INSERT INTO orders VALUES (3, 'pending', 7.25, NULL)",
+	}
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to run %q: %v", stmt, err)
+		}
+	}
+
+	return dsn
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestExecute(t *testing.T) {
+	dsn := newDatabase(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		assertions config.Assertions
+		wantStatus providers.Status
+		wantError  string
+	}{
+		{
+			name:       "row count",
+			query:      "SELECT id FROM orders WHERE status = 'shipped'",
+			assertions: config.Assertions{Settings: map[string]any{"rows": map[string]any{"equals": 2}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "row count too low",
+			query:      "SELECT id FROM orders WHERE status = 'cancelled'",
+			assertions: config.Assertions{Settings: map[string]any{"rows": map[string]any{"greater_than": 0}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "rows: expected greater than 0, got 0",
+		},
+		{
+			name:  "scalar values",
+			query: "SELECT count(*) AS total, sum(total) AS revenue FROM orders",
+			assertions: config.Assertions{JSON: []config.JSONAssertion{
+				{Path: "$.row.total", Matcher: config.Matcher{Equals: 3}},
+				{Path: "$.row.revenue", Matcher: config.Matcher{GreaterThan: floatPtr(39)}},
+				{Path: "$.row_count", Matcher: config.Matcher{Equals: 1}},
+			}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "fresh",
+			query:      "SELECT max(updated_at) FROM orders",
+			assertions: config.Assertions{Settings: map[string]any{"freshness": map[string]any{"max_age": "26h"}}},
+			wantStatus: providers.StatusSucceeded,
+		},
+		{
+			name:       "stale",
+			query:      "SELECT min(updated_at) AS oldest, count(*) AS n FROM orders",
+			assertions: config.Assertions{Settings: map[string]any{"freshness": map[string]any{"column": "oldest", "max_age": "26h"}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "freshness: oldest is 30h0m",
+		},
+		{
+			name:       "null timestamp",
+			query:      "SELECT updated_at FROM orders WHERE id = 3",
+			assertions: config.Assertions{Settings: map[string]any{"freshness": map[string]any{"max_age": "1h"}}},
+			wantStatus: providers.StatusFailed,
+			wantError:  "freshness: updated_at is NULL",
+		},
+		{
+			name:       "writes rejected",
+			query:      "DELETE FROM orders RETURNING id",
+			wantStatus: providers.StatusFailed,
+			wantError:  "query failed:",
+		},
+		{
+			name:       "invalid query",
+			query:      "SELECT * FROM shipments",
+			wantStatus: providers.StatusFailed,
+			wantError:  "no such table: shipments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := config.Job{
+				Name:       "orders",
+				Type:       "sql",
+				Spec:       map[string]any{"query": tt.query},
+				Timeout:    5 * time.Second,
+				Assertions: tt.assertions,
+			}
+			env := config.Environment{Type: "sql", Spec: map[string]any{"driver": "sqlite", "dsn": dsn}}
+
+			result, err := NewProvider().Execute(context.Background(), job, env)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %s, want %s (error: %s)", result.Status, tt.wantStatus, result.Error)
+			}
+			if !strings.Contains(result.Error, tt.wantError) {
+				t.Errorf("Error = %q, want it to contain %q", result.Error, tt.wantError)
+			}
+		})
+	}
+
+	// The DELETE above must not have removed anything.
+	job := config.Job{
+		Name:       "count",
+		Spec:       map[string]any{"query": "SELECT id FROM orders"},
+		Assertions: config.Assertions{Settings: map[string]any{"rows": map[string]any{"equals": 3}}},
+	}
+	result, _ := NewProvider().Execute(context.Background(), job, config.Environment{Spec: map[string]any{"driver": "sqlite", "dsn": dsn}})
+	if result.Status != providers.StatusSucceeded {
+		t.Errorf("rows were modified: %s", result.Error)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2025, 3, 1, 4, 30, 0, 0, time.UTC)
+
+	tests := []any{
+		"2025-03-01T04:30:00Z",
+		"2025-03-01 06:30:00+02:00",
+		"2025-03-01 04:30:00",
+		"2025-03-01 04:30:00.000000",
+		"2025-03-01 04:30:00+00",
+		int64(1740803400),
+	}
+
+	for _, value := range tests {
+		got, ok := parseTimestamp(value)
+		if !ok || !got.Equal(want) {
+			t.Errorf("parseTimestamp(%v) = %v, %v, want %v", value, got, ok, want)
+		}
+	}
+
+	if _, ok := parseTimestamp("yesterday"); ok {
+		t.Error("parseTimestamp(\"yesterday\") should fail")
+	}
+}
+
+func TestValidateJob(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.Job
+		want []string
+	}{
+		{
+			name: "valid",
+			job: config.Job{
+				Settings:   map[string]any{"query": "SELECT max(updated_at) FROM orders"},
+				Assertions: config.Assertions{Settings: map[string]any{"freshness": map[string]any{"max_age": "26h"}}},
+			},
+		},
+		{
+			name: "missing query",
+			job:  config.Job{Assertions: config.Assertions{Settings: map[string]any{"rows": map[string]any{"matches": "("}}}},
+			want: []string{"query: is required for sql jobs", "assertions.rows.matches: invalid pattern"},
+		},
+		{
+			name: "freshness without max_age",
+			job: config.Job{
+				Settings:   map[string]any{"query": "SELECT 1"},
+				Assertions: config.Assertions{Settings: map[string]any{"freshness": map[string]any{"column": "updated_at"}}},
+			},
+			want: []string{"assertions.freshness.max_age: must be greater than 0"},
+		},
+	}
+
+	p := NewProvider()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := p.ValidateJob(tt.job, config.Environment{Type: "sql"})
+			if len(errs) != len(tt.want) {
+				t.Fatalf("ValidateJob() = %v, want %d errors", errs, len(tt.want))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(errs.Error(), want) {
+					t.Errorf("expected %q in %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	env := config.Environment{Settings: map[string]any{"driver": "postgres"}}
+	errs := NewProvider().ValidateEnvironment(env)
+	if len(errs) != 1 || !strings.Contains(errs.Error(), "dsn: is required for sql environments") {
+		t.Errorf("ValidateEnvironment() = %v, want only the dsn error", errs)
+	}
+
+	env = config.Environment{Spec: map[string]any{"driver": "oracle", "dsn": "db"}}
+	if errs := NewProvider().ValidateEnvironment(env); !strings.Contains(errs.Error(), "driver: invalid driver 'oracle'") {
+		t.Errorf("ValidateEnvironment() = %v, want driver error", errs)
+	}
+}
@@ -0,0 +1,51 @@
+package sql
+
+import (
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the textual timestamp formats accepted for freshness
+// checks. Drivers return aggregates such as max(updated_at) as text when the
+// column type is lost, and MySQL returns DATETIME as text unless parseTime is
+// set in the DSN.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// normalize converts a scanned column value into a JSON-friendly value.
+// Byte slices become strings and times are formatted as RFC 3339.
+func normalize(v any) any {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return val
+	}
+}
+
+// parseTimestamp interprets a normalized column value as a timestamp.
+// Timestamps without a zone are taken to be UTC; integers are Unix seconds.
+func parseTimestamp(v any) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, true
+	case int64:
+		return time.Unix(val, 0), true
+	case string:
+		s := strings.TrimSpace(val)
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
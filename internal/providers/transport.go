@@ -0,0 +1,178 @@
+package providers
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// Default transport settings used when an environment does not override them.
+const (
+	DefaultKeepAlive           = 30 * time.Second
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 10
+	DefaultRequestTimeout      = 30 * time.Second
+)
+
+// TransportWrapper wraps the transport of an environment's pooled client,
+// for example to record or replay its traffic.
+type TransportWrapper func(name string, env config.Environment, next http.RoundTripper) http.RoundTripper
+
+// ClientPool caches one HTTP client per environment so that connections,
+// TLS sessions and HTTP/2 streams are reused across jobs.
+type ClientPool struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+	wrap    TransportWrapper
+}
+
+// NewClientPool creates a new, empty client pool.
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		clients: make(map[string]*http.Client),
+	}
+}
+
+// SetTransportWrapper sets a wrapper applied to the transport of every
+// client the pool creates afterwards.
+func (p *ClientPool) SetTransportWrapper(wrap TransportWrapper) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.wrap = wrap
+}
+
+// Client returns the cached HTTP client for the named environment, creating
+// it on first use. Environments with digest auth get a client that answers
+// challenges, so all their jobs share the cached challenge. A nil pool
+// returns a fresh, uncached client.
+func (p *ClientPool) Client(name string, env config.Environment) *http.Client {
+	if p == nil {
+		return NewEnvironmentClient(env)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if client, ok := p.clients[name]; ok {
+		return client
+	}
+
+	client := NewHTTPClient(env.Transport)
+	if p.wrap != nil {
+		client.Transport = p.wrap(name, env, client.Transport)
+	}
+	client.Transport = authTransport(env, client.Transport)
+	p.clients[name] = client
+	return client
+}
+
+// Close releases idle connections held by all cached clients.
+func (p *ClientPool) Close() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for name, client := range p.clients {
+		client.CloseIdleConnections()
+		delete(p.clients, name)
+	}
+}
+
+// NewHTTPClient creates an HTTP client with a transport tuned by cfg.
+func NewHTTPClient(cfg config.Transport) *http.Client {
+	return &http.Client{
+		Timeout:   DefaultRequestTimeout,
+		Transport: NewTransport(cfg),
+	}
+}
+
+// NewEnvironmentClient creates a dedicated HTTP client for env, answering
+// digest challenges when the environment uses digest auth.
+func NewEnvironmentClient(env config.Environment) *http.Client {
+	client := NewHTTPClient(env.Transport)
+	client.Transport = authTransport(env, client.Transport)
+	return client
+}
+
+// authTransport wraps next with the authentication the transport handles
+// rather than each request, which is only digest.
+func authTransport(env config.Environment, next http.RoundTripper) http.RoundTripper {
+	if env.Auth.Type == "digest" {
+		return NewDigestTransport(env.Auth.Username, env.Auth.Password, next)
+	}
+	return next
+}
+
+// ApplyAuth sets the request headers for auth. api_key auth uses the
+// X-API-Key header unless auth names another. Digest auth is answered by the
+// transport instead; see authTransport.
+func ApplyAuth(req *http.Request, auth config.Auth) {
+	switch auth.Type {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case "basic":
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case "api_key":
+		header := auth.Header
+		if header == "" {
+			header = "X-API-Key"
+		}
+		req.Header.Set(header, auth.APIKey)
+	}
+}
+
+// NewTransport creates an HTTP transport tuned by cfg.
+func NewTransport(cfg config.Transport) *http.Transport {
+	keepAlive := cfg.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = DefaultKeepAlive
+	}
+
+	idleConnTimeout := cfg.IdleConnTimeout
+	if idleConnTimeout == 0 {
+		idleConnTimeout = DefaultIdleConnTimeout
+	}
+
+	maxIdleConns := cfg.MaxIdleConns
+	if maxIdleConns == 0 {
+		maxIdleConns = DefaultMaxIdleConns
+	}
+
+	maxIdleConnsPerHost := cfg.MaxIdleConnsPerHost
+	if maxIdleConnsPerHost == 0 {
+		maxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: keepAlive,
+	}
+
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          maxIdleConns,
+		MaxIdleConnsPerHost:   maxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		DisableKeepAlives:     cfg.DisableKeepAlives,
+	}
+
+	if !cfg.HTTP2Enabled() {
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+
+	return transport
+}
@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/jobprobe/internal/config"
+)
+
+// RequireURL reports an environment without a URL.
+func RequireURL(env config.Environment) config.ValidationErrors {
+	if env.URL == "" {
+		return config.ValidationErrors{{Field: "url", Message: "is required"}}
+	}
+	return nil
+}
+
+// ValidateURLScheme reports an environment URL that does not start with one
+// of schemes. An empty URL is left to RequireURL.
+func ValidateURLScheme(env config.Environment, schemes ...string) config.ValidationErrors {
+	if env.URL == "" {
+		return nil
+	}
+	for _, scheme := range schemes {
+		if strings.HasPrefix(env.URL, scheme) {
+			return nil
+		}
+	}
+	return config.ValidationErrors{{
+		Field:   "url",
+		Message: fmt.Sprintf("must start with %s", strings.Join(schemes, " or ")),
+	}}
+}
+
+// ValidateAuthType reports an auth type the provider would ignore. kind
+// names the environment type in messages; supported lists the auth types
+// the provider applies. No auth is always accepted.
+func ValidateAuthType(env config.Environment, kind string, supported ...string) config.ValidationErrors {
+	if env.Auth.Type == "" || env.Auth.Type == "none" {
+		return nil
+	}
+	for _, t := range supported {
+		if env.Auth.Type == t {
+			return nil
+		}
+	}
+
+	message := fmt.Sprintf("%s environments do not use auth", kind)
+	if n := len(supported); n > 0 {
+		types := supported[n-1]
+		if n > 1 {
+			types = strings.Join(supported[:n-1], ", ") + " or " + types
+		}
+		message = fmt.Sprintf("%s environments support %s auth", kind, types)
+	}
+	return config.ValidationErrors{{Field: "auth.type", Message: message}}
+}
@@ -2,26 +2,54 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/user/jobprobe/internal/config"
 	"github.com/user/jobprobe/internal/providers"
 )
 
+// abortTimeout bounds the request that stops a remote execution after a job
+// is interrupted.
+const abortTimeout = 30 * time.Second
+
 // Executor executes jobs using the appropriate provider.
 type Executor struct {
 	registry   *providers.Registry
+	clients    *providers.ClientPool
 	onProgress providers.ProgressCallback
+
+	mu          sync.Mutex
+	initialized map[string]*initState
+	used        map[string]providers.Provider
+}
+
+// initState holds the outcome of a provider's Init for one environment.
+type initState struct {
+	once sync.Once
+	err  error
 }
 
 // NewExecutor creates a new executor.
 func NewExecutor(registry *providers.Registry) *Executor {
 	return &Executor{
-		registry: registry,
+		registry:    registry,
+		initialized: make(map[string]*initState),
+		used:        make(map[string]providers.Provider),
 	}
 }
 
-// SetProgressCallback sets the progress callback.
+// SetClientPool sets the HTTP client pool shared with providers. It must be
+// called before the first job.
+func (e *Executor) SetClientPool(pool *providers.ClientPool) {
+	e.clients = pool
+}
+
+// SetProgressCallback sets the progress callback. It must be called before
+// the first job.
 func (e *Executor) SetProgressCallback(cb providers.ProgressCallback) {
 	e.onProgress = cb
 }
@@ -39,20 +67,158 @@ func (e *Executor) Execute(ctx context.Context, job config.Job, env config.Envir
 		}, nil
 	}
 
-	if setter, ok := provider.(interface{ SetProgressCallback(providers.ProgressCallback) }); ok {
-		setter.SetProgressCallback(e.onProgress)
+	if err := e.init(ctx, provider, job.Environment, env); err != nil {
+		return &providers.Result{
+			JobName:     job.Name,
+			Environment: job.Environment,
+			Type:        job.Type,
+			Status:      providers.StatusFailed,
+			Error:       fmt.Sprintf("failed to initialize %s provider: %v", job.Type, err),
+		}, nil
 	}
 
 	result, err := provider.Execute(ctx, job, env)
 	if err != nil {
-		return &providers.Result{
+		result = &providers.Result{
 			JobName:     job.Name,
 			Environment: job.Environment,
 			Type:        job.Type,
 			Status:      providers.StatusFailed,
 			Error:       err.Error(),
-		}, nil
+		}
+	}
+
+	if aborter, ok := provider.(providers.Aborter); ok && result.RemoteRunning {
+		e.abort(aborter, job, env, result)
 	}
 
 	return result, nil
 }
+
+// Preflight checks a job without running it. Jobs whose provider has no
+// preflight are reported as skipped.
+func (e *Executor) Preflight(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	result := &providers.Result{
+		JobName:     job.Name,
+		Environment: job.Environment,
+		Type:        job.Type,
+	}
+
+	provider, err := e.registry.Get(job.Type)
+	if err != nil {
+		result.Status = providers.StatusFailed
+		result.Error = fmt.Sprintf("provider not found: %s", job.Type)
+		return result, nil
+	}
+
+	preflighter, ok := provider.(providers.Preflighter)
+	if !ok {
+		result.Status = providers.StatusSkipped
+		result.Details = map[string]interface{}{"reason": fmt.Sprintf("%s provider does not support dry run", job.Type)}
+		return result, nil
+	}
+
+	if err := e.init(ctx, provider, job.Environment, env); err != nil {
+		result.Status = providers.StatusFailed
+		result.Error = fmt.Sprintf("failed to initialize %s provider: %v", job.Type, err)
+		return result, nil
+	}
+
+	checked, err := preflighter.Preflight(ctx, job, env)
+	if err != nil {
+		result.Status = providers.StatusFailed
+		result.Error = err.Error()
+		return result, nil
+	}
+	return checked, nil
+}
+
+// init records the provider as used and runs its Init hook the first time it
+// sees the environment. The outcome is remembered, so a failed Init fails
+// later jobs in the environment without retrying. Init runs outside e.mu, so
+// a slow environment only holds up jobs waiting for that same environment.
+//
+// The provider gets the executor's client pool and progress callback once,
+// under e.mu, before its first job. Providers are shared instances, so
+// setting them on every job would race with jobs already running.
+func (e *Executor) init(ctx context.Context, provider providers.Provider, envName string, env config.Environment) error {
+	initializer, ok := provider.(providers.Initializer)
+
+	e.mu.Lock()
+	if _, seen := e.used[provider.Name()]; !seen {
+		if reporter, ok := provider.(providers.ProgressReporter); ok {
+			reporter.SetProgressCallback(e.onProgress)
+		}
+		if user, ok := provider.(providers.ClientPoolUser); ok {
+			user.SetClientPool(e.clients)
+		}
+		e.used[provider.Name()] = provider
+	}
+	var state *initState
+	if ok {
+		key := provider.Name() + "/" + envName
+		state = e.initialized[key]
+		if state == nil {
+			state = &initState{}
+			e.initialized[key] = state
+		}
+	}
+	e.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	state.once.Do(func() {
+		state.err = initializer.Init(ctx, envName, env)
+	})
+	return state.err
+}
+
+// abort asks the provider to stop the remote execution a result left
+// running. It runs on a fresh context because the job's context is usually
+// done by now.
+func (e *Executor) abort(aborter providers.Aborter, job config.Job, env config.Environment, result *providers.Result) {
+	ctx, cancel := context.WithTimeout(context.Background(), abortTimeout)
+	defer cancel()
+
+	aborted, err := aborter.Abort(ctx, job, env, result)
+	if err == nil && !aborted {
+		return
+	}
+	if result.Details == nil {
+		result.Details = make(map[string]interface{})
+	}
+	if err != nil {
+		result.Details["abort_error"] = err.Error()
+		return
+	}
+	result.Details["remote_aborted"] = true
+	if e.onProgress != nil {
+		e.onProgress(job.Name, result.Status, "Remote execution aborted")
+	}
+}
+
+// Close closes every provider used by the executor that holds state between
+// jobs, and returns their errors joined.
+func (e *Executor) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.used))
+	for name := range e.used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if closer, ok := e.used[name].(providers.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s provider: %w", name, err))
+			}
+		}
+	}
+	e.used = make(map[string]providers.Provider)
+	e.initialized = make(map[string]*initState)
+	return errors.Join(errs...)
+}
@@ -0,0 +1,235 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/jobprobe/internal/config"
+	"github.com/user/jobprobe/internal/providers"
+)
+
+// lifecycleProvider records the lifecycle hooks the executor calls.
+type lifecycleProvider struct {
+	initErr  error
+	status   providers.Status
+	running  bool
+	inits    []string
+	aborts   int
+	closes   int
+	abortErr error
+}
+
+func (p *lifecycleProvider) Name() string { return "fake" }
+
+func (p *lifecycleProvider) Init(ctx context.Context, name string, env config.Environment) error {
+	p.inits = append(p.inits, env.URL)
+	return p.initErr
+}
+
+func (p *lifecycleProvider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	return &providers.Result{JobName: job.Name, Status: p.status, RemoteRunning: p.running, Details: map[string]interface{}{}}, nil
+}
+
+func (p *lifecycleProvider) Abort(ctx context.Context, job config.Job, env config.Environment, result *providers.Result) (bool, error) {
+	p.aborts++
+	return p.abortErr == nil, p.abortErr
+}
+
+func (p *lifecycleProvider) Close() error {
+	p.closes++
+	return nil
+}
+
+func newTestExecutor(p providers.Provider) *Executor {
+	registry := providers.NewRegistry()
+	registry.Register(p)
+	return NewExecutor(registry)
+}
+
+func TestExecutorInitOncePerEnvironment(t *testing.T) {
+	p := &lifecycleProvider{status: providers.StatusSucceeded}
+	e := newTestExecutor(p)
+
+	jobs := []struct{ env, url string }{{"a", "http://a"}, {"a", "http://a"}, {"b", "http://b"}}
+	for _, j := range jobs {
+		job := config.Job{Name: "job", Type: "fake", Environment: j.env}
+		if _, err := e.Execute(context.Background(), job, config.Environment{URL: j.url}); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	}
+
+	if strings.Join(p.inits, ",") != "http://a,http://b" {
+		t.Errorf("Init calls = %v, want one per environment", p.inits)
+	}
+	if p.aborts != 0 {
+		t.Errorf("Abort called %d times for successful jobs", p.aborts)
+	}
+
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if p.closes != 1 {
+		t.Errorf("Close calls = %d, want 1", p.closes)
+	}
+}
+
+func TestExecutorInitFailure(t *testing.T) {
+	p := &lifecycleProvider{initErr: errors.New("login refused"), status: providers.StatusSucceeded}
+	e := newTestExecutor(p)
+
+	for i := 0; i < 2; i++ {
+		result, _ := e.Execute(context.Background(), config.Job{Name: "job", Type: "fake", Environment: "a"}, config.Environment{})
+		if result.Status != providers.StatusFailed || result.Error != "failed to initialize fake provider: login refused" {
+			t.Errorf("result = %s %q, want init failure", result.Status, result.Error)
+		}
+	}
+	if len(p.inits) != 1 {
+		t.Errorf("Init calls = %d, want 1", len(p.inits))
+	}
+}
+
+// blockingInitProvider blocks Init for the "slow" environment until
+// release is closed, and counts Init calls per environment.
+type blockingInitProvider struct {
+	release chan struct{}
+
+	mu    sync.Mutex
+	inits map[string]int
+}
+
+func (p *blockingInitProvider) Name() string { return "fake" }
+
+func (p *blockingInitProvider) Init(ctx context.Context, name string, env config.Environment) error {
+	p.mu.Lock()
+	p.inits[name]++
+	p.mu.Unlock()
+
+	if name == "slow" {
+		<-p.release
+	}
+	return nil
+}
+
+func (p *blockingInitProvider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	return &providers.Result{JobName: job.Name, Status: providers.StatusSucceeded}, nil
+}
+
+func TestExecutorInitDoesNotBlockOtherEnvironments(t *testing.T) {
+	p := &blockingInitProvider{release: make(chan struct{}), inits: make(map[string]int)}
+	e := newTestExecutor(p)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Execute(context.Background(), config.Job{Name: "slow", Type: "fake", Environment: "slow"}, config.Environment{})
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		e.Execute(context.Background(), config.Job{Name: "fast", Type: "fake", Environment: "fast"}, config.Environment{})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job in another environment waited for a slow Init")
+	}
+
+	close(p.release)
+	wg.Wait()
+
+	if p.inits["slow"] != 1 || p.inits["fast"] != 1 {
+		t.Errorf("Init calls = %v, want one per environment", p.inits)
+	}
+}
+
+func TestExecutorAbort(t *testing.T) {
+	t.Run("aborted", func(t *testing.T) {
+		p := &lifecycleProvider{status: providers.StatusFailed, running: true}
+		result, _ := newTestExecutor(p).Execute(context.Background(), config.Job{Name: "job", Type: "fake"}, config.Environment{})
+		if p.aborts != 1 || result.Details["remote_aborted"] != true {
+			t.Errorf("aborts = %d, details = %v", p.aborts, result.Details)
+		}
+	})
+
+	t.Run("finished", func(t *testing.T) {
+		p := &lifecycleProvider{status: providers.StatusFailed}
+		result, _ := newTestExecutor(p).Execute(context.Background(), config.Job{Name: "job", Type: "fake"}, config.Environment{})
+		if p.aborts != 0 || len(result.Details) != 0 {
+			t.Errorf("aborts = %d, details = %v, want no abort for a finished execution", p.aborts, result.Details)
+		}
+	})
+
+	t.Run("abort error", func(t *testing.T) {
+		p := &lifecycleProvider{status: providers.StatusTimedOut, running: true, abortErr: errors.New("forbidden")}
+		result, _ := newTestExecutor(p).Execute(context.Background(), config.Job{Name: "job", Type: "fake"}, config.Environment{})
+		if result.Details["abort_error"] != "forbidden" || result.Status != providers.StatusTimedOut {
+			t.Errorf("result = %s, details = %v", result.Status, result.Details)
+		}
+	})
+}
+
+func TestExecutorPreflightSkipsUnsupportedProviders(t *testing.T) {
+	p := &lifecycleProvider{status: providers.StatusSucceeded}
+	result, err := newTestExecutor(p).Preflight(context.Background(), config.Job{Name: "job", Type: "fake"}, config.Environment{})
+	if err != nil {
+		t.Fatalf("Preflight() error = %v", err)
+	}
+	if result.Status != providers.StatusSkipped || result.Details["reason"] != "fake provider does not support dry run" {
+		t.Errorf("result = %s %v, want skipped", result.Status, result.Details)
+	}
+	if len(p.inits) != 0 {
+		t.Errorf("Init called for a provider without preflight")
+	}
+
+	run := NewRunResult("test")
+	run.AddResult(result)
+	if run.Summary.Skipped != 1 || !run.Success() || len(run.FailedResults()) != 0 {
+		t.Errorf("summary = %+v, want one skipped job and success", run.Summary)
+	}
+}
+
+// pooledProvider counts how often the executor hands it a client pool and
+// progress callback.
+type pooledProvider struct {
+	pools     int
+	callbacks int
+}
+
+func (p *pooledProvider) Name() string { return "fake" }
+
+func (p *pooledProvider) SetClientPool(pool *providers.ClientPool) { p.pools++ }
+
+func (p *pooledProvider) SetProgressCallback(cb providers.ProgressCallback) { p.callbacks++ }
+
+func (p *pooledProvider) Execute(ctx context.Context, job config.Job, env config.Environment) (*providers.Result, error) {
+	return &providers.Result{JobName: job.Name, Status: providers.StatusSucceeded}, nil
+}
+
+func TestExecutorConfiguresProviderOnce(t *testing.T) {
+	p := &pooledProvider{}
+	e := newTestExecutor(p)
+	e.SetClientPool(providers.NewClientPool())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Execute(context.Background(), config.Job{Name: "job", Type: "fake"}, config.Environment{})
+		}()
+	}
+	wg.Wait()
+
+	if p.pools != 1 || p.callbacks != 1 {
+		t.Errorf("SetClientPool calls = %d, SetProgressCallback calls = %d, want 1 each", p.pools, p.callbacks)
+	}
+}
@@ -38,7 +38,9 @@ func NewRunResult(version string) *RunResult {
 func (r *RunResult) AddResult(result *providers.Result) {
 	r.Results = append(r.Results, result)
 	r.Summary.Total++
-	if result.Passed() {
+	if result.Status == providers.StatusSkipped {
+		r.Summary.Skipped++
+	} else if result.Passed() {
 		r.Summary.Passed++
 	} else {
 		r.Summary.Failed++
@@ -60,7 +62,7 @@ func (r *RunResult) Success() bool {
 func (r *RunResult) FailedResults() []*providers.Result {
 	var failed []*providers.Result
 	for _, result := range r.Results {
-		if !result.Passed() {
+		if !result.Passed() && result.Status != providers.StatusSkipped {
 			failed = append(failed, result)
 		}
 	}
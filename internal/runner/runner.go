@@ -27,19 +27,40 @@ type ProgressHandler interface {
 type Runner struct {
 	config          *config.Config
 	executor        *Executor
+	clients         *providers.ClientPool
 	progressHandler ProgressHandler
 	version         string
 }
 
 // NewRunner creates a new runner.
 func NewRunner(cfg *config.Config, version string) *Runner {
+	clients := providers.NewClientPool()
+	executor := NewExecutor(providers.DefaultRegistry)
+	executor.SetClientPool(clients)
+
 	return &Runner{
 		config:   cfg,
-		executor: NewExecutor(providers.DefaultRegistry),
+		executor: executor,
+		clients:  clients,
 		version:  version,
 	}
 }
 
+// Close releases resources held by the runner, such as idle connections
+// and provider sessions.
+func (r *Runner) Close() error {
+	err := r.executor.Close()
+	r.clients.Close()
+	return err
+}
+
+// SetTransportWrapper wraps the transport of the HTTP clients providers
+// share, for example to record or replay their traffic. It must be called
+// before Run.
+func (r *Runner) SetTransportWrapper(wrap providers.TransportWrapper) {
+	r.clients.SetTransportWrapper(wrap)
+}
+
 // SetProgressHandler sets the progress handler.
 func (r *Runner) SetProgressHandler(handler ProgressHandler) {
 	r.progressHandler = handler
@@ -65,20 +86,6 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*RunResult, error) {
 			r.progressHandler.OnJobStart(i+1, len(jobs), job)
 		}
 
-		if opts.DryRun {
-			dryRunResult := &providers.Result{
-				JobName:     job.Name,
-				Environment: job.Environment,
-				Type:        job.Type,
-				Status:      providers.StatusSucceeded,
-			}
-			result.AddResult(dryRunResult)
-			if r.progressHandler != nil {
-				r.progressHandler.OnJobComplete(i+1, len(jobs), dryRunResult)
-			}
-			continue
-		}
-
 		env, ok := r.config.Environments[job.Environment]
 		if !ok {
 			jobResult := &providers.Result{
@@ -95,7 +102,12 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) (*RunResult, error) {
 			continue
 		}
 
-		jobResult, err := r.executor.Execute(ctx, job, env)
+		execute := r.executor.Execute
+		if opts.DryRun {
+			execute = r.executor.Preflight
+		}
+
+		jobResult, err := execute(ctx, job.WithDefaults(r.config.Defaults), env)
 		if err != nil {
 			jobResult = &providers.Result{
 				JobName:     job.Name,
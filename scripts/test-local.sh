@@ -23,7 +23,7 @@ make build
 
 echo ""
 echo "=== Building mock API ==="
-go build -o "$PROJECT_ROOT/test/mock-api/mock-api" "$PROJECT_ROOT/test/mock-api/main.go"
+go build -o "$PROJECT_ROOT/test/mock-api/mock-api" ./test/mock-api
 
 echo ""
 echo "=== Starting mock API ==="
@@ -1,3 +0,0 @@
-module mock-api
-
-go 1.23
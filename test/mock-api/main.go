@@ -1,4 +1,5 @@
-// Package main provides a mock API server for testing jprobe.
+// Package main provides a mock API server for testing jprobe. Set MODE to
+// rundeck to serve a simulated Rundeck server instead of the HTTP endpoints.
 package main
 
 import (
@@ -12,8 +13,8 @@ import (
 )
 
 var (
-	healthy     atomic.Bool
-	ready       atomic.Bool
+	healthy      atomic.Bool
+	ready        atomic.Bool
 	requestCount atomic.Int64
 )
 
@@ -28,6 +29,14 @@ func main() {
 		port = "8000"
 	}
 
+	if os.Getenv("MODE") == "rundeck" {
+		log.Printf("Mock Rundeck server starting on port %s", port)
+		if err := http.ListenAndServe(":"+port, loggingMiddleware(rundeckHandler())); err != nil {
+			log.Fatalf("Server failed: %v", err)
+		}
+		return
+	}
+
 	mux := http.NewServeMux()
 
 	// Health check endpoint
@@ -315,8 +324,8 @@ func serviceHealthHandler(serviceName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"service": serviceName,
-			"status":  "healthy",
+			"service":    serviceName,
+			"status":     "healthy",
 			"latency_ms": 5,
 			"connections": map[string]interface{}{
 				"active": 10,
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/user/jobprobe/internal/providers/rundeck"
+	"github.com/user/jobprobe/internal/providers/rundeck/rundecktest"
+)
+
+// rundeckHandler returns a simulated Rundeck server with demo jobs in the
+// "demo" project. RUNDECK_TOKEN sets the API token it requires, and
+// RUNDECK_USER and RUNDECK_PASSWORD a user for session login.
+func rundeckHandler() http.Handler {
+	cfg := rundecktest.Config{Token: os.Getenv("RUNDECK_TOKEN")}
+	if user := os.Getenv("RUNDECK_USER"); user != "" {
+		cfg.Users = map[string]string{user: os.Getenv("RUNDECK_PASSWORD")}
+	}
+	server := rundecktest.New(cfg)
+
+	server.AddJob(rundecktest.Job{
+		ID:              "backup",
+		Name:            "nightly-backup",
+		Group:           "ops",
+		Project:         "demo",
+		Duration:        3 * time.Second,
+		AverageDuration: 3 * time.Second,
+		Nodes:           []string{"db01"},
+		Options: []rundeck.JobOption{
+			{Name: "target", Required: true, Enforced: true, Values: []string{"mysql", "postgres"}},
+		},
+		Log: []rundeck.LogEntry{
+			{Type: "log", Level: "NORMAL", Node: "db01", StepCtx: "1", Log: "starting export"},
+			{Type: "log", Level: "NORMAL", Node: "db01", StepCtx: "2", Log: "1520 rows exported"},
+		},
+	})
+	server.AddJob(rundecktest.Job{
+		ID:          "deploy",
+		Name:        "deploy",
+		Project:     "demo",
+		Duration:    2 * time.Second,
+		Result:      rundeck.ExecutionStatusFailed,
+		Nodes:       []string{"web01", "web02"},
+		FailedNodes: []string{"web02"},
+		Log: []rundeck.LogEntry{
+			{Type: "log", Level: "ERROR", Node: "web02", StepCtx: "1", Log: "connection refused"},
+		},
+	})
+	server.AddJob(rundecktest.Job{
+		ID:      "stuck",
+		Name:    "stuck",
+		Project: "demo",
+		Hold:    true,
+	})
+
+	return server.Handler()
+}